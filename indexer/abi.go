@@ -0,0 +1,189 @@
+package indexer
+
+import (
+	"github.com/aergoio/aergo-indexer-2.0/indexer/client"
+	"github.com/aergoio/aergo-indexer-2.0/indexer/db"
+	doc "github.com/aergoio/aergo-indexer-2.0/indexer/documents"
+	"github.com/aergoio/aergo-indexer-2.0/indexer/transaction"
+	"github.com/aergoio/aergo-indexer-2.0/types"
+	"github.com/mr-tron/base58"
+)
+
+// MinerContractAbi stores the abi of a deployed or redeployed contract, versioned by the tx
+func (ns *Indexer) MinerContractAbi(txDoc *doc.EsTx, contractAddress []byte, MinerGRPC *client.AergoClientController) *doc.EsContractAbi {
+	abi, err := MinerGRPC.GetABI(contractAddress)
+	if err != nil {
+		ns.log.Warn().Err(err).Str("tx", txDoc.Id).Str("contract", transaction.EncodeAccount(contractAddress)).Msg("Failed to get abi")
+		return nil
+	}
+	contract := transaction.EncodeAccount(contractAddress)
+	abiDoc := doc.ConvContractAbi(txDoc, contract, abi)
+	ns.addContractAbi(abiDoc)
+	ns.cache.storeContractAbi(contract, abiDoc)
+	return abiDoc
+}
+
+// MinerContractVersion records a version of contract. on redeploy, the contract points at the new version
+// and its verification is reset if the code is changed
+func (ns *Indexer) MinerContractVersion(txDoc *doc.EsTx, contractAddress []byte, abiDoc *doc.EsContractAbi) {
+	contract := transaction.EncodeAccount(contractAddress)
+	versionDoc := doc.ConvContractVersion(txDoc, contract, abiDoc)
+	ns.addContractVersion(versionDoc)
+	if txDoc.Category != transaction.TxRedeploy {
+		return
+	}
+
+	// the contract doc is added by its deploy tx. if not yet ( bulk ), the deploy applies the latest version
+	contractDoc, err := ns.getContract(contract)
+	if err != nil || contractDoc == nil || contractDoc.VersionBlockNo > versionDoc.BlockNo {
+		return
+	}
+	ns.updateContractVersion(doc.ConvContractUpVersion(versionDoc))
+	if contractDoc.CodeHash != versionDoc.CodeHash && contractDoc.VerifiedStatus != string(NotVerified) {
+		ns.updateContract(doc.ConvContractUp(contract, string(NotVerified), contractDoc.VerifiedToken, "", "", "", ""))
+		ns.log.Info().Str("contract", contract).Str("tx", txDoc.Id).Msg("contract code changed, verification reset")
+	}
+}
+
+// rollbackContractVersion deletes contract versions in range, and points the contracts redeployed in range at their latest version left.
+// verification is reset if the code is changed, so that the verified contract is verified again with the code of the version
+func (ns *Indexer) rollbackContractVersion(fromBlockHeight uint64, toBlockHeight uint64) {
+	contracts := make(map[string]bool)
+	if err := ns.ScrollContractVersionInRange(fromBlockHeight, toBlockHeight, func(versionDoc *doc.EsContractVersion) {
+		contracts[versionDoc.Contract] = true
+	}); err != nil {
+		ns.log.Warn().Err(err).Msg("Failed to scroll contract version")
+	}
+	ns.deleteTypeByQuery("contract_version", db.IntegerRangeQuery{Field: "blockno", Min: fromBlockHeight, Max: toBlockHeight})
+	if err := ns.db.Refresh(ns.indexNamePrefix + "contract_version"); err != nil {
+		ns.log.Warn().Err(err).Msg("Failed to refresh indices")
+	}
+
+	for contract := range contracts {
+		contractDoc, err := ns.getContract(contract)
+		if err != nil || contractDoc == nil || contractDoc.BlockNo >= fromBlockHeight || contractDoc.VersionBlockNo < fromBlockHeight {
+			continue
+		}
+		versionDoc, err := ns.getLastContractVersion(contract)
+		if err != nil {
+			continue
+		}
+		if versionDoc == nil {
+			// deployed before versions are indexed, so the deploy is the version
+			if versionDoc = ns.deployVersionOf(contractDoc); versionDoc == nil {
+				continue
+			}
+		}
+		ns.updateContractVersion(doc.ConvContractUpVersion(versionDoc))
+		if contractDoc.CodeHash != versionDoc.CodeHash && (contractDoc.VerifiedStatus != string(NotVerified) || contractDoc.CodeUrl != "") {
+			ns.updateContract(doc.ConvContractUp(contract, string(NotVerified), contractDoc.VerifiedToken, "", "", "", ""))
+		}
+		ns.log.Info().Str("contract", contract).Str("tx", versionDoc.TxId).Msg("contract version restored")
+	}
+}
+
+// deployVersionOf returns the version of the deploy of contract with the payload from node. nil if failed to get
+func (ns *Indexer) deployVersionOf(contractDoc *doc.EsContract) *doc.EsContractVersion {
+	txHash, err := base58.Decode(contractDoc.TxId)
+	if err != nil {
+		return nil
+	}
+	tx, err := ns.grpcClient.GetTx(txHash)
+	if err != nil {
+		ns.log.Warn().Err(err).Str("tx", contractDoc.TxId).Msg("Failed to get deploy tx")
+		return nil
+	}
+	txDoc := &doc.EsTx{
+		BaseEsType: &doc.BaseEsType{Id: contractDoc.TxId},
+		BlockNo:    contractDoc.BlockNo,
+		Timestamp:  contractDoc.Timestamp,
+		Account:    contractDoc.Creator,
+		Category:   transaction.TxDeploy,
+		Payload:    string(tx.GetBody().GetPayload()),
+	}
+	return doc.ConvContractVersion(txDoc, contractDoc.Id, nil)
+}
+
+// MinerTokenStandard detects the token standard of contract by its abi, and probes the view functions of the standard
+func (ns *Indexer) MinerTokenStandard(abiDoc *doc.EsContractAbi, contractAddress []byte, MinerGRPC *client.AergoClientController) *transaction.TokenDetection {
+	if abiDoc == nil {
+		return nil
+	}
+	detection := transaction.DetectTokenStandard(abiDoc.FunctionViews())
+	if detection.Type == transaction.TokenNone {
+		return detection
+	}
+
+	passed := 0
+	account := transaction.EncodeAccount(contractAddress)
+	for _, name := range detection.Probes {
+		if ret, err := MinerGRPC.QueryView(contractAddress, name, transaction.ProbeArgs(name, account)...); err == nil && ret != "null" {
+			passed++
+		}
+	}
+	detection.ApplyProbe(passed)
+	ns.log.Debug().Str("contract", transaction.EncodeAccount(contractAddress)).Str("type", string(detection.Type)).Float64("confidence", detection.Confidence).Strs("interfaces", detection.Interfaces).Msg("token standard detected")
+	return detection
+}
+
+// applyLatestVersion points a new contract doc at its latest version, if redeployed later
+func (ns *Indexer) applyLatestVersion(contractDoc *doc.EsContract) {
+	versionDoc, err := ns.getLastContractVersion(contractDoc.Id)
+	if err != nil || versionDoc == nil || versionDoc.BlockNo <= contractDoc.VersionBlockNo {
+		return
+	}
+	contractDoc.Payload = versionDoc.Payload
+	contractDoc.VersionTx = versionDoc.TxId
+	contractDoc.VersionBlockNo = versionDoc.BlockNo
+	contractDoc.PayloadHash = versionDoc.PayloadHash
+	contractDoc.CodeHash = versionDoc.CodeHash
+}
+
+// getContractAbi returns the abi of contract in effect at the block from cache, database or node. nil if not exist or failed to get
+// the latest abi is cached, and an abi replaced by a redeploy after the block is read from database
+func (ns *Indexer) getContractAbi(contract string, blockNo uint64, MinerGRPC *client.AergoClientController) *doc.EsContractAbi {
+	latest, exist := ns.cache.getContractAbi(contract)
+	if exist != true {
+		var err error
+		if latest, err = ns.getLastContractAbi(contract); err != nil {
+			return nil
+		}
+		if latest == nil {
+			// contract deployed before abi indexing, use the abi of node. failures are not cached, so that it is asked again
+			abi, err := MinerGRPC.GetABI(transaction.DecodeAccount(contract))
+			if err != nil {
+				ns.log.Warn().Err(err).Str("contract", contract).Msg("Failed to get abi")
+				return nil
+			}
+			latest = doc.ConvContractAbi(&doc.EsTx{BaseEsType: &doc.BaseEsType{}}, contract, abi)
+		}
+		ns.cache.storeContractAbi(contract, latest)
+	}
+	if latest.BlockNo <= blockNo {
+		return latest
+	}
+
+	abiDoc, err := ns.getContractAbiAt(contract, blockNo)
+	if err != nil {
+		return nil
+	}
+	return abiDoc
+}
+
+// MinerTxArgs decodes call arguments of a tx, named by the abi of the contract at the block if known
+func (ns *Indexer) MinerTxArgs(txDoc *doc.EsTx, tx *types.Tx, MinerGRPC *client.AergoClientController) []transaction.Arg {
+	payload, err := transaction.UnmarshalPayloadArgs(tx.GetBody().GetPayload())
+	if err != nil || len(payload.Args) == 0 {
+		return nil
+	}
+
+	var names []string
+	if txDoc.Category == transaction.TxCall {
+		if abiDoc := ns.getContractAbi(txDoc.RecipientAddr, txDoc.BlockNo, MinerGRPC); abiDoc != nil {
+			if fn := abiDoc.GetFunction(payload.Name); fn != nil {
+				names = fn.Arguments
+			}
+		}
+	}
+	return transaction.DecodeArgs(payload.Args, names)
+}
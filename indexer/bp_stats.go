@@ -0,0 +1,153 @@
+package indexer
+
+import (
+	doc "github.com/aergoio/aergo-indexer-2.0/indexer/documents"
+	"github.com/aergoio/aergo-indexer-2.0/indexer/transaction"
+)
+
+// MinerBpStats applies the block to the stats of its producer, and the slots missed since the previous block to the stats of scheduled bps ( sync only )
+func (ns *Indexer) MinerBpStats(blockDoc *doc.EsBlock) {
+	changed := make(map[string]*doc.EsBpStats)
+	if blockDoc.BlockProducer != "" {
+		if stats := ns.loadBpStats(blockDoc.BlockProducer); stats != nil {
+			stats.Apply(blockDoc)
+			changed[stats.Id] = stats
+		}
+	}
+
+	prevBlock, exist := ns.cache.getPrevBlock()
+	if exist != true || prevBlock.BlockNo+1 != blockDoc.BlockNo {
+		prevBlock, _ = ns.getBlockOf("", blockDoc.BlockNo)
+	}
+	if prevBlock != nil {
+		for bp, slots := range ns.missedSlots(prevBlock, blockDoc) {
+			if stats := ns.loadBpStats(bp); stats != nil {
+				stats.AddMissed(int64(slots))
+				changed[stats.Id] = stats
+			}
+		}
+	}
+	ns.cache.storePrevBlock(blockDoc)
+
+	for _, stats := range changed {
+		ns.addBpStats(stats)
+	}
+}
+
+// loadBpStats returns the stats of bp, which starts from the bp stats doc. returns nil if the doc can not be read
+func (ns *Indexer) loadBpStats(bp string) *doc.EsBpStats {
+	if stats, exist := ns.cache.getBpStats(bp); exist == true {
+		return stats
+	}
+	stats, err := ns.getBpStats(bp)
+	if err != nil {
+		return nil
+	}
+	if stats == nil {
+		stats = doc.NewBpStats(bp)
+	}
+	ns.cache.storeBpStats(stats)
+	return stats
+}
+
+// missedSlots returns the slots missed between two consecutive blocks by the bp schedule in effect at the previous block. no slot is missed if the schedule is unknown
+func (ns *Indexer) missedSlots(prevBlock *doc.EsBlock, blockDoc *doc.EsBlock) map[string]uint64 {
+	schedule := ns.cache.getBpScheduleAt(prevBlock.BlockNo)
+	return transaction.MissedSlots(schedule, prevBlock.Timestamp.UnixNano(), blockDoc.Timestamp.UnixNano(), ns.blockInterval)
+}
+
+// rollbackBpStats reverts the blocks in the block range, which are going to be deleted, from the stats of bps
+func (ns *Indexer) rollbackBpStats(fromBlockHeight uint64, toBlockHeight uint64) {
+	changed := make(map[string]*doc.EsBpStats)
+	load := func(bp string) *doc.EsBpStats {
+		stats := ns.loadBpStats(bp)
+		if stats != nil {
+			changed[bp] = stats
+		}
+		return stats
+	}
+
+	prevBlock, _ := ns.getBlockOf("", fromBlockHeight)
+	if err := ns.ScrollBlocksInRange(fromBlockHeight, toBlockHeight, func(blockDoc *doc.EsBlock) {
+		if blockDoc.BlockProducer != "" {
+			if stats := load(blockDoc.BlockProducer); stats != nil {
+				stats.Revert(blockDoc)
+			}
+		}
+		if prevBlock != nil {
+			for bp, slots := range ns.missedSlots(prevBlock, blockDoc) {
+				if stats := load(bp); stats != nil {
+					stats.AddMissed(-int64(slots))
+				}
+			}
+		}
+		prevBlock = blockDoc
+	}); err != nil {
+		ns.log.Error().Err(err).Str("func", "rollbackBpStats").Msg("error while scroll block")
+	}
+
+	for bp, stats := range changed {
+		lastBlock, err := ns.getBlockOf(bp, fromBlockHeight)
+		if err != nil {
+			continue
+		}
+		if stats.Blocks == 0 {
+			lastBlock = nil
+		}
+		stats.SetLast(lastBlock)
+		ns.addBpStats(stats)
+	}
+	ns.cache.resetPrevBlock()
+}
+
+// applyBpStatsInRange applies the blocks in the block range, and the slots missed next to them, to the stats of bps. they are reverted if revert is true
+// blocks indexed in bulk are applied after bulk sync, and blocks going to be indexed again are reverted before
+func (ns *Indexer) applyBpStatsInRange(fromBlockHeight uint64, toBlockHeight uint64, revert bool) {
+	changed := make(map[string]*doc.EsBpStats)
+	load := func(bp string) *doc.EsBpStats {
+		stats := ns.loadBpStats(bp)
+		if stats != nil {
+			changed[bp] = stats
+		}
+		return stats
+	}
+	sign := int64(1)
+	if revert == true {
+		sign = -1
+	}
+
+	from := fromBlockHeight
+	if from > 0 {
+		from--
+	}
+	var prevBlock *doc.EsBlock
+	blocks := 0
+	if err := ns.ScrollBlocksInRange(from, toBlockHeight+1, func(blockDoc *doc.EsBlock) {
+		if blockDoc.BlockNo >= fromBlockHeight && blockDoc.BlockNo <= toBlockHeight && blockDoc.BlockProducer != "" {
+			if stats := load(blockDoc.BlockProducer); stats != nil {
+				if revert == true {
+					stats.Revert(blockDoc)
+				} else {
+					stats.Apply(blockDoc)
+				}
+				blocks++
+			}
+		}
+		// every consecutive pair has a block in the range
+		if prevBlock != nil && prevBlock.BlockNo+1 == blockDoc.BlockNo {
+			for bp, slots := range ns.missedSlots(prevBlock, blockDoc) {
+				if stats := load(bp); stats != nil {
+					stats.AddMissed(sign * int64(slots))
+				}
+			}
+		}
+		prevBlock = blockDoc
+	}); err != nil {
+		ns.log.Error().Err(err).Str("func", "applyBpStatsInRange").Msg("error while scroll block")
+	}
+
+	for _, stats := range changed {
+		ns.addBpStats(stats)
+	}
+	ns.log.Info().Uint64("from", fromBlockHeight).Uint64("to", toBlockHeight).Bool("revert", revert).Int("blocks", blocks).Int("bps", len(changed)).Msg("apply bp stats")
+}
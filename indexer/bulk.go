@@ -52,6 +52,11 @@ func (b *Bulk) StartBulkChannel() {
 	b.BChannel.Contract = make(chan ChanInfo)
 	b.BChannel.TokenTransfer = make(chan ChanInfo)
 	b.BChannel.AccTokens = make(chan ChanInfo)
+	b.BChannel.NftHistory = make(chan ChanInfo)
+	b.BChannel.EventIndex = make(map[string]chan ChanInfo)
+	for _, spec := range b.idxer.eventIndices {
+		b.BChannel.EventIndex[spec.Index] = make(chan ChanInfo)
+	}
 	b.SynDone = make(chan bool)
 
 	// Start bulk indexers for each indices
@@ -61,6 +66,10 @@ func (b *Bulk) StartBulkChannel() {
 	go b.BulkIndexer(b.BChannel.Contract, b.idxer.indexNamePrefix+"contract", b.bulkSize, b.batchTime, false)
 	go b.BulkIndexer(b.BChannel.TokenTransfer, b.idxer.indexNamePrefix+"token_transfer", b.bulkSize, b.batchTime, false)
 	go b.BulkIndexer(b.BChannel.AccTokens, b.idxer.indexNamePrefix+"account_tokens", b.bulkSize, b.batchTime, false)
+	go b.BulkIndexer(b.BChannel.NftHistory, b.idxer.indexNamePrefix+"nft_history", b.bulkSize, b.batchTime, false)
+	for typeName, docChannel := range b.BChannel.EventIndex {
+		go b.BulkIndexer(docChannel, b.idxer.indexNamePrefix+typeName, b.bulkSize, b.batchTime, false)
+	}
 
 	// Start multiple miners
 	GrpcClients := make([]*client.AergoClientController, b.grpcNum)
@@ -95,24 +104,36 @@ func (b *Bulk) StopBulkChannel() {
 
 	// Send stop messages to each bulk channels
 	b.BChannel.Block <- ChanInfo{ChanType_StopBulk, nil}
-	b.BChannel.Tx <- ChanInfo{ChanType_StopBulk, nil}
-	b.BChannel.Event <- ChanInfo{ChanType_StopBulk, nil}
-	b.BChannel.Contract <- ChanInfo{ChanType_StopBulk, nil}
-	b.BChannel.TokenTransfer <- ChanInfo{ChanType_StopBulk, nil}
-	b.BChannel.AccTokens <- ChanInfo{ChanType_StopBulk, nil}
+	for _, docChannel := range b.docChannels() {
+		docChannel <- ChanInfo{ChanType_StopBulk, nil}
+	}
 
 	// Close bulk channels
 	close(b.BChannel.Block)
-	close(b.BChannel.Tx)
-	close(b.BChannel.Event)
-	close(b.BChannel.Contract)
-	close(b.BChannel.TokenTransfer)
-	close(b.BChannel.AccTokens)
+	for _, docChannel := range b.docChannels() {
+		close(docChannel)
+	}
 	close(b.SynDone)
 
 	b.idxer.log.Info().Msg("Stop Bulk Indexer")
 }
 
+// docChannels returns the bulk channels other than block, which are committed with block channel
+func (b *Bulk) docChannels() []chan ChanInfo {
+	docChannels := []chan ChanInfo{
+		b.BChannel.Tx,
+		b.BChannel.Event,
+		b.BChannel.Contract,
+		b.BChannel.TokenTransfer,
+		b.BChannel.AccTokens,
+		b.BChannel.NftHistory,
+	}
+	for _, docChannel := range b.BChannel.EventIndex {
+		docChannels = append(docChannels, docChannel)
+	}
+	return docChannels
+}
+
 func (b *Bulk) BulkIndexer(docChannel chan ChanInfo, indexName string, bulkSize int32, batchTime time.Duration, isBlock bool) {
 	bulk := b.idxer.db.InsertBulk(indexName)
 	total := int32(0)
@@ -148,13 +169,12 @@ func (b *Bulk) BulkIndexer(docChannel chan ChanInfo, indexName string, bulkSize
 
 		// Block Channel : wait other channels
 		if isBlock {
-			b.BChannel.Tx <- ChanInfo{ChanType_Commit, nil}
-			b.BChannel.Event <- ChanInfo{ChanType_Commit, nil}
-			b.BChannel.Contract <- ChanInfo{ChanType_Commit, nil}
-			b.BChannel.TokenTransfer <- ChanInfo{ChanType_Commit, nil}
-			b.BChannel.AccTokens <- ChanInfo{ChanType_Commit, nil}
+			docChannels := b.docChannels()
+			for _, docChannel := range docChannels {
+				docChannel <- ChanInfo{ChanType_Commit, nil}
+			}
 
-			for i := 0; i < 5; i++ {
+			for i := 0; i < len(docChannels); i++ {
 				<-b.SynDone
 			}
 		}
@@ -2,6 +2,7 @@ package indexer
 
 import (
 	"sync"
+	"sync/atomic"
 
 	"github.com/aergoio/aergo-indexer-2.0/indexer/client"
 	doc "github.com/aergoio/aergo-indexer-2.0/indexer/documents"
@@ -14,13 +15,36 @@ type Cache struct {
 	accToken     sync.Map
 	peerId       sync.Map
 	addrsBalance sync.Map
+	bpSet        sync.Map
+	nameState    sync.Map
+	raftMembers  sync.Map
+	contractAbi  sync.Map
+	tokenHolders sync.Map
+	decimals     sync.Map
+	stats        sync.Map
+	firstSeen    sync.Map
+	holders      sync.Map // tokens which balances are indexed in bulk
+	confs        sync.Map // enterprise config keys changed in bulk
+	bpStats      sync.Map
+	prevBlock    sync.Map
+	nameLock     sync.Mutex
+	chainParams  map[string]doc.ChainParamHistory
+	paramLock    sync.RWMutex
+	bpSchedules  doc.BpScheduleHistory
+	scheduleLock sync.RWMutex
+	statsAcc     map[string]*statsAcc
+	statsLock    sync.Mutex
+	firstSeenCnt int64
+	lastReward   *rewardState // state of the last synced block, accessed by the sync miner only
 	// addrsVerifiedToken    sync.Map
 	// addrsVerifiedContract sync.Map
 }
 
 func NewCache(idxer *Indexer) *Cache {
 	cache := &Cache{
-		idxer: idxer,
+		idxer:       idxer,
+		chainParams: make(map[string]doc.ChainParamHistory),
+		statsAcc:    make(map[string]*statsAcc),
 	}
 
 	for _, tokenAddr := range idxer.tokenVerifyWhitelist {
@@ -103,6 +127,9 @@ func (ns *Cache) refreshVariables(info BlockInfo, blockDoc *doc.EsBlock, minerGR
 	for addr := range mapBalance {
 		ns.addrsBalance.Delete(addr)
 	}
+
+	// recount token holders which may be inaccurate
+	ns.idxer.recountInBackground(ns.idxer.recountStaleTokenHolders)
 }
 
 func (c *Cache) getPeerId(pubKey []byte) string {
@@ -127,3 +154,286 @@ func (c *Cache) getAccTokens(id string) (exist bool) {
 func (c *Cache) storeBalance(id string) {
 	c.addrsBalance.Store(id, true)
 }
+
+func (c *Cache) getBps() (bps []string, exist bool) {
+	if v, exist := c.bpSet.Load("active"); exist == true {
+		return v.([]string), true
+	}
+	return nil, false
+}
+
+func (c *Cache) storeBps(bps []string) {
+	c.bpSet.Store("active", bps)
+}
+
+func (c *Cache) resetBps() {
+	c.bpSet.Delete("active")
+}
+
+// getBpScheduleAt returns the peer ids of bps in the order of slots at the block. nil if unknown
+func (c *Cache) getBpScheduleAt(blockNo uint64) []string {
+	c.scheduleLock.RLock()
+	defer c.scheduleLock.RUnlock()
+	return c.bpSchedules.ScheduleAt(blockNo)
+}
+
+// getLastBpChange returns the latest change of bp set, or nil if unknown
+func (c *Cache) getLastBpChange() *doc.EsBpChange {
+	c.scheduleLock.RLock()
+	defer c.scheduleLock.RUnlock()
+	return c.bpSchedules.Latest()
+}
+
+func (c *Cache) applyBpChange(change *doc.EsBpChange) {
+	c.scheduleLock.Lock()
+	defer c.scheduleLock.Unlock()
+	c.bpSchedules = c.bpSchedules.Apply(change)
+}
+
+func (c *Cache) rollbackBpSchedules(fromBlockNo uint64) {
+	c.scheduleLock.Lock()
+	defer c.scheduleLock.Unlock()
+	c.bpSchedules = c.bpSchedules.Rollback(fromBlockNo)
+}
+
+func (c *Cache) getNameState(name string) (nameState *doc.EsNameState, exist bool) {
+	if v, exist := c.nameState.Load(name); exist == true {
+		return v.(*doc.EsNameState), true
+	}
+	return nil, false
+}
+
+func (c *Cache) storeNameState(nameState *doc.EsNameState) {
+	c.nameState.Store(nameState.Name, nameState)
+}
+
+func (c *Cache) deleteNameState(name string) {
+	c.nameState.Delete(name)
+}
+
+func (c *Cache) getRaftMembers() (members []transaction.ConsensusBp, leader string, exist bool) {
+	v, exist := c.raftMembers.Load("members")
+	if exist != true {
+		return nil, "", false
+	}
+	if l, ok := c.raftMembers.Load("leader"); ok == true {
+		leader = l.(string)
+	}
+	return v.([]transaction.ConsensusBp), leader, true
+}
+
+func (c *Cache) storeRaftMembers(members []transaction.ConsensusBp, leader string) {
+	c.raftMembers.Store("members", members)
+	c.raftMembers.Store("leader", leader)
+}
+
+func (c *Cache) resetRaftMembers() {
+	c.raftMembers.Delete("members")
+	c.raftMembers.Delete("leader")
+}
+
+func (c *Cache) getContractAbi(contract string) (abiDoc *doc.EsContractAbi, exist bool) {
+	if v, exist := c.contractAbi.Load(contract); exist == true {
+		return v.(*doc.EsContractAbi), true
+	}
+	return nil, false
+}
+
+// storeContractAbi keeps the latest abi of contract
+func (c *Cache) storeContractAbi(contract string, abiDoc *doc.EsContractAbi) {
+	if prev, exist := c.getContractAbi(contract); exist == true && prev.BlockNo > abiDoc.BlockNo {
+		return
+	}
+	c.contractAbi.Store(contract, abiDoc)
+}
+
+func (c *Cache) resetContractAbi() {
+	c.contractAbi.Range(func(k, v interface{}) bool {
+		c.contractAbi.Delete(k)
+		return true
+	})
+}
+
+// tokenHolders is the holder tracker of a token with the supply to compute shares. it is locked since recounts run in background
+type tokenHolders struct {
+	sync.Mutex
+	*doc.TokenHolders
+	supply string
+}
+
+func (c *Cache) getTokenHolders(tokenAddr string) (holders *tokenHolders, exist bool) {
+	if v, exist := c.tokenHolders.Load(tokenAddr); exist == true {
+		return v.(*tokenHolders), true
+	}
+	return nil, false
+}
+
+func (c *Cache) storeTokenHolders(tokenAddr string, holders *tokenHolders) {
+	c.tokenHolders.Store(tokenAddr, holders)
+}
+
+// loadOrStoreTokenHolders returns the holders of token if exist, otherwise stores the given one
+func (c *Cache) loadOrStoreTokenHolders(tokenAddr string, holders *tokenHolders) *tokenHolders {
+	v, _ := c.tokenHolders.LoadOrStore(tokenAddr, holders)
+	return v.(*tokenHolders)
+}
+
+// storeTokenSupply keeps the supply of a token which holders are tracked
+func (c *Cache) storeTokenSupply(tokenAddr string, supply string) {
+	if holders, exist := c.getTokenHolders(tokenAddr); exist == true {
+		holders.Lock()
+		holders.supply = supply
+		holders.Unlock()
+	}
+}
+
+func (c *Cache) resetTokenHolders() {
+	c.tokenHolders.Range(func(k, v interface{}) bool {
+		c.tokenHolders.Delete(k)
+		return true
+	})
+}
+
+func (c *Cache) rangeTokenHolders(fn func(tokenAddr string, holders *tokenHolders)) {
+	c.tokenHolders.Range(func(k, v interface{}) bool {
+		fn(k.(string), v.(*tokenHolders))
+		return true
+	})
+}
+
+// markStats keeps the bucket marked first, so that a rollup updated every block is rebuilt as well
+func (c *Cache) markStats(bucket *statsBucket) {
+	c.stats.LoadOrStore(bucket.id(), bucket)
+}
+
+func (c *Cache) unmarkStats(bucket *statsBucket) {
+	c.stats.Delete(bucket.id())
+}
+
+func (c *Cache) rangeStats(fn func(bucket *statsBucket)) {
+	c.stats.Range(func(k, v interface{}) bool {
+		fn(v.(*statsBucket))
+		return true
+	})
+}
+
+func (c *Cache) markHolders(tokenAddr string) {
+	c.holders.Store(tokenAddr, true)
+}
+
+// takeMarkedHolders returns the marked tokens and unmarks them
+func (c *Cache) takeMarkedHolders() []string {
+	return takeMarks(&c.holders)
+}
+
+func (c *Cache) markEnterpriseConf(key string) {
+	c.confs.Store(key, true)
+}
+
+// takeMarkedEnterpriseConfs returns the marked config keys and unmarks them
+func (c *Cache) takeMarkedEnterpriseConfs() []string {
+	return takeMarks(&c.confs)
+}
+
+func takeMarks(marks *sync.Map) (keys []string) {
+	marks.Range(func(k, v interface{}) bool {
+		keys = append(keys, k.(string))
+		marks.Delete(k)
+		return true
+	})
+	return keys
+}
+
+func (c *Cache) getFirstSeen(account string) (blockNo uint64, exist bool) {
+	if v, exist := c.firstSeen.Load(account); exist == true {
+		return v.(uint64), true
+	}
+	return 0, false
+}
+
+// storeFirstSeen caches the first block of account. the cache is cleared when it is full, since active accounts are cached again soon
+func (c *Cache) storeFirstSeen(account string, blockNo uint64) {
+	if atomic.AddInt64(&c.firstSeenCnt, 1) > firstSeenLimit {
+		c.resetFirstSeen()
+		atomic.AddInt64(&c.firstSeenCnt, 1)
+	}
+	c.firstSeen.Store(account, blockNo)
+}
+
+func (c *Cache) resetFirstSeen() {
+	atomic.StoreInt64(&c.firstSeenCnt, 0)
+	c.firstSeen.Range(func(k, v interface{}) bool {
+		c.firstSeen.Delete(k)
+		return true
+	})
+}
+
+// getChainParam returns the value of a chain parameter at the block
+func (c *Cache) getChainParam(name string, blockNo uint64) (value string, exist bool) {
+	c.paramLock.RLock()
+	defer c.paramLock.RUnlock()
+	return c.chainParams[name].ValueAt(blockNo)
+}
+
+// applyChainParam adds the change of a chain parameter. returns false if the value is not changed
+func (c *Cache) applyChainParam(param *doc.EsChainParam) (applied bool) {
+	c.paramLock.Lock()
+	defer c.paramLock.Unlock()
+	c.chainParams[param.Name], applied = c.chainParams[param.Name].Apply(param)
+	return applied
+}
+
+func (c *Cache) rollbackChainParams(fromBlockNo uint64) {
+	c.paramLock.Lock()
+	defer c.paramLock.Unlock()
+	for name, history := range c.chainParams {
+		c.chainParams[name] = history.Rollback(fromBlockNo)
+	}
+}
+
+func (c *Cache) getBpStats(bp string) (bpStats *doc.EsBpStats, exist bool) {
+	if v, exist := c.bpStats.Load(bp); exist == true {
+		return v.(*doc.EsBpStats), true
+	}
+	return nil, false
+}
+
+func (c *Cache) storeBpStats(bpStats *doc.EsBpStats) {
+	c.bpStats.Store(bpStats.Id, bpStats)
+}
+
+// getPrevBlock returns the last block applied to bp stats
+func (c *Cache) getPrevBlock() (blockDoc *doc.EsBlock, exist bool) {
+	if v, exist := c.prevBlock.Load("last"); exist == true {
+		return v.(*doc.EsBlock), true
+	}
+	return nil, false
+}
+
+func (c *Cache) storePrevBlock(blockDoc *doc.EsBlock) {
+	c.prevBlock.Store("last", blockDoc)
+}
+
+func (c *Cache) resetBpStats() {
+	c.bpStats.Range(func(k, v interface{}) bool {
+		c.bpStats.Delete(k)
+		return true
+	})
+	c.resetPrevBlock()
+}
+
+func (c *Cache) resetPrevBlock() {
+	c.prevBlock.Delete("last")
+}
+
+// getTokenDecimals returns the decimals of a token by raw contract address. decimals of a token never change
+func (c *Cache) getTokenDecimals(contractAddr string) (decimals uint8, exist bool) {
+	if v, exist := c.decimals.Load(contractAddr); exist == true {
+		return v.(uint8), true
+	}
+	return 0, false
+}
+
+func (c *Cache) storeTokenDecimals(contractAddr string, decimals uint8) {
+	c.decimals.Store(contractAddr, decimals)
+}
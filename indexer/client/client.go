@@ -69,6 +69,14 @@ func (t *AergoClientController) GetReceipt(txHash []byte) (*types.Receipt, error
 	return receipt, nil
 }
 
+func (t *AergoClientController) GetTx(txHash []byte) (*types.Tx, error) {
+	tx, err := t.client.GetTX(context.Background(), &types.SingleBytes{Value: txHash})
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
 func (t *AergoClientController) ListBlockStream() (types.AergoRPCService_ListBlockStreamClient, error) {
 	stream, err := t.client.ListBlockStream(context.Background(), &types.Empty{})
 	if err != nil {
@@ -77,6 +85,62 @@ func (t *AergoClientController) ListBlockStream() (types.AergoRPCService_ListBlo
 	return stream, nil
 }
 
+func (t *AergoClientController) GetConsensusInfo() (*types.ConsensusInfo, error) {
+	consensusInfo, err := t.client.GetConsensusInfo(context.Background(), &types.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	return consensusInfo, nil
+}
+
+func (t *AergoClientController) GetVotes(voteId string, count uint32) (*types.VoteList, error) {
+	voteList, err := t.client.GetVotes(context.Background(), &types.VoteParams{Id: voteId, Count: count})
+	if err != nil {
+		return nil, err
+	}
+	return voteList, nil
+}
+
+func (t *AergoClientController) GetAccountVotes(address []byte) (*types.AccountVoteInfo, error) {
+	voteInfo, err := t.client.GetAccountVotes(context.Background(), &types.AccountAddress{Value: address})
+	if err != nil {
+		return nil, err
+	}
+	return voteInfo, nil
+}
+
+func (t *AergoClientController) GetNameInfo(name string, blockNo uint64) (*types.NameInfo, error) {
+	nameInfo, err := t.client.GetNameInfo(context.Background(), &types.Name{Name: name, BlockNo: blockNo})
+	if err != nil {
+		return nil, err
+	}
+	return nameInfo, nil
+}
+
+func (t *AergoClientController) GetEnterpriseConfig(key string) (*types.EnterpriseConfig, error) {
+	conf, err := t.client.GetEnterpriseConfig(context.Background(), &types.EnterpriseConfigKey{Key: key})
+	if err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
+func (t *AergoClientController) GetConfChangeProgress(txHash []byte) (*types.ConfChangeProgress, error) {
+	progress, err := t.client.GetConfChangeProgress(context.Background(), &types.SingleBytes{Value: txHash})
+	if err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
+
+func (t *AergoClientController) GetABI(contractAddress []byte) (*types.ABI, error) {
+	abi, err := t.client.GetABI(context.Background(), &types.SingleBytes{Value: contractAddress})
+	if err != nil {
+		return nil, err
+	}
+	return abi, nil
+}
+
 func (t *AergoClientController) BalanceOf(address []byte) (balance string, balanceFloat float32, staking string, stakingFloat float32) {
 	// get unstake balance
 	unstakingInfo, err := t.client.GetState(context.Background(), &types.SingleBytes{Value: address})
@@ -113,13 +177,17 @@ func (t *AergoClientController) BalanceOf(address []byte) (balance string, balan
 	return balance, balanceFloat, staking, stakingFloat
 }
 
-func (t *AergoClientController) QueryBalanceOf(contractAddress []byte, account string, isCccvNft bool) (balance string, balanceFloat float32) {
-	var err error
-	if isCccvNft == true {
-		balance, err = t.queryContract(contractAddress, "query", "balanceOf", account)
-	} else {
-		balance, err = t.queryContract(contractAddress, "balanceOf", account)
+// GetBalanceAt returns the balance of address in the state of root, which is the blocks root hash of a block
+func (t *AergoClientController) GetBalanceAt(address []byte, root []byte) (*big.Int, error) {
+	proof, err := t.client.GetStateAndProof(context.Background(), &types.AccountAndRoot{Account: address, Root: root, Compressed: true})
+	if err != nil {
+		return nil, err
 	}
+	return new(big.Int).SetBytes(proof.GetState().GetBalance()), nil
+}
+
+func (t *AergoClientController) QueryBalanceOf(contractAddress []byte, account string, profile *ContractProfile) (balance string, balanceFloat float32) {
+	balance, err := t.queryMethod(contractAddress, profile, MethodBalanceOf, account)
 	if err != nil {
 		return "0", 0
 	}
@@ -141,16 +209,9 @@ func (t *AergoClientController) QueryMetadataOf(contractAddress []byte, tokenAdd
 	return req
 }
 
-func (t *AergoClientController) QueryOwnerOf(contractAddress []byte, amountOrId string, isCccvNft bool) (tokenType tx.TokenType, tokenId string, amount string, amountFloat float32) {
-	var err error
-	var owner string
-
+func (t *AergoClientController) QueryOwnerOf(contractAddress []byte, amountOrId string, profile *ContractProfile) (tokenType tx.TokenType, tokenId string, amount string, amountFloat float32) {
 	// 2022/06/05 숫자인 token ID 허용
-	if isCccvNft == true {
-		owner, err = t.queryContract(contractAddress, "query", "ownerOf", amountOrId)
-	} else {
-		owner, err = t.queryContract(contractAddress, "ownerOf", amountOrId)
-	}
+	owner, err := t.queryMethod(contractAddress, profile, MethodOwnerOf, amountOrId)
 	if err == nil { // ARC 2
 		tokenType = tx.TokenARC2
 		tokenId = amountOrId
@@ -175,13 +236,8 @@ func (t *AergoClientController) QueryOwnerOf(contractAddress []byte, amountOrId
 	return tokenType, tokenId, amount, amountFloat
 }
 
-func (t *AergoClientController) QueryTotalSupply(contractAddress []byte, isCccvNft bool) (supply string, supplyFloat float32) {
-	var err error
-	if isCccvNft == true {
-		supply, err = t.queryContract(contractAddress, "query", "totalSupply")
-	} else {
-		supply, err = t.queryContract(contractAddress, "totalSupply")
-	}
+func (t *AergoClientController) QueryTotalSupply(contractAddress []byte, profile *ContractProfile) (supply string, supplyFloat float32) {
+	supply, err := t.queryMethod(contractAddress, profile, MethodTotalSupply)
 	if err != nil {
 		return "0", 0
 	}
@@ -194,6 +250,19 @@ func (t *AergoClientController) QueryTotalSupply(contractAddress []byte, isCccvN
 	return supply, supplyFloat
 }
 
+// QueryDecimals returns the decimals of a token. returns an error if failed to query or the result is not decimals
+func (t *AergoClientController) QueryDecimals(contractAddress []byte, profile *ContractProfile) (uint8, error) {
+	strDecimals, err := t.queryMethod(contractAddress, profile, MethodDecimals)
+	if err != nil {
+		return 0, err
+	}
+	decimals, err := strconv.ParseUint(strDecimals, 10, 8)
+	if err != nil {
+		return 0, err
+	}
+	return uint8(decimals), nil
+}
+
 func (t *AergoClientController) QueryTokenInfo(contractAddress []byte) (name, symbol string, decimals uint8) {
 	var err error
 	name, err = t.queryContract(contractAddress, "name")
@@ -251,6 +320,16 @@ func (t *AergoClientController) QueryVerifyContract(contractAddress []byte) (str
 	return metadata, nil
 }
 
+func (t *AergoClientController) QueryView(contractAddress []byte, name string, args ...string) (string, error) {
+	return t.queryContract(contractAddress, name, args...)
+}
+
+// queryMethod queries a standard token method by the calling convention of profile. profile can be nil for standard contracts
+func (t *AergoClientController) queryMethod(address []byte, profile *ContractProfile, method string, args ...string) (string, error) {
+	name, args := profile.CallArgs(method, args...)
+	return t.queryContract(address, name, args...)
+}
+
 func (t *AergoClientController) queryContract(address []byte, name string, args ...string) (string, error) {
 	queryinfo := map[string]interface{}{"Name": name}
 	if args != nil {
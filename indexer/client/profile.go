@@ -0,0 +1,122 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	tx "github.com/aergoio/aergo-indexer-2.0/indexer/transaction"
+	"github.com/aergoio/aergo-indexer-2.0/types"
+)
+
+// calling conventions of token queries
+const (
+	CallDirect = "direct" // calls the method, e.g. balanceOf(account)
+	CallQuery  = "query"  // wraps the method in query, e.g. query("balanceOf", account)
+)
+
+// standard methods which can be overridden by profile
+const (
+	MethodBalanceOf   = "balanceOf"
+	MethodOwnerOf     = "ownerOf"
+	MethodTotalSupply = "totalSupply"
+	MethodDecimals    = "decimals"
+)
+
+// ContractProfiles is a registry of token contracts which are not queried in the standard way
+type ContractProfiles struct {
+	Profiles []*ContractProfile `json:"profiles"`
+}
+
+// ContractProfile is how to query a token contract, and the token record to seed
+type ContractProfile struct {
+	Address string            `json:"address"`
+	Call    string            `json:"call"`    // calling convention, direct if not set
+	Methods map[string]string `json:"methods"` // method names of balanceOf, ownerOf, totalSupply and decimals, if different
+	Token   *ProfileToken     `json:"token"`   // token record to seed, for tokens not created by an event
+}
+
+// ProfileToken is a token record seeded from profile
+type ProfileToken struct {
+	TxId     string       `json:"tx_id"`
+	BlockNo  uint64       `json:"blockno"`
+	Name     string       `json:"name"`
+	Symbol   string       `json:"symbol"`
+	Type     tx.TokenType `json:"type"`
+	Decimals uint8        `json:"decimals"`
+}
+
+// LoadContractProfiles reads and validates the config file of contract profiles
+func LoadContractProfiles(path string) (*ContractProfiles, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseContractProfiles(data)
+}
+
+// ParseContractProfiles decodes and validates the config of contract profiles
+func ParseContractProfiles(data []byte) (*ContractProfiles, error) {
+	profiles := new(ContractProfiles)
+	if err := json.Unmarshal(data, profiles); err != nil {
+		return nil, err
+	}
+	addresses := make(map[string]bool)
+	for _, profile := range profiles.Profiles {
+		if err := profile.Validate(); err != nil {
+			return nil, err
+		}
+		if addresses[profile.Address] == true {
+			return nil, fmt.Errorf("duplicated contract profile: %s", profile.Address)
+		}
+		addresses[profile.Address] = true
+	}
+	return profiles, nil
+}
+
+// Validate checks the profile, and sets the direct calling convention if not set
+func (p *ContractProfile) Validate() error {
+	if _, err := types.DecodeAddress(p.Address); err != nil {
+		return fmt.Errorf("invalid contract address: %q", p.Address)
+	}
+	switch p.Call {
+	case "":
+		p.Call = CallDirect
+	case CallDirect, CallQuery:
+	default:
+		return fmt.Errorf("invalid calling convention: %s %q", p.Address, p.Call)
+	}
+	for method, name := range p.Methods {
+		switch method {
+		case MethodBalanceOf, MethodOwnerOf, MethodTotalSupply, MethodDecimals:
+		default:
+			return fmt.Errorf("invalid method override: %s %q", p.Address, method)
+		}
+		if name == "" {
+			return fmt.Errorf("empty method name: %s %s", p.Address, method)
+		}
+	}
+	if p.Token != nil {
+		if p.Token.Name == "" {
+			return fmt.Errorf("token name is not set: %s", p.Address)
+		}
+		if p.Token.Type != tx.TokenARC1 && p.Token.Type != tx.TokenARC2 {
+			return fmt.Errorf("invalid token type: %s %q", p.Address, p.Token.Type)
+		}
+	}
+	return nil
+}
+
+// CallArgs returns the query name and arguments to call the method. profile can be nil for standard contracts
+func (p *ContractProfile) CallArgs(method string, args ...string) (string, []string) {
+	if p == nil {
+		return method, args
+	}
+	if name, exist := p.Methods[method]; exist == true {
+		method = name
+	}
+	if p.Call == CallQuery {
+		return CallQuery, append([]string{method}, args...)
+	}
+	return method, args
+}
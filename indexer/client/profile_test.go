@@ -0,0 +1,58 @@
+package client
+
+import (
+	"testing"
+
+	tx "github.com/aergoio/aergo-indexer-2.0/indexer/transaction"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseContractProfiles(t *testing.T) {
+	fn_test := func(config string, expectErr bool) {
+		_, err := ParseContractProfiles([]byte(config))
+		require.Equal(t, expectErr, err != nil, config)
+	}
+
+	fn_test(`{"profiles":[{"address":"Amg5KQVkBcX1rR1nmKFPyZPnU8CeGWnZkqAiqp3v4fgSL6KmcCuF","call":"query","token":{"name":"cccv_nft","symbol":"CNFT","type":"ARC2"}}]}`, false)
+	fn_test(`{"profiles":[{"address":"Amg5KQVkBcX1rR1nmKFPyZPnU8CeGWnZkqAiqp3v4fgSL6KmcCuF","methods":{"balanceOf":"balance"}}]}`, false)
+	fn_test(`{"profiles":[{"address":"Amg5KQVkBcX1rR1nmKFPyZPnU8CeGWnZkqAiqp3v4fgSL6KmcCuX"}]}`, true)
+	fn_test(`{"profiles":[{"address":"Amg5KQVkBcX1rR1nmKFPyZPnU8CeGWnZkqAiqp3v4fgSL6KmcCuF","call":"wrapped"}]}`, true)
+	fn_test(`{"profiles":[{"address":"Amg5KQVkBcX1rR1nmKFPyZPnU8CeGWnZkqAiqp3v4fgSL6KmcCuF","methods":{"transfer":"send"}}]}`, true)
+	fn_test(`{"profiles":[{"address":"Amg5KQVkBcX1rR1nmKFPyZPnU8CeGWnZkqAiqp3v4fgSL6KmcCuF","methods":{"ownerOf":""}}]}`, true)
+	fn_test(`{"profiles":[{"address":"Amg5KQVkBcX1rR1nmKFPyZPnU8CeGWnZkqAiqp3v4fgSL6KmcCuF","token":{"name":"nft","type":"ARC3"}}]}`, true)
+	fn_test(`{"profiles":[{"address":"Amg5KQVkBcX1rR1nmKFPyZPnU8CeGWnZkqAiqp3v4fgSL6KmcCuF"},{"address":"Amg5KQVkBcX1rR1nmKFPyZPnU8CeGWnZkqAiqp3v4fgSL6KmcCuF"}]}`, true)
+
+	profiles, err := ParseContractProfiles([]byte(`{"profiles":[{"address":"Amg5KQVkBcX1rR1nmKFPyZPnU8CeGWnZkqAiqp3v4fgSL6KmcCuF"}]}`))
+	require.NoError(t, err)
+	require.Equal(t, CallDirect, profiles.Profiles[0].Call)
+}
+
+func TestCallArgs(t *testing.T) {
+	fn_test := func(profile *ContractProfile, method string, args []string, expectName string, expectArgs []string) {
+		name, callArgs := profile.CallArgs(method, args...)
+		require.Equal(t, expectName, name)
+		require.Equal(t, expectArgs, callArgs)
+	}
+
+	fn_test(nil, MethodTotalSupply, nil, "totalSupply", nil)
+	fn_test(nil, MethodBalanceOf, []string{"a"}, "balanceOf", []string{"a"})
+	fn_test(&ContractProfile{Call: CallDirect, Methods: map[string]string{MethodBalanceOf: "balance"}}, MethodBalanceOf, []string{"a"}, "balance", []string{"a"})
+	fn_test(&ContractProfile{Call: CallQuery}, MethodTotalSupply, nil, "query", []string{"totalSupply"})
+	fn_test(&ContractProfile{Call: CallQuery}, MethodDecimals, nil, "query", []string{"decimals"})
+	fn_test(&ContractProfile{Call: CallQuery, Methods: map[string]string{MethodOwnerOf: "owner"}}, MethodOwnerOf, []string{"1"}, "query", []string{"owner", "1"})
+
+	// shipped profiles of cccv nft, which are loaded by --cccv
+	for network, address := range map[string]string{
+		"mainnet": "Amg5yZU9j5rCYBmCs1TiZ65GpffFBhEBpYyRAyjwXMweouVTeckE",
+		"testnet": "Amg5KQVkBcX1rR1nmKFPyZPnU8CeGWnZkqAiqp3v4fgSL6KmcCuF",
+	} {
+		profiles, err := LoadContractProfiles("../../profiles/" + network + ".json")
+		require.NoError(t, err)
+		require.Len(t, profiles.Profiles, 1)
+		cccv := profiles.Profiles[0]
+		require.Equal(t, address, cccv.Address)
+		require.Equal(t, CallQuery, cccv.Call)
+		require.Equal(t, "cccv_nft", cccv.Token.Name)
+		require.Equal(t, tx.TokenARC2, cccv.Token.Type)
+	}
+}
@@ -1,6 +1,8 @@
 package db
 
 import (
+	"math/big"
+
 	doc "github.com/aergoio/aergo-indexer-2.0/indexer/documents"
 )
 
@@ -12,10 +14,13 @@ type DbController interface {
 	Delete(params QueryParams) (uint64, error)
 	Count(params QueryParams) (int64, error)
 	SelectOne(params QueryParams, createDocument CreateDocFunction) (doc.DocType, error)
+	SelectById(indexName string, id string, createDocument CreateDocFunction) (doc.DocType, error)
+	Search(params QueryParams, createDocument CreateDocFunction) ([]doc.DocType, error)
 	Scroll(params QueryParams, createDocument CreateDocFunction) ScrollInstance
 	GetExistingIndexPrefix(aliasName string, documentType string) (bool, string, error)
 	CreateIndex(indexName string, documentType string) error
 	UpdateAlias(aliasName string, indexName string) error
+	Refresh(indexNames ...string) error
 }
 
 type IntegerRangeQuery struct {
@@ -24,9 +29,28 @@ type IntegerRangeQuery struct {
 	Max   uint64
 }
 
-type StringMatchQuery struct {
+type GreaterThanQuery struct {
 	Field string
-	Value string
+	Value float64
+}
+
+// AmountRangeQuery matches exact amounts on a padded amount field, e.g. amount_padded. Min and Max are in base units of the decimals, and nil is unbounded
+type AmountRangeQuery struct {
+	Field    string
+	Min      *big.Int
+	Max      *big.Int
+	Decimals uint8
+}
+
+// NewAergoRange returns the range query of aergo amounts in aer, e.g. amount_padded of tx or balance_padded of account_balance
+func NewAergoRange(field string, min *big.Int, max *big.Int) *AmountRangeQuery {
+	return &AmountRangeQuery{Field: field, Min: min, Max: max, Decimals: 18}
+}
+
+type StringMatchQuery struct {
+	Field  string
+	Fields []string // matches any of fields instead of Field, if set
+	Value  string
 }
 
 type QueryParams struct {
@@ -37,9 +61,12 @@ type QueryParams struct {
 	Size         int
 	SortField    string
 	SortAsc      bool
+	SortMissing  bool // scroll documents without the sort field as well, before others
 	SelectFields []string
 	IntegerRange *IntegerRangeQuery
 	StringMatch  *StringMatchQuery
+	GreaterThan  *GreaterThanQuery
+	AmountRange  *AmountRangeQuery
 }
 
 type CreateDocFunction = func() doc.DocType
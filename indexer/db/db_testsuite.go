@@ -14,8 +14,9 @@ import (
 //	test 1. Index  = CreateIndexOld - UpdateAlias - GetExistingIndexPrefix - CreateIndexNew - UpdateAlias - GetExistingIndexPrefix(old -> new)
 //	test 2. Count  = Insert - Count - Delete - Count
 //	test 3. Select = Insert - SelectOne - Update - SelectOne
-//	test 4. Scroll = Insert - Scroll
-//	test 5. Bulk   = Bulk - Count
+//	test 4. SelectById = Insert - SelectById ( before refresh )
+//	test 5. Scroll = Insert - Scroll
+//	test 6. Bulk   = Bulk - Count
 func TestDatabaseSuite(t *testing.T, New func() DbController) {
 	t.Run("Index", func(t *testing.T) {
 		tests := []struct {
@@ -205,6 +206,35 @@ func TestDatabaseSuite(t *testing.T, New func() DbController) {
 		}
 	})
 
+	t.Run("SelectById", func(t *testing.T) {
+		db := New()
+		err := db.CreateIndex("idx_get_account_tokens", "account_tokens")
+		require.NoError(t, err, "error in [SelectById]")
+
+		docInsert := &doc.EsAccountTokens{
+			BaseEsType:   &doc.BaseEsType{Id: "AmMxsdWPy5M6RA7eMnnBYVtvCeCwxNdrB1EXaj4d2WEX7MVuAEgZ-AmNpn7K9wg6wsn6oMkTGaFbfrUJGAhGKh8xgBFTi2d5bBRq9Q3sx"},
+			TokenAddress: "AmMxsdWPy5M6RA7eMnnBYVtvCeCwxNdrB1EXaj4d2WEX7MVuAEgZ",
+			Account:      "AmNpn7K9wg6wsn6oMkTGaFbfrUJGAhGKh8xgBFTi2d5bBRq9Q3sx",
+			Balance:      "100",
+			BalanceFloat: 100,
+		}
+		err = db.Insert(docInsert, "idx_get_account_tokens")
+		require.NoError(t, err, "error in [SelectById]")
+
+		// found without refresh
+		docResult, err := db.SelectById("idx_get_account_tokens", docInsert.Id, func() doc.DocType {
+			return getDocType("account_tokens")
+		})
+		require.NoError(t, err, "error in [SelectById]")
+		require.EqualValues(t, docInsert, docResult, "error in [SelectById]")
+
+		docResult, err = db.SelectById("idx_get_account_tokens", "not-exist", func() doc.DocType {
+			return getDocType("account_tokens")
+		})
+		require.NoError(t, err, "error in [SelectById]")
+		require.Nil(t, docResult, "error in [SelectById]")
+	})
+
 	t.Run("Scroll", func(t *testing.T) {
 		tests := []struct {
 			idxName   string
@@ -81,13 +81,7 @@ func (esdb *ElasticsearchDbController) Insert(document doc.DocType, indexName st
 
 // Delete removes documents specified by the query params
 func (esdb *ElasticsearchDbController) Delete(params QueryParams) (uint64, error) {
-	var query elastic.Query
-	if params.IntegerRange != nil {
-		query = elastic.NewRangeQuery(params.IntegerRange.Field).From(params.IntegerRange.Min).To(params.IntegerRange.Max)
-	} else if params.StringMatch != nil {
-		query = elastic.NewMatchQuery(params.StringMatch.Field, params.StringMatch.Value)
-	}
-
+	query := buildQuery(params)
 	res, err := esdb.client.DeleteByQuery().Index(params.IndexName).Query(query).Do(context.Background())
 	if err != nil {
 		return 0, err
@@ -97,24 +91,14 @@ func (esdb *ElasticsearchDbController) Delete(params QueryParams) (uint64, error
 
 // Count returns the number of indexed documents
 func (esdb *ElasticsearchDbController) Count(params QueryParams) (int64, error) {
-	var query elastic.Query
-	if params.IntegerRange != nil {
-		query = elastic.NewRangeQuery(params.IntegerRange.Field).From(params.IntegerRange.Min).To(params.IntegerRange.Max)
-	} else if params.StringMatch != nil {
-		query = elastic.NewMatchQuery(params.StringMatch.Field, params.StringMatch.Value)
-	}
+	query := buildQuery(params)
 	return esdb.client.Count(params.IndexName).Query(query).Do(context.Background())
 }
 
 // SelectOne selects a single document
 func (esdb *ElasticsearchDbController) SelectOne(params QueryParams, createDocument CreateDocFunction) (doc.DocType, error) {
 	service := esdb.client.Search().Index(params.IndexName)
-	if params.IntegerRange != nil {
-		query := elastic.NewRangeQuery(params.IntegerRange.Field).From(params.IntegerRange.Min).To(params.IntegerRange.Max)
-		service = service.Query(query)
-	}
-	if params.StringMatch != nil {
-		query := elastic.NewMatchQuery(params.StringMatch.Field, params.StringMatch.Value)
+	if query := buildQuery(params); query != nil {
 		service = service.Query(query)
 	}
 	if params.SortField != "" {
@@ -139,6 +123,122 @@ func (esdb *ElasticsearchDbController) SelectOne(params QueryParams, createDocum
 	return document, nil
 }
 
+// SelectById gets a single document by id. unlike search, it is realtime and finds documents not refreshed yet
+func (esdb *ElasticsearchDbController) SelectById(indexName string, id string, createDocument CreateDocFunction) (doc.DocType, error) {
+	res, err := esdb.client.Get().Index(indexName).Id(id).Realtime(true).Do(context.Background())
+	if elastic.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if res == nil || !res.Found || res.Source == nil {
+		return nil, nil
+	}
+
+	// Unmarshall document
+	document := createDocument()
+	if err := json.Unmarshal(res.Source, document); err != nil {
+		return nil, err
+	}
+	document.SetID(res.Id)
+	return document, nil
+}
+
+// Refresh makes recently indexed documents searchable
+func (esdb *ElasticsearchDbController) Refresh(indexNames ...string) error {
+	_, err := esdb.client.Refresh(indexNames...).Do(context.Background())
+	return err
+}
+
+// Search selects documents sorted by the sort field, up to the size
+func (esdb *ElasticsearchDbController) Search(params QueryParams, createDocument CreateDocFunction) ([]doc.DocType, error) {
+	service := esdb.client.Search().Index(params.IndexName)
+	if query := buildQuery(params); query != nil {
+		service = service.Query(query)
+	}
+	if params.SortField != "" {
+		service = service.Sort(params.SortField, params.SortAsc).From(params.From)
+	}
+
+	res, err := service.Size(params.Size).Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	if res == nil || res.Hits == nil {
+		return nil, nil
+	}
+
+	// Unmarshall documents
+	documents := make([]doc.DocType, 0, len(res.Hits.Hits))
+	for _, hit := range res.Hits.Hits {
+		document := createDocument()
+		if err := json.Unmarshal([]byte(hit.Source), document); err != nil {
+			return nil, err
+		}
+		document.SetID(hit.Id)
+		documents = append(documents, document)
+	}
+	return documents, nil
+}
+
+// buildQuery combines the conditions of params with the given queries, all of which must match
+func buildQuery(params QueryParams, queries ...elastic.Query) elastic.Query {
+	if params.IntegerRange != nil {
+		queries = append(queries, elastic.NewRangeQuery(params.IntegerRange.Field).From(params.IntegerRange.Min).To(params.IntegerRange.Max))
+	}
+	if params.StringMatch != nil {
+		queries = append(queries, stringMatchQuery(params.StringMatch))
+	}
+	if params.GreaterThan != nil {
+		queries = append(queries, elastic.NewRangeQuery(params.GreaterThan.Field).Gt(params.GreaterThan.Value))
+	}
+	if params.AmountRange != nil {
+		queries = append(queries, amountRangeQuery(params.AmountRange))
+	}
+	switch len(queries) {
+	case 0:
+		return nil
+	case 1:
+		return queries[0]
+	default:
+		return elastic.NewBoolQuery().Must(queries...)
+	}
+}
+
+// stringMatchQuery returns the match query of the field, or of any of the fields
+func stringMatchQuery(match *StringMatchQuery) elastic.Query {
+	if len(match.Fields) == 0 {
+		return elastic.NewMatchQuery(match.Field, match.Value)
+	}
+	queries := make([]elastic.Query, 0, len(match.Fields))
+	for _, field := range match.Fields {
+		queries = append(queries, elastic.NewMatchQuery(field, match.Value))
+	}
+	return elastic.NewBoolQuery().Should(queries...).MinimumNumberShouldMatch(1)
+}
+
+// amountRangeQuery returns the range query of padded amounts, which sort as numbers.
+// a bound which can not be padded is below zero or beyond uint256, so it either bounds nothing or matches none
+func amountRangeQuery(amountRange *AmountRangeQuery) elastic.Query {
+	query := elastic.NewRangeQuery(amountRange.Field)
+	if amountRange.Min != nil {
+		if min := doc.PadAmount(amountRange.Min, amountRange.Decimals); min != "" {
+			query = query.Gte(min)
+		} else if amountRange.Min.Sign() > 0 {
+			return elastic.NewMatchNoneQuery()
+		}
+	}
+	if amountRange.Max != nil {
+		if max := doc.PadAmount(amountRange.Max, amountRange.Decimals); max != "" {
+			query = query.Lte(max)
+		} else if amountRange.Max.Sign() < 0 {
+			return elastic.NewMatchNoneQuery()
+		}
+	}
+	return query
+}
+
 // UpdateAlias updates an alias with a new index name and delete stale indices
 func (esdb *ElasticsearchDbController) UpdateAlias(aliasName string, indexName string) error {
 	ctx := context.Background()
@@ -197,7 +297,8 @@ func (esdb *ElasticsearchDbController) Scroll(params QueryParams, createDocument
 	fsc := elastic.NewFetchSourceContext(true).Include(params.SelectFields...)
 
 	scroll := esdb.client.Scroll(params.IndexName)
-	if params.SortField != "" {
+	var queries []elastic.Query
+	if params.SortField != "" && (params.From != 0 || params.To != 0) {
 		query := elastic.NewRangeQuery(params.SortField)
 		if params.From != 0 {
 			query = query.From(params.From)
@@ -205,13 +306,22 @@ func (esdb *ElasticsearchDbController) Scroll(params QueryParams, createDocument
 		if params.To != 0 {
 			query = query.To(params.To)
 		}
-		scroll = scroll.Query(query)
+		if params.SortMissing {
+			queries = append(queries, elastic.NewBoolQuery().Should(query, elastic.NewBoolQuery().MustNot(elastic.NewExistsQuery(params.SortField))))
+		} else {
+			queries = append(queries, query)
+		}
 	}
-	if params.StringMatch != nil {
-		scroll = scroll.Query(elastic.NewMatchQuery(params.StringMatch.Field, params.StringMatch.Value))
+	if query := buildQuery(params, queries...); query != nil {
+		scroll = scroll.Query(query)
 	}
 
-	scroll = scroll.Size(params.Size).Sort(params.SortField, params.SortAsc).FetchSourceContext(fsc)
+	if params.SortMissing {
+		scroll = scroll.SortBy(elastic.NewFieldSort(params.SortField).Order(params.SortAsc).Missing("_first"))
+	} else {
+		scroll = scroll.Sort(params.SortField, params.SortAsc)
+	}
+	scroll = scroll.Size(params.Size).FetchSourceContext(fsc)
 	return &EsScrollInstance{
 		scrollService:  scroll,
 		ctx:            context.Background(),
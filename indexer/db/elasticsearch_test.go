@@ -2,6 +2,8 @@ package db
 
 import (
 	"context"
+	"math/big"
+	"strings"
 	"testing"
 
 	doc "github.com/aergoio/aergo-indexer-2.0/indexer/documents"
@@ -47,3 +49,59 @@ func mockupDocker() (mock *gnomock.Container, err error) {
 	}
 	return mock, nil
 }
+
+func TestBuildQuery(t *testing.T) {
+	fn_test := func(params QueryParams, expect interface{}) {
+		source, err := buildQuery(params).Source()
+		require.NoError(t, err)
+		require.Equal(t, expect, source)
+	}
+
+	million := new(big.Int).Mul(big.NewInt(1000000), new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))
+	fn_test(QueryParams{AmountRange: NewAergoRange("amount_padded", million, nil)}, map[string]interface{}{
+		"range": map[string]interface{}{
+			"amount_padded": map[string]interface{}{
+				"from":          strings.Repeat("0", 71) + "1" + strings.Repeat("0", 24),
+				"include_lower": true,
+				"include_upper": true,
+				"to":            nil,
+			},
+		},
+	})
+	fn_test(QueryParams{AmountRange: &AmountRangeQuery{Field: "supply_padded", Min: big.NewInt(1), Max: big.NewInt(5)}}, map[string]interface{}{
+		"range": map[string]interface{}{
+			"supply_padded": map[string]interface{}{
+				"from":          strings.Repeat("0", 77) + "1" + strings.Repeat("0", 18),
+				"include_lower": true,
+				"include_upper": true,
+				"to":            strings.Repeat("0", 77) + "5" + strings.Repeat("0", 18),
+			},
+		},
+	})
+
+	// bounds below zero or beyond uint256 bound nothing or match none
+	beyond := new(big.Int).Exp(big.NewInt(10), big.NewInt(80), nil)
+	fn_test(QueryParams{AmountRange: &AmountRangeQuery{Field: "supply_padded", Min: big.NewInt(-1), Max: beyond}}, map[string]interface{}{
+		"range": map[string]interface{}{
+			"supply_padded": map[string]interface{}{
+				"from":          nil,
+				"include_lower": true,
+				"include_upper": true,
+				"to":            nil,
+			},
+		},
+	})
+	fn_test(QueryParams{AmountRange: &AmountRangeQuery{Field: "supply_padded", Min: beyond}}, map[string]interface{}{"match_none": map[string]interface{}{}})
+	fn_test(QueryParams{AmountRange: &AmountRangeQuery{Field: "supply_padded", Max: big.NewInt(-1)}}, map[string]interface{}{"match_none": map[string]interface{}{}})
+
+	// match any of fields
+	fn_test(QueryParams{StringMatch: &StringMatchQuery{Fields: []string{"from", "to"}, Value: "a"}}, map[string]interface{}{
+		"bool": map[string]interface{}{
+			"minimum_should_match": "1",
+			"should": []interface{}{
+				map[string]interface{}{"match": map[string]interface{}{"from": map[string]interface{}{"query": "a"}}},
+				map[string]interface{}{"match": map[string]interface{}{"to": map[string]interface{}{"query": "a"}}},
+			},
+		},
+	})
+}
@@ -36,6 +36,8 @@ type ChanInfoType struct {
 	Contract      chan ChanInfo
 	TokenTransfer chan ChanInfo
 	AccTokens     chan ChanInfo
+	NftHistory    chan ChanInfo
+	EventIndex    map[string]chan ChanInfo // custom event indices by type name
 }
 
 type VerifiedStatus string
@@ -0,0 +1,94 @@
+package documents
+
+import (
+	"math/big"
+	"time"
+)
+
+// NewBpStats returns empty block production of the bp
+func NewBpStats(bp string) *EsBpStats {
+	return &EsBpStats{
+		BaseEsType: &BaseEsType{Id: bp},
+		Bp:         bp,
+		Reward:     "0",
+	}
+}
+
+// Apply adds a block produced by the bp
+func (s *EsBpStats) Apply(blockDoc *EsBlock) {
+	if s.Blocks == 0 || blockDoc.BlockNo < s.FirstBlock {
+		s.FirstBlock, s.FirstTs = blockDoc.BlockNo, blockDoc.Timestamp
+	}
+	if s.Blocks == 0 || blockDoc.BlockNo > s.LastBlock {
+		s.LastBlock, s.LastTs = blockDoc.BlockNo, blockDoc.Timestamp
+	}
+	s.Blocks++
+	s.TotalSize += blockDoc.Size
+	s.TotalTxs += blockDoc.TxCount
+	s.addReward(blockDoc.RewardAmount, 1)
+	s.average()
+}
+
+// Revert removes a block produced by the bp. the first and last block are left to the caller, since they need the other blocks
+func (s *EsBpStats) Revert(blockDoc *EsBlock) {
+	if s.Blocks == 0 {
+		return
+	}
+	s.Blocks--
+	s.TotalSize -= min64(s.TotalSize, blockDoc.Size)
+	s.TotalTxs -= min64(s.TotalTxs, blockDoc.TxCount)
+	s.addReward(blockDoc.RewardAmount, -1)
+	s.average()
+}
+
+// SetLast sets the last block produced by the bp, or clears the block range if lastBlock is nil
+func (s *EsBpStats) SetLast(lastBlock *EsBlock) {
+	if lastBlock == nil {
+		s.FirstBlock, s.FirstTs = 0, time.Time{}
+		s.LastBlock, s.LastTs = 0, time.Time{}
+		return
+	}
+	s.LastBlock, s.LastTs = lastBlock.BlockNo, lastBlock.Timestamp
+}
+
+// AddMissed adds slots missed by the bp. negative slots are reverted
+func (s *EsBpStats) AddMissed(slots int64) {
+	if slots < 0 && uint64(-slots) > s.MissedSlots {
+		s.MissedSlots = 0
+		return
+	}
+	s.MissedSlots = uint64(int64(s.MissedSlots) + slots)
+}
+
+func (s *EsBpStats) addReward(amount string, sign int64) {
+	reward, ok := new(big.Int).SetString(amount, 10)
+	if !ok {
+		return
+	}
+	total, ok := new(big.Int).SetString(s.Reward, 10)
+	if !ok {
+		total = big.NewInt(0)
+	}
+	total.Add(total, reward.Mul(reward, big.NewInt(sign)))
+	if total.Sign() < 0 {
+		total.SetInt64(0)
+	}
+	s.Reward = total.String()
+	s.RewardFloat = bigIntToFloat(total, 18)
+}
+
+func (s *EsBpStats) average() {
+	if s.Blocks == 0 {
+		s.AvgSize, s.AvgTxs = 0, 0
+		return
+	}
+	s.AvgSize = float64(s.TotalSize) / float64(s.Blocks)
+	s.AvgTxs = float64(s.TotalTxs) / float64(s.Blocks)
+}
+
+func min64(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -3,61 +3,102 @@ package documents
 import (
 	"fmt"
 	"math/big"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/aergoio/aergo-indexer-2.0/indexer/metadata"
 	"github.com/aergoio/aergo-indexer-2.0/indexer/transaction"
 	"github.com/aergoio/aergo-indexer-2.0/types"
 	"github.com/mr-tron/base58"
 	"google.golang.org/protobuf/proto"
 )
 
-// ConvBlock converts Block from RPC into Elasticsearch type - 1.0
-func ConvBlock(block *types.Block, blockProducer string) *EsBlock {
-	rewardAmount := ""
-	if len(block.Header.Consensus) > 0 {
-		rewardAmount = "160000000000000000"
-	}
-	return &EsBlock{
+// sources of the block reward
+const (
+	RewardFromParam = "param" // voting reward of the chain
+	RewardFromState = "state" // balance change of the reward account
+)
+
+// ConvBlock converts Block from RPC into Elasticsearch type - 1.0. votingReward is the chain voting reward at the block, or empty if no reward is paid. names are resolved with resolver
+func ConvBlock(block *types.Block, blockProducer string, votingReward string, resolver transaction.NameResolver) *EsBlock {
+	blockDoc := &EsBlock{
 		BaseEsType:    &BaseEsType{Id: base58.Encode(block.Hash)},
 		Timestamp:     time.Unix(0, block.Header.Timestamp),
 		BlockNo:       block.Header.BlockNo,
 		PreviousBlock: base58.Encode(block.Header.PrevBlockHash),
 		TxCount:       uint64(len(block.Body.Txs)),
 		Size:          uint64(proto.Size(block)),
-		Coinbase:      transaction.EncodeAndResolveAccount(block.Header.CoinbaseAccount, block.Header.BlockNo),
+		Coinbase:      transaction.EncodeAndResolveAccount(block.Header.CoinbaseAccount, block.Header.BlockNo, resolver),
 		BlockProducer: blockProducer,
-		RewardAccount: transaction.EncodeAndResolveAccount(block.Header.Consensus, block.Header.BlockNo),
-		RewardAmount:  rewardAmount,
+		RewardAccount: transaction.EncodeAndResolveAccount(block.Header.Consensus, block.Header.BlockNo, resolver),
+	}
+	// the reward is paid to the account in the consensus field
+	if reward, ok := new(big.Int).SetString(votingReward, 10); ok && len(block.Header.Consensus) > 0 {
+		blockDoc.SetReward(reward, RewardFromParam)
+	}
+	return blockDoc
+}
+
+// SetReward sets the reward amount of block and where it comes from
+func (b *EsBlock) SetReward(amount *big.Int, source string) {
+	b.RewardAmount = amount.String()
+	b.RewardAmountFloat = bigIntToFloat(amount, 18)
+	b.RewardSource = source
+}
+
+// ConvBlockUpReward converts the reward of block into Elasticsearch type
+func ConvBlockUpReward(blockDoc *EsBlock) *EsBlockUpReward {
+	return &EsBlockUpReward{
+		BaseEsType:        &BaseEsType{Id: blockDoc.Id},
+		RewardAmount:      blockDoc.RewardAmount,
+		RewardAmountFloat: blockDoc.RewardAmountFloat,
+		RewardSource:      blockDoc.RewardSource,
 	}
 }
 
-// ConvTx converts Tx from RPC into Elasticsearch type
-func ConvTx(txIdx uint64, tx *types.Tx, receipt *types.Receipt, blockDoc *EsBlock) *EsTx {
+// ConvTx converts Tx from RPC into Elasticsearch type. gasPrice is the chain gas price at the block, since the gas price of tx is always zero. names are resolved with resolver
+func ConvTx(txIdx uint64, tx *types.Tx, receipt *types.Receipt, blockDoc *EsBlock, gasPrice string, resolver transaction.NameResolver) *EsTx {
 	var status string = "NO_RECEIPT"
 	var result string
 	var gasUsed uint64
 	var feeDelegation bool
 	var feeUsed string
-	var gasPrice string
+	var feeUsedFloat float32
+	var feePayer string
+	var feeMismatch bool
 	var contract string
 	if receipt != nil {
 		status = receipt.Status
 		gasUsed = receipt.GasUsed
-		contract = transaction.EncodeAndResolveAccount(receipt.ContractAddress, blockDoc.BlockNo)
+		contract = transaction.EncodeAndResolveAccount(receipt.ContractAddress, blockDoc.BlockNo, resolver)
 		feeDelegation = receipt.FeeDelegation
 		result = receipt.Ret
 		feeUsed = big.NewInt(0).SetBytes(receipt.FeeUsed).String()
+		feeUsedFloat = bigIntToFloat(big.NewInt(0).SetBytes(receipt.FeeUsed), 18)
+
+		// the called contract pays the fee of fee delegation
+		feePayer = transaction.EncodeAndResolveAccount(tx.Body.Account, blockDoc.BlockNo, resolver)
+		if feeDelegation == true {
+			feePayer = transaction.EncodeAndResolveAccount(tx.Body.Recipient, blockDoc.BlockNo, resolver)
+		}
+
+		// fee is charged by gas since v2
+		if price, ok := new(big.Int).SetString(gasPrice, 10); ok && gasUsed > 0 {
+			expectFee := new(big.Int).Mul(new(big.Int).SetUint64(gasUsed), price)
+			feeMismatch = expectFee.Cmp(new(big.Int).SetBytes(receipt.FeeUsed)) != 0
+		}
 	}
-	// TODO: currently, gas price always zero in tx. so, use default price
-	gasPrice = "50000000000"
-	// gasPrice := big.NewInt(0).SetBytes(tx.GetBody().GasPrice)
 	amount := big.NewInt(0).SetBytes(tx.GetBody().Amount)
 	category, method := transaction.DetectTxCategory(tx)
 	if len(method) > 50 {
 		method = method[:50]
 	}
 	nonce := tx.Body.Nonce
+	var ops []transaction.MulticallOp
+	if category == transaction.TxMultiCall {
+		ops, _ = transaction.UnmarshalMulticall(tx.GetBody().GetPayload())
+	}
 
 	return &EsTx{
 		BaseEsType:    &BaseEsType{Id: base58.Encode(tx.Hash)},
@@ -66,13 +107,16 @@ func ConvTx(txIdx uint64, tx *types.Tx, receipt *types.Receipt, blockDoc *EsBloc
 		Timestamp:     blockDoc.Timestamp,
 		TxIdx:         txIdx,
 		Payload:       string(tx.GetBody().GetPayload()),
-		Account:       transaction.EncodeAndResolveAccount(tx.Body.Account, blockDoc.BlockNo),
-		Recipient:     transaction.EncodeAndResolveAccount(tx.Body.Recipient, blockDoc.BlockNo),
+		Account:       transaction.EncodeAndResolveAccount(tx.Body.Account, blockDoc.BlockNo, resolver),
+		Recipient:     transaction.EncodeAccount(tx.Body.Recipient),
+		RecipientAddr: transaction.EncodeAndResolveAccount(tx.Body.Recipient, blockDoc.BlockNo, resolver),
 		Amount:        amount.String(),
 		AmountFloat:   bigIntToFloat(amount, 18),
+		AmountPadded:  PadAmount(amount, 18),
 		Type:          uint64(tx.Body.Type),
 		Category:      category,
 		Method:        method,
+		Ops:           ops,
 		Status:        status,
 		Result:        result,
 		Contract:      contract,
@@ -82,54 +126,199 @@ func ConvTx(txIdx uint64, tx *types.Tx, receipt *types.Receipt, blockDoc *EsBloc
 		GasUsed:       gasUsed,
 		GasLimit:      tx.Body.GasLimit,
 		FeeUsed:       feeUsed,
+		FeeUsedFloat:  feeUsedFloat,
+		FeePayer:      feePayer,
+		FeeMismatch:   feeMismatch,
+	}
+}
+
+// MethodOf returns the method called to the contract. for multicall, the method of the sub calls
+func (t *EsTx) MethodOf(contract string) string {
+	if t.Category == transaction.TxMultiCall {
+		return transaction.MulticallMethodOf(t.Ops, contract)
 	}
+	return t.Method
 }
 
 // ConvContractCreateTx creates document for token creation
 func ConvContract(txDoc *EsTx, contractAddress []byte) *EsContract {
 	return &EsContract{
-		BaseEsType: &BaseEsType{Id: transaction.EncodeAndResolveAccount(contractAddress, txDoc.BlockNo)},
+		BaseEsType: &BaseEsType{Id: transaction.EncodeAccount(contractAddress)},
 		Creator:    txDoc.Account,
 		TxId:       txDoc.GetID(),
 		BlockNo:    txDoc.BlockNo,
 		Timestamp:  txDoc.Timestamp,
 		Payload:    txDoc.Payload,
+
+		VersionTx:      txDoc.GetID(),
+		VersionBlockNo: txDoc.BlockNo,
+		PayloadHash:    transaction.HashPayload([]byte(txDoc.Payload)),
+		CodeHash:       transaction.HashPayload(transaction.ContractCode([]byte(txDoc.Payload))),
+	}
+}
+
+// SetTokenDetection sets the token standard detected from abi. the token type is set only if the detection is confident enough
+func (c *EsContract) SetTokenDetection(detection *transaction.TokenDetection) {
+	if detection == nil {
+		return
+	}
+	if detection.IsToken() {
+		c.TokenType = string(detection.Type)
+	}
+	c.Interfaces = detection.Interfaces
+	c.TokenConfidence = float32(detection.Confidence)
+}
+
+// ConvContractVersion creates a version document of a deploy or redeploy
+func ConvContractVersion(txDoc *EsTx, contractAddress string, abiDoc *EsContractAbi) *EsContractVersion {
+	versionDoc := &EsContractVersion{
+		BaseEsType:  &BaseEsType{Id: fmt.Sprintf("%s-%s", contractAddress, txDoc.GetID())},
+		Contract:    contractAddress,
+		TxId:        txDoc.GetID(),
+		BlockNo:     txDoc.BlockNo,
+		Timestamp:   txDoc.Timestamp,
+		Deployer:    txDoc.Account,
+		Category:    txDoc.Category,
+		Payload:     txDoc.Payload,
+		PayloadHash: transaction.HashPayload([]byte(txDoc.Payload)),
+		CodeHash:    transaction.HashPayload(transaction.ContractCode([]byte(txDoc.Payload))),
+	}
+	if abiDoc != nil {
+		versionDoc.AbiId = abiDoc.GetID()
+		for _, fn := range abiDoc.Functions {
+			versionDoc.AbiFunctions = append(versionDoc.AbiFunctions, fn.Name)
+		}
+	}
+	return versionDoc
+}
+
+// ConvContractUpVersion creates a document to point the contract at the version
+func ConvContractUpVersion(versionDoc *EsContractVersion) *EsContractUpVersion {
+	return &EsContractUpVersion{
+		BaseEsType:     &BaseEsType{Id: versionDoc.Contract},
+		Payload:        versionDoc.Payload,
+		VersionTx:      versionDoc.TxId,
+		VersionBlockNo: versionDoc.BlockNo,
+		PayloadHash:    versionDoc.PayloadHash,
+		CodeHash:       versionDoc.CodeHash,
 	}
 }
 
-func ConvContractUp(contractAddress string, status, token, codeUrl, code string) *EsContractUp {
+func ConvContractUp(contractAddress string, status, token, codeUrl, code, compiler, reason string) *EsContractUp {
 	return &EsContractUp{
-		BaseEsType:     &BaseEsType{Id: contractAddress},
-		VerifiedToken:  token,
-		VerifiedStatus: status,
-		CodeUrl:        codeUrl,
-		Code:           code,
+		BaseEsType:       &BaseEsType{Id: contractAddress},
+		VerifiedToken:    token,
+		VerifiedStatus:   status,
+		VerifiedCompiler: compiler,
+		VerifiedReason:   reason,
+		CodeUrl:          codeUrl,
+		Code:             code,
 	}
 }
 
+// ConvContractAbi converts ABI from RPC into Elasticsearch type
+func ConvContractAbi(txDoc *EsTx, contractAddress string, abi *types.ABI) *EsContractAbi {
+	abiDoc := &EsContractAbi{
+		BaseEsType:     &BaseEsType{Id: fmt.Sprintf("%s-%s", contractAddress, txDoc.GetID())},
+		Contract:       contractAddress,
+		TxId:           txDoc.GetID(),
+		BlockNo:        txDoc.BlockNo,
+		Timestamp:      txDoc.Timestamp,
+		Language:       abi.GetLanguage(),
+		Version:        abi.GetVersion(),
+		Functions:      make([]*EsAbiFunction, 0, len(abi.GetFunctions())),
+		StateVariables: make([]*EsAbiStateVar, 0, len(abi.GetStateVariables())),
+	}
+	for _, fn := range abi.GetFunctions() {
+		arguments := make([]string, 0, len(fn.GetArguments()))
+		for _, arg := range fn.GetArguments() {
+			arguments = append(arguments, arg.GetName())
+		}
+		abiDoc.Functions = append(abiDoc.Functions, &EsAbiFunction{
+			Name:          fn.GetName(),
+			Arguments:     arguments,
+			Payable:       fn.GetPayable(),
+			View:          fn.GetView(),
+			FeeDelegation: fn.GetFeeDelegation(),
+		})
+	}
+	for _, stateVar := range abi.GetStateVariables() {
+		abiDoc.StateVariables = append(abiDoc.StateVariables, &EsAbiStateVar{
+			Name: stateVar.GetName(),
+			Type: stateVar.GetType(),
+			Len:  stateVar.GetLen(),
+		})
+	}
+	return abiDoc
+}
+
+// GetFunction returns the abi function of the given name
+func (a *EsContractAbi) GetFunction(name string) *EsAbiFunction {
+	for _, fn := range a.Functions {
+		if fn.Name == name {
+			return fn
+		}
+	}
+	return nil
+}
+
+// FunctionViews maps function names of the abi to whether they are view
+func (a *EsContractAbi) FunctionViews() map[string]bool {
+	functions := make(map[string]bool, len(a.Functions))
+	for _, fn := range a.Functions {
+		functions[fn.Name] = fn.View
+	}
+	return functions
+}
+
 // ConvEvent converts Event from RPC into Elasticsearch type
 func ConvEvent(event *types.Event, blockDoc *EsBlock, txDoc *EsTx, txIdx uint64) *EsEvent {
 	id := fmt.Sprintf("%d-%d-%d", blockDoc.BlockNo, txDoc.TxIdx, event.EventIdx)
 	return &EsEvent{
 		BaseEsType: &BaseEsType{Id: id},
-		Contract:   transaction.EncodeAndResolveAccount(event.ContractAddress, txDoc.BlockNo),
+		Contract:   transaction.EncodeAccount(event.ContractAddress),
 		BlockNo:    blockDoc.BlockNo,
 		TxId:       txDoc.Id,
 		TxIdx:      txIdx,
 		EventIdx:   uint64(event.EventIdx),
 		EventName:  event.EventName,
 		EventArgs:  event.JsonArgs,
+		Args:       transaction.FlattenEventArgs(event.JsonArgs),
 	}
 }
 
-func ConvTokenUp(txDoc *EsTx, contractAddress []byte, supply string, supplyFloat float32) *EsTokenUpSupply {
+func ConvTokenUp(txDoc *EsTx, contractAddress []byte, tokenType transaction.TokenType, supply string, supplyFloat float32, decimals uint8) *EsTokenUpSupply {
 	return &EsTokenUpSupply{
-		BaseEsType:  &BaseEsType{Id: transaction.EncodeAndResolveAccount(contractAddress, txDoc.BlockNo)},
-		Supply:      supply,
-		SupplyFloat: supplyFloat,
+		BaseEsType:   &BaseEsType{Id: transaction.EncodeAccount(contractAddress)},
+		Supply:       supply,
+		SupplyFloat:  supplyFloat,
+		SupplyPadded: padAmountOf(supply, supplyDecimals(tokenType, decimals)),
 	}
 }
 
+// supplyDecimals returns the decimals of the supply of token. supply of nft is the number of tokens, whatever decimals it reports
+func supplyDecimals(tokenType transaction.TokenType, decimals uint8) uint8 {
+	if tokenType == transaction.TokenARC2 {
+		return 0
+	}
+	return decimals
+}
+
+func ConvTokenUpHolders(tokenAddress string, holders *TokenHolders, supply string) *EsTokenUpHolders {
+	tokenUpDoc := &EsTokenUpHolders{
+		BaseEsType:  &BaseEsType{Id: tokenAddress},
+		HolderCount: holders.Count,
+		TopHolders:  make([]*EsTokenHolder, 0, len(holders.Top)),
+	}
+	for _, holder := range holders.Top {
+		topHolder := *holder
+		topHolder.Share = share(holder.Balance, supply)
+		tokenUpDoc.TopHolders = append(tokenUpDoc.TopHolders, &topHolder)
+		tokenUpDoc.TopHoldersShare += topHolder.Share
+	}
+	return tokenUpDoc
+}
+
 func ConvTokenUpVerified(tokenDoc *EsToken, status, tokenAddress, owner, comment, email, regDate, homepageUrl, imageUrl string, totalTransfer uint64) *EsTokenUpVerified {
 	return &EsTokenUpVerified{
 		BaseEsType:     &BaseEsType{Id: tokenDoc.Id},
@@ -147,7 +336,7 @@ func ConvTokenUpVerified(tokenDoc *EsToken, status, tokenAddress, owner, comment
 
 func ConvToken(txDoc *EsTx, contractAddress []byte, tokenType transaction.TokenType, name string, symbol string, decimals uint8, supply string, supplyFloat float32) *EsToken {
 	return &EsToken{
-		BaseEsType:   &BaseEsType{Id: transaction.EncodeAndResolveAccount(contractAddress, txDoc.BlockNo)},
+		BaseEsType:   &BaseEsType{Id: transaction.EncodeAccount(contractAddress)},
 		TxId:         txDoc.GetID(),
 		BlockNo:      txDoc.BlockNo,
 		Creator:      txDoc.Account, // tx account --> token creator
@@ -159,11 +348,12 @@ func ConvToken(txDoc *EsTx, contractAddress []byte, tokenType transaction.TokenT
 		Decimals:     decimals,
 		Supply:       supply,
 		SupplyFloat:  supplyFloat,
+		SupplyPadded: padAmountOf(supply, supplyDecimals(tokenType, decimals)),
 	}
 }
 
 // ConvName parses a name transaction into Elasticsearch type
-func ConvName(tx *types.Tx, blockNo uint64) *EsName {
+func ConvName(tx *types.Tx, blockNo uint64, resolver transaction.NameResolver) *EsName {
 	var name = "error"
 	var address string
 
@@ -171,7 +361,7 @@ func ConvName(tx *types.Tx, blockNo uint64) *EsName {
 	if err == nil {
 		name = payload.Args[0]
 		if payload.Name == "v1createName" {
-			address = transaction.EncodeAndResolveAccount(tx.Body.Account, blockNo)
+			address = transaction.EncodeAndResolveAccount(tx.Body.Account, blockNo, resolver)
 		}
 		if payload.Name == "v1updateName" {
 			address = payload.Args[1]
@@ -188,6 +378,258 @@ func ConvName(tx *types.Tx, blockNo uint64) *EsName {
 	}
 }
 
+// ConvNameHistory creates a history entry of name state from a name transaction
+func ConvNameHistory(txDoc *EsTx, owner, destination string) *EsNameHistory {
+	return &EsNameHistory{
+		BlockNo:     txDoc.BlockNo,
+		UpdateTx:    txDoc.GetID(),
+		Operation:   txDoc.Method,
+		Owner:       owner,
+		Destination: destination,
+	}
+}
+
+// names of chain parameters tracked per block range
+const (
+	ParamGasPrice     = "gasprice"
+	ParamVotingReward = "votingreward"
+)
+
+// ConvChainParam converts the value of a chain parameter observed at the block into Elasticsearch type
+func ConvChainParam(name string, blockNo uint64, ts time.Time, value *big.Int) *EsChainParam {
+	return &EsChainParam{
+		BaseEsType: &BaseEsType{Id: fmt.Sprintf("%s-%d", name, blockNo)},
+		Name:       name,
+		BlockNo:    blockNo,
+		Timestamp:  ts,
+		Value:      value.String(),
+		ValueFloat: bigIntToFloat(value, 0),
+	}
+}
+
+// Apply appends the value observed at the block if it differs from the latest value. values observed before the latest change are ignored
+func (h ChainParamHistory) Apply(param *EsChainParam) (ChainParamHistory, bool) {
+	if len(h) > 0 {
+		latest := h[len(h)-1]
+		if param.BlockNo <= latest.BlockNo || param.Value == latest.Value {
+			return h, false
+		}
+	}
+	return append(h, param), true
+}
+
+// ValueAt returns the value applied at the block. the value is unknown before the first change
+func (h ChainParamHistory) ValueAt(blockNo uint64) (string, bool) {
+	for i := len(h) - 1; i >= 0; i-- {
+		if h[i].BlockNo <= blockNo {
+			return h[i].Value, true
+		}
+	}
+	return "", false
+}
+
+// Rollback removes the changes from the block
+func (h ChainParamHistory) Rollback(fromBlockNo uint64) ChainParamHistory {
+	kept := h[:0]
+	for _, change := range h {
+		if change.BlockNo < fromBlockNo {
+			kept = append(kept, change)
+		}
+	}
+	return kept
+}
+
+// NewNameState creates an empty name state
+func NewNameState(name string) *EsNameState {
+	return &EsNameState{
+		BaseEsType: &BaseEsType{Id: name},
+		Name:       name,
+	}
+}
+
+// ApplyHistory inserts a history entry in block order and refreshes the current state.
+// an entry of the same tx replaces the previous one
+func (s *EsNameState) ApplyHistory(history *EsNameHistory) {
+	idx := len(s.History)
+	for i, h := range s.History {
+		if h.UpdateTx == history.UpdateTx {
+			s.History = append(s.History[:i], s.History[i+1:]...)
+			idx = len(s.History)
+			break
+		}
+	}
+	for i, h := range s.History {
+		if h.BlockNo > history.BlockNo {
+			idx = i
+			break
+		}
+	}
+	s.History = append(s.History, nil)
+	copy(s.History[idx+1:], s.History[idx:])
+	s.History[idx] = history
+	s.refresh()
+}
+
+// RollbackHistory removes history entries from the given block and refreshes the current state
+func (s *EsNameState) RollbackHistory(fromBlockNo uint64) {
+	kept := s.History[:0]
+	for _, h := range s.History {
+		if h.BlockNo < fromBlockNo {
+			kept = append(kept, h)
+		}
+	}
+	s.History = kept
+	s.refresh()
+}
+
+// DestinationAt returns the destination of the name as of the given block
+func (s *EsNameState) DestinationAt(blockNo uint64) (string, bool) {
+	for i := len(s.History) - 1; i >= 0; i-- {
+		if s.History[i].BlockNo <= blockNo {
+			return s.History[i].Destination, s.History[i].Destination != ""
+		}
+	}
+	return "", false
+}
+
+func (s *EsNameState) refresh() {
+	if len(s.History) == 0 {
+		s.Owner, s.Destination, s.CreatedBlock, s.BlockNo, s.UpdateTx = "", "", 0, 0, ""
+		return
+	}
+	last := s.History[len(s.History)-1]
+	s.Owner = last.Owner
+	s.Destination = last.Destination
+	s.CreatedBlock = s.History[0].BlockNo
+	for _, h := range s.History {
+		if strings.HasSuffix(h.Operation, "createname") {
+			s.CreatedBlock = h.BlockNo
+		}
+	}
+	s.BlockNo = last.BlockNo
+	s.UpdateTx = last.UpdateTx
+}
+
+// ConvEnterpriseHistory creates a history document of enterprise config or cluster change
+func ConvEnterpriseHistory(txDoc *EsTx, payload *transaction.EnterprisePayload, progress *types.ConfChangeProgress) *EsEnterpriseHistory {
+	historyDoc := &EsEnterpriseHistory{
+		BaseEsType: &BaseEsType{Id: txDoc.Id},
+		BlockNo:    txDoc.BlockNo,
+		Timestamp:  txDoc.Timestamp,
+		Sender:     txDoc.Account,
+		Operation:  payload.Name,
+		Status:     txDoc.Status,
+		Key:        payload.Key,
+		Values:     payload.Values,
+		Enable:     payload.Enable,
+	}
+	if payload.Cluster != nil {
+		historyDoc.ClusterCommand = payload.Cluster.Command
+		historyDoc.ClusterName = payload.Cluster.Name
+		historyDoc.ClusterId = payload.Cluster.ID
+		historyDoc.ClusterPeerId = payload.Cluster.PeerID
+		historyDoc.ClusterAddress = payload.Cluster.Address
+	}
+	if progress != nil {
+		historyDoc.ClusterState = progress.GetState().String()
+		historyDoc.ClusterError = progress.GetErr()
+		for _, member := range progress.GetMembers() {
+			historyDoc.ClusterMembers = append(historyDoc.ClusterMembers, member.GetName())
+		}
+	}
+	return historyDoc
+}
+
+// NewEnterpriseConf creates an empty config state of the key
+func NewEnterpriseConf(key string) *EsEnterpriseConf {
+	return &EsEnterpriseConf{
+		BaseEsType: &BaseEsType{Id: key},
+		Key:        key,
+	}
+}
+
+// ApplyHistory applies a successful change of the key to the config state, as the enterprise contract does. changes are applied in block order
+func (c *EsEnterpriseConf) ApplyHistory(historyDoc *EsEnterpriseHistory) (applied bool) {
+	if historyDoc.Key != c.Key || historyDoc.Status != "SUCCESS" {
+		return false
+	}
+	switch historyDoc.Operation {
+	case transaction.EnterpriseAppendAdmin, transaction.EnterpriseAppendConf:
+		for _, value := range historyDoc.Values {
+			if !containsString(c.Values, value) {
+				c.Values = append(c.Values, value)
+			}
+		}
+	case transaction.EnterpriseRemoveAdmin, transaction.EnterpriseRemoveConf:
+		kept := make([]string, 0, len(c.Values))
+		for _, value := range c.Values {
+			if !containsString(historyDoc.Values, value) {
+				kept = append(kept, value)
+			}
+		}
+		c.Values = kept
+	case transaction.EnterpriseSetConf:
+		c.Values = append([]string(nil), historyDoc.Values...)
+	case transaction.EnterpriseEnableConf:
+		c.On = historyDoc.Enable
+	default:
+		return false
+	}
+	// admins are enabled while any admin exists
+	if c.Key == transaction.EnterpriseAdmins {
+		c.On = len(c.Values) > 0
+	}
+	c.BlockNo = historyDoc.BlockNo
+	c.Timestamp = historyDoc.Timestamp
+	c.UpdateTx = historyDoc.Id
+	return true
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ConvRaftMember creates a document of raft membership or leader change
+func ConvRaftMember(blockDoc *EsBlock, event string, member transaction.ConsensusBp, leader string, members []transaction.ConsensusBp, updateTx string) *EsRaftMember {
+	sorted := make([]transaction.ConsensusBp, len(members))
+	copy(sorted, members)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].PeerID < sorted[j].PeerID
+	})
+	memberIds := make([]string, 0, len(sorted))
+	memberNames := make([]string, 0, len(sorted))
+	for _, m := range sorted {
+		memberIds = append(memberIds, m.PeerID)
+		memberNames = append(memberNames, m.Name)
+	}
+
+	id := fmt.Sprintf("%d-%s", blockDoc.BlockNo, event)
+	if event == "leader" {
+		id = fmt.Sprintf("%s-%s", id, leader)
+	} else if member.PeerID != "" {
+		id = fmt.Sprintf("%s-%s", id, member.PeerID)
+	}
+	return &EsRaftMember{
+		BaseEsType:  &BaseEsType{Id: id},
+		BlockNo:     blockDoc.BlockNo,
+		Timestamp:   blockDoc.Timestamp,
+		Event:       event,
+		Name:        member.Name,
+		RaftId:      member.RaftID,
+		PeerId:      member.PeerID,
+		Address:     member.Addr,
+		Leader:      leader,
+		Members:     memberIds,
+		MemberNames: memberNames,
+		UpdateTx:    updateTx,
+	}
+}
+
 func ConvNFT(ttDoc *EsTokenTransfer, tokenUri string, imageUrl string) *EsNFT {
 	return &EsNFT{
 		BaseEsType:   &BaseEsType{Id: fmt.Sprintf("%s-%s", ttDoc.TokenAddress, ttDoc.TokenId)},
@@ -201,24 +643,102 @@ func ConvNFT(ttDoc *EsTokenTransfer, tokenUri string, imageUrl string) *EsNFT {
 	}
 }
 
-func ConvTokenTransfer(contractAddress []byte, txDoc *EsTx, idx int, from string, to string, tokenId string, amount string, amountFloat float32) *EsTokenTransfer {
-	return &EsTokenTransfer{
+// ConvNFTHistory converts a mint, transfer or burn event of nft
+func ConvNFTHistory(txDoc *EsTx, ttDoc *EsTokenTransfer, eventIdx int, event transaction.EventName) *EsNFTHistory {
+	return &EsNFTHistory{
+		BaseEsType:   &BaseEsType{Id: ttDoc.Id},
+		NftId:        fmt.Sprintf("%s-%s", ttDoc.TokenAddress, ttDoc.TokenId),
+		TokenAddress: ttDoc.TokenAddress,
+		TokenId:      ttDoc.TokenId,
+		Event:        string(event),
+		From:         ttDoc.From,
+		To:           ttDoc.To,
+		TxId:         ttDoc.TxId,
+		BlockNo:      ttDoc.BlockNo,
+		Timestamp:    ttDoc.Timestamp,
+		Order:        ttDoc.BlockNo<<32 | (txDoc.TxIdx&0xffff)<<16 | uint64(eventIdx)&0xffff,
+	}
+}
+
+// ConvNFTFromHistory restores the nft doc from its last history. off-chain metadata of the doc is kept
+func ConvNFTFromHistory(historyDoc *EsNFTHistory, tokenUri string, imageUrl string) *EsNFTUp {
+	account := historyDoc.To
+	if historyDoc.Event == string(transaction.EventBurn) {
+		account = "BURN"
+	}
+	return &EsNFTUp{
+		BaseEsType: &BaseEsType{Id: historyDoc.NftId},
+		Timestamp:  historyDoc.Timestamp,
+		BlockNo:    historyDoc.BlockNo,
+		Account:    account,
+		TokenUri:   tokenUri,
+		ImageUrl:   imageUrl,
+	}
+}
+
+// ConvNFTMetadata converts the metadata resolved from token uri of nft. err is recorded if failed to resolve
+func ConvNFTMetadata(nftId string, md *metadata.Metadata, err error) *EsNFTMetadata {
+	metadataDoc := &EsNFTMetadata{
+		BaseEsType: &BaseEsType{Id: nftId},
+		MetadataTs: time.Now(),
+	}
+	if err != nil {
+		metadataDoc.MetadataError = err.Error()
+		return metadataDoc
+	}
+	metadataDoc.Name = md.Name
+	metadataDoc.Description = md.Description
+	metadataDoc.MetadataImage = md.Image
+	metadataDoc.Attributes = md.Attributes
+	metadataDoc.MetadataHash = md.Hash
+	metadataDoc.MetadataTs = md.FetchedAt
+	return metadataDoc
+}
+
+func ConvTokenTransfer(contractAddress []byte, txDoc *EsTx, idx int, from string, to string, tokenId string, amount string, amountFloat float32, decimals uint8) *EsTokenTransfer {
+	tokenTransferDoc := &EsTokenTransfer{
 		BaseEsType:   &BaseEsType{Id: fmt.Sprintf("%s-%d", txDoc.Id, idx)},
 		TxId:         txDoc.GetID(),
 		BlockNo:      txDoc.BlockNo,
 		Timestamp:    txDoc.Timestamp,
-		TokenAddress: transaction.EncodeAndResolveAccount(contractAddress, txDoc.BlockNo),
+		TokenAddress: transaction.EncodeAccount(contractAddress),
 		Sender:       txDoc.Account,
+		Method:       txDoc.MethodOf(transaction.EncodeAccount(contractAddress)),
 		From:         from,
 		To:           to,
 		TokenId:      tokenId,
 		Amount:       amount,
 		AmountFloat:  amountFloat,
 	}
+	// amount of nft is the owner
+	if value, ok := new(big.Int).SetString(amount, 10); ok {
+		tokenTransferDoc.AmountAdjusted, tokenTransferDoc.AmountPadded = adjustDecimals(value, decimals), PadAmount(value, decimals)
+	}
+	return tokenTransferDoc
+}
+
+// ConvTokenStats rolls up the transfers of a token in the period. mint and burn are not counted as transfers
+func ConvTokenStats(tokenAddress string, period StatsPeriod, start time.Time, decimals uint8, transfers []*EsTokenTransfer) *EsTokenStats {
+	acc := NewTokenStatsAcc(tokenAddress, period, start)
+	for _, transfer := range transfers {
+		acc.AddTransfer(transfer)
+	}
+	return acc.Stats(decimals)
+}
+
+// ConvChainStats rolls up the txs in the period. new accounts, contracts and tokens are counted by the caller
+func ConvChainStats(period StatsPeriod, start time.Time, blockCount uint64, txs []*EsTx, newAccounts, newContracts, newTokens uint64) *EsChainStats {
+	acc := NewChainStatsAcc(period, start)
+	acc.blocks = blockCount
+	for _, txDoc := range txs {
+		acc.AddTx(txDoc)
+	}
+	acc.AddNewAccounts(newAccounts)
+	return acc.Stats(newContracts, newTokens)
 }
 
-func ConvAccountTokens(tokenType transaction.TokenType, tokenAddress string, timestamp time.Time, account string, balance string, balanceFloat float32) *EsAccountTokens {
-	return &EsAccountTokens{
+func ConvAccountTokens(tokenType transaction.TokenType, tokenAddress string, timestamp time.Time, account string, balance string, balanceFloat float32, decimals uint8) *EsAccountTokens {
+	accountTokensDoc := &EsAccountTokens{
 		BaseEsType:   &BaseEsType{Id: fmt.Sprintf("%s-%s", account, tokenAddress)},
 		Account:      account,
 		TokenAddress: tokenAddress,
@@ -227,17 +747,23 @@ func ConvAccountTokens(tokenType transaction.TokenType, tokenAddress string, tim
 		Balance:      balance,
 		BalanceFloat: balanceFloat,
 	}
+	if value, ok := new(big.Int).SetString(balance, 10); ok {
+		accountTokensDoc.BalanceAdjusted, accountTokensDoc.BalancePadded = adjustDecimals(value, decimals), PadAmount(value, decimals)
+	}
+	return accountTokensDoc
 }
 
 func ConvAccountBalance(blockNo uint64, address string, ts time.Time, balance string, balanceFloat float32, staking string, stakingFloat float32) *EsAccountBalance {
 	return &EsAccountBalance{
-		BaseEsType:   &BaseEsType{Id: address},
-		Timestamp:    ts,
-		BlockNo:      blockNo,
-		Balance:      balance,
-		BalanceFloat: balanceFloat,
-		Staking:      staking,
-		StakingFloat: stakingFloat,
+		BaseEsType:    &BaseEsType{Id: address},
+		Timestamp:     ts,
+		BlockNo:       blockNo,
+		Balance:       balance,
+		BalanceFloat:  balanceFloat,
+		BalancePadded: padAmountOf(balance, 18),
+		Staking:       staking,
+		StakingFloat:  stakingFloat,
+		StakingPadded: padAmountOf(staking, 18),
 	}
 }
 
@@ -259,6 +785,95 @@ func ConvWhitelist(token string, contract string, whitelistType string) *EsWhite
 	}
 }
 
+func ConvBpVotes(blockDoc *EsBlock, candidate string, votes *big.Int, rank uint64, active bool) *EsBpVotes {
+	return &EsBpVotes{
+		BaseEsType: &BaseEsType{Id: fmt.Sprintf("%d-%s", blockDoc.BlockNo, candidate)},
+		BlockNo:    blockDoc.BlockNo,
+		Timestamp:  blockDoc.Timestamp,
+		Candidate:  candidate,
+		Votes:      votes.String(),
+		VotesFloat: bigIntToFloat(votes, 18),
+		Rank:       rank,
+		Active:     active,
+	}
+}
+
+func ConvBpChange(blockDoc *EsBlock, bps, schedule, added, removed []string) *EsBpChange {
+	return &EsBpChange{
+		BaseEsType: &BaseEsType{Id: fmt.Sprintf("%d", blockDoc.BlockNo)},
+		BlockNo:    blockDoc.BlockNo,
+		Timestamp:  blockDoc.Timestamp,
+		Bps:        bps,
+		Schedule:   schedule,
+		Added:      added,
+		Removed:    removed,
+	}
+}
+
+// Apply appends the change of bp set. changes before the latest one are ignored
+func (h BpScheduleHistory) Apply(change *EsBpChange) BpScheduleHistory {
+	if len(h) > 0 && change.BlockNo <= h[len(h)-1].BlockNo {
+		return h
+	}
+	return append(h, change)
+}
+
+// ScheduleAt returns the bp schedule in effect at the block. the schedule is unknown before the first change
+func (h BpScheduleHistory) ScheduleAt(blockNo uint64) []string {
+	for i := len(h) - 1; i >= 0; i-- {
+		if h[i].BlockNo <= blockNo {
+			return h[i].Schedule
+		}
+	}
+	return nil
+}
+
+// Latest returns the latest change, or nil if no change is known
+func (h BpScheduleHistory) Latest() *EsBpChange {
+	if len(h) == 0 {
+		return nil
+	}
+	return h[len(h)-1]
+}
+
+// Rollback removes the changes from the block
+func (h BpScheduleHistory) Rollback(fromBlockNo uint64) BpScheduleHistory {
+	kept := h[:0]
+	for _, change := range h {
+		if change.BlockNo < fromBlockNo {
+			kept = append(kept, change)
+		}
+	}
+	return kept
+}
+
+// ConvAccountVotes converts AccountVoteInfo from RPC into Elasticsearch type. only bp voting is kept
+func ConvAccountVotes(txDoc *EsTx, account string, voteInfo *types.AccountVoteInfo) *EsAccountVotes {
+	candidates := make([]string, 0)
+	amount := big.NewInt(0)
+	for _, voting := range voteInfo.GetVoting() {
+		if voting.GetId() != transaction.VoteBP {
+			continue
+		}
+		candidates = append(candidates, voting.GetCandidates()...)
+		if am, ok := new(big.Int).SetString(voting.GetAmount(), 10); ok {
+			amount = am
+		}
+	}
+	staking := big.NewInt(0).SetBytes(voteInfo.GetStaking().GetAmount())
+
+	return &EsAccountVotes{
+		BaseEsType:   &BaseEsType{Id: account},
+		BlockNo:      txDoc.BlockNo,
+		Timestamp:    txDoc.Timestamp,
+		Candidates:   candidates,
+		Amount:       amount.String(),
+		AmountFloat:  bigIntToFloat(amount, 18),
+		Staking:      staking.String(),
+		StakingFloat: bigIntToFloat(staking, 18),
+	}
+}
+
 // bigIntToFloat takes a big.Int, divides it by 10^exp and returns the resulting float
 // Note that this float is not precise. It can be used for sorting purposes
 func bigIntToFloat(a *big.Int, exp int64) float32 {
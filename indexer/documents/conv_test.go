@@ -1,11 +1,15 @@
 package documents
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/aergoio/aergo-indexer-2.0/indexer/metadata"
 	tx "github.com/aergoio/aergo-indexer-2.0/indexer/transaction"
 	"github.com/aergoio/aergo-indexer-2.0/types"
 	"github.com/mr-tron/base58"
@@ -23,8 +27,8 @@ func decodeAddr(addr string) []byte {
 }
 
 func TestConvBlock(t *testing.T) {
-	fn_test := func(aergoBlock *types.Block, blockProducer string, esBlockExpect *EsBlock) {
-		esBlockConv := ConvBlock(aergoBlock, blockProducer)
+	fn_test := func(aergoBlock *types.Block, blockProducer string, votingReward string, esBlockExpect *EsBlock) {
+		esBlockConv := ConvBlock(aergoBlock, blockProducer, votingReward, nil)
 		require.Equal(t, esBlockExpect, esBlockConv)
 	}
 
@@ -41,22 +45,44 @@ func TestConvBlock(t *testing.T) {
 		Body: &types.BlockBody{
 			Txs: []*types.Tx{{}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}},
 		},
-	}, "16Uiu2HAmGiJ2QgVAWHMUtzLKKNM5eFUJ3Ds3FN7nYJq1mHN5ZPj9", &EsBlock{
+	}, "16Uiu2HAmGiJ2QgVAWHMUtzLKKNM5eFUJ3Ds3FN7nYJq1mHN5ZPj9", "160000000000000000", &EsBlock{
+		BaseEsType:        &BaseEsType{Id: "AvtCKTqL3eQBCvkidbY7i4YkwbtbuResohfRKQhV5Bu"},
+		Timestamp:         time.Unix(0, 1668652376002288214),
+		BlockNo:           104524962,
+		Size:              207,
+		TxCount:           11,
+		PreviousBlock:     "9CEiURiJbPpxg3JdsXVZAJLsvhMQfMVCytoPdmiJ1Tga",
+		Coinbase:          "5t64bLzisGj793C28zDfkhs8uWitZLoPqj98fMBj27GeRnhbAUEW94d6mhW",
+		BlockProducer:     "16Uiu2HAmGiJ2QgVAWHMUtzLKKNM5eFUJ3Ds3FN7nYJq1mHN5ZPj9",
+		RewardAccount:     "554c66wDnfgGQ2XmBq7Q9jmHuTpNZ",
+		RewardAmount:      "160000000000000000",
+		RewardAmountFloat: 0.16,
+		RewardSource:      RewardFromParam,
+	})
+
+	// no reward before the voting reward is paid
+	fn_test(&types.Block{
+		Hash: decodeBase58("AvtCKTqL3eQBCvkidbY7i4YkwbtbuResohfRKQhV5Bu"),
+		Header: &types.BlockHeader{
+			Timestamp:     1668652376002288214,
+			BlockNo:       100,
+			Consensus:     []byte{48, 69, 2, 33, 0, 132, 143, 216, 185, 150, 194, 108, 165, 179, 18, 240},
+			PrevBlockHash: decodeBase58("9CEiURiJbPpxg3JdsXVZAJLsvhMQfMVCytoPdmiJ1Tga"),
+		},
+		Body: &types.BlockBody{},
+	}, "", "", &EsBlock{
 		BaseEsType:    &BaseEsType{Id: "AvtCKTqL3eQBCvkidbY7i4YkwbtbuResohfRKQhV5Bu"},
 		Timestamp:     time.Unix(0, 1668652376002288214),
-		BlockNo:       104524962,
-		Size:          207,
-		TxCount:       11,
+		BlockNo:       100,
+		Size:          102,
 		PreviousBlock: "9CEiURiJbPpxg3JdsXVZAJLsvhMQfMVCytoPdmiJ1Tga",
-		BlockProducer: "16Uiu2HAmGiJ2QgVAWHMUtzLKKNM5eFUJ3Ds3FN7nYJq1mHN5ZPj9",
 		RewardAccount: "554c66wDnfgGQ2XmBq7Q9jmHuTpNZ",
-		RewardAmount:  "160000000000000000",
 	})
 }
 
 func TestConvTx(t *testing.T) {
-	fn_test := func(txIdx uint64, aergoTx *types.Tx, aergoReceipt *types.Receipt, esBlock *EsBlock, esTxExpect *EsTx) {
-		esTxConv := ConvTx(txIdx, aergoTx, aergoReceipt, esBlock)
+	fn_test := func(txIdx uint64, aergoTx *types.Tx, aergoReceipt *types.Receipt, esBlock *EsBlock, gasPrice string, esTxExpect *EsTx) {
+		esTxConv := ConvTx(txIdx, aergoTx, aergoReceipt, esBlock, gasPrice, nil)
 		require.Equal(t, esTxExpect, esTxConv)
 	}
 
@@ -71,26 +97,122 @@ func TestConvTx(t *testing.T) {
 	}, &types.Receipt{
 		FeeDelegation: true,
 		GasUsed:       100000,
+		FeeUsed:       big.NewInt(5000000000000000).Bytes(),
 	}, &EsBlock{
-		BlockNo:   1,
-		Timestamp: time.Unix(0, 1668652376002288214),
-	}, &EsTx{
+		BaseEsType: &BaseEsType{Id: "B1"},
+		BlockNo:    1,
+		Timestamp:  time.Unix(0, 1668652376002288214),
+	}, "50000000000", &EsTx{
 		TxIdx:         0,
 		BaseEsType:    &BaseEsType{Id: "8Zj68cFzrzUtwPe6kZF8qPgVp9LbsefjdTsi4C3hVY8"},
+		BlockId:       "B1",
 		Timestamp:     time.Unix(0, 1668652376002288214),
 		BlockNo:       1,
 		Account:       "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA",
 		Recipient:     "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA",
+		RecipientAddr: "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA",
 		Amount:        "100",
 		AmountFloat:   bigIntToFloat(big.NewInt(100), 18),
+		AmountPadded:  strings.Repeat("0", 93) + "100",
 		Type:          uint64(types.TxType_TRANSFER),
 		Category:      tx.TxTransfer,
 		Status:        "",
 		FeeDelegation: true,
-		GasPrice:      "0",
+		GasPrice:      "50000000000",
 		GasLimit:      0,
 		GasUsed:       100000,
+		FeeUsed:       "5000000000000000",
+		FeeUsedFloat:  0.005,
+		FeePayer:      "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA",
+	})
+
+	// fee charged by another gas price
+	esTx := ConvTx(0, &types.Tx{
+		Hash: decodeBase58("8Zj68cFzrzUtwPe6kZF8qPgVp9LbsefjdTsi4C3hVY8"),
+		Body: &types.TxBody{
+			Account: decodeAddr("AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA"),
+			Type:    types.TxType_TRANSFER,
+		},
+	}, &types.Receipt{
+		GasUsed: 100000,
+		FeeUsed: big.NewInt(5000000000000000).Bytes(),
+	}, &EsBlock{BaseEsType: &BaseEsType{Id: "B1"}, BlockNo: 1}, "20000000000", nil)
+	require.True(t, esTx.FeeMismatch)
+
+	// recipient name resolved by the given resolver
+	esTx = ConvTx(0, &types.Tx{
+		Hash: decodeBase58("8Zj68cFzrzUtwPe6kZF8qPgVp9LbsefjdTsi4C3hVY8"),
+		Body: &types.TxBody{
+			Account:   decodeAddr("AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA"),
+			Recipient: []byte("testnametest"),
+			Type:      types.TxType_TRANSFER,
+		},
+	}, &types.Receipt{}, &EsBlock{BaseEsType: &BaseEsType{Id: "B1"}, BlockNo: 1}, "50000000000", func(name string, blockNo uint64) (string, bool) {
+		return "AmMK3LZiR1oEf66xzXir7mA5SUVVHSinWUYmh5FwueoVmciH3CuJ", true
 	})
+	require.Equal(t, "testnametest", esTx.Recipient)
+	require.Equal(t, "AmMK3LZiR1oEf66xzXir7mA5SUVVHSinWUYmh5FwueoVmciH3CuJ", esTx.RecipientAddr)
+}
+
+func TestChainParamHistory(t *testing.T) {
+	param := func(blockNo uint64, value int64) *EsChainParam {
+		return ConvChainParam(ParamGasPrice, blockNo, time.Unix(0, 0), big.NewInt(value))
+	}
+	var history ChainParamHistory
+	_, ok := history.ValueAt(10)
+	require.False(t, ok)
+
+	history, ok = history.Apply(param(100, 50000000000))
+	require.True(t, ok)
+	history, ok = history.Apply(param(200, 50000000000))
+	require.False(t, ok)
+	history, ok = history.Apply(param(300, 20000000000))
+	require.True(t, ok)
+	history, ok = history.Apply(param(50, 10000000000))
+	require.False(t, ok)
+	require.Equal(t, "gasprice-300", history[1].Id)
+
+	fn_test := func(blockNo uint64, expect string) {
+		value, ok := history.ValueAt(blockNo)
+		require.True(t, ok)
+		require.Equal(t, expect, value)
+	}
+	fn_test(100, "50000000000")
+	fn_test(299, "50000000000")
+	fn_test(300, "20000000000")
+
+	// unknown before the first change
+	_, ok = history.ValueAt(99)
+	require.False(t, ok)
+
+	history = history.Rollback(300)
+	fn_test(1000, "50000000000")
+	require.Equal(t, 1, len(history))
+}
+
+func TestBpScheduleHistory(t *testing.T) {
+	change := func(blockNo uint64, schedule ...string) *EsBpChange {
+		return ConvBpChange(&EsBlock{BlockNo: blockNo}, schedule, schedule, nil, nil)
+	}
+	var history BpScheduleHistory
+	require.Nil(t, history.ScheduleAt(10))
+	require.Nil(t, history.Latest())
+
+	history = history.Apply(change(100, "bp1", "bp2"))
+	history = history.Apply(change(200, "bp2", "bp1", "bp3"))
+	history = history.Apply(change(150, "bp3"))
+	require.Equal(t, 2, len(history))
+	require.Equal(t, uint64(200), history.Latest().BlockNo)
+
+	// unknown before the first change
+	require.Nil(t, history.ScheduleAt(99))
+	require.Equal(t, []string{"bp1", "bp2"}, history.ScheduleAt(100))
+	require.Equal(t, []string{"bp1", "bp2"}, history.ScheduleAt(199))
+	require.Equal(t, []string{"bp2", "bp1", "bp3"}, history.ScheduleAt(1000))
+
+	history = history.Rollback(200)
+	require.Equal(t, []string{"bp1", "bp2"}, history.ScheduleAt(1000))
+	require.Equal(t, 1, len(history))
 }
 
 func TestConvContract(t *testing.T) {
@@ -112,12 +234,70 @@ func TestConvContract(t *testing.T) {
 		Creator:    "AmLXGJq1GfZWRYjmNVZxCsrJodc1qC1nCXnYkkG7pQLbiWy9NMZw",
 		BlockNo:    1,
 		Timestamp:  time.Unix(0, 1668652376002288214),
+
+		VersionTx:      "8Zj68cFzrzUtwPe6kZF8qPgVp9LbsefjdTsi4C3hVY8",
+		VersionBlockNo: 1,
+		PayloadHash:    "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		CodeHash:       "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+	})
+}
+
+func TestSetTokenDetection(t *testing.T) {
+	fn_test := func(detection *tx.TokenDetection, expectType string) {
+		contractDoc := &EsContract{BaseEsType: &BaseEsType{Id: "contract"}}
+		contractDoc.SetTokenDetection(detection)
+		require.Equal(t, expectType, contractDoc.TokenType)
+	}
+	fn_test(nil, "")
+	fn_test(&tx.TokenDetection{Type: tx.TokenARC1, Confidence: 1}, "ARC1")
+	// mentions keywords, but not a token
+	fn_test(&tx.TokenDetection{Type: tx.TokenARC2, Confidence: 0.2}, "")
+}
+
+func TestConvContractVersion(t *testing.T) {
+	fn_test := func(esTx *EsTx, contractAddress string, abiDoc *EsContractAbi, esVersionExpect *EsContractVersion) {
+		esVersionConv := ConvContractVersion(esTx, contractAddress, abiDoc)
+		require.Equal(t, esVersionExpect, esVersionConv)
+		require.Equal(t, &EsContractUpVersion{
+			BaseEsType:     &BaseEsType{Id: contractAddress},
+			Payload:        esVersionExpect.Payload,
+			VersionTx:      esVersionExpect.TxId,
+			VersionBlockNo: esVersionExpect.BlockNo,
+			PayloadHash:    esVersionExpect.PayloadHash,
+			CodeHash:       esVersionExpect.CodeHash,
+		}, ConvContractUpVersion(esVersionConv))
+	}
+
+	fn_test(&EsTx{
+		BaseEsType: &BaseEsType{Id: "8Zj68cFzrzUtwPe6kZF8qPgVp9LbsefjdTsi4C3hVY8"},
+		Timestamp:  time.Unix(0, 1668652376002288214),
+		BlockNo:    1000,
+		Account:    "AmLXGJq1GfZWRYjmNVZxCsrJodc1qC1nCXnYkkG7pQLbiWy9NMZw",
+		Type:       uint64(types.TxType_REDEPLOY),
+		Category:   tx.TxRedeploy,
+		Payload:    "abc",
+	}, "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA", &EsContractAbi{
+		BaseEsType: &BaseEsType{Id: "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA-8Zj68cFzrzUtwPe6kZF8qPgVp9LbsefjdTsi4C3hVY8"},
+		Functions:  []*EsAbiFunction{{Name: "transfer"}, {Name: "balanceOf"}},
+	}, &EsContractVersion{
+		BaseEsType:   &BaseEsType{Id: "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA-8Zj68cFzrzUtwPe6kZF8qPgVp9LbsefjdTsi4C3hVY8"},
+		Contract:     "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA",
+		TxId:         "8Zj68cFzrzUtwPe6kZF8qPgVp9LbsefjdTsi4C3hVY8",
+		BlockNo:      1000,
+		Timestamp:    time.Unix(0, 1668652376002288214),
+		Deployer:     "AmLXGJq1GfZWRYjmNVZxCsrJodc1qC1nCXnYkkG7pQLbiWy9NMZw",
+		Category:     tx.TxRedeploy,
+		Payload:      "abc",
+		PayloadHash:  "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		CodeHash:     "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		AbiId:        "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA-8Zj68cFzrzUtwPe6kZF8qPgVp9LbsefjdTsi4C3hVY8",
+		AbiFunctions: []string{"transfer", "balanceOf"},
 	})
 }
 
 func TestConvTokenUp(t *testing.T) {
-	fn_test := func(esTx *EsTx, contractAddress []byte, supply string, supplyFloat float32, esTokenUpExpect *EsTokenUpSupply) {
-		esTokenUpConv := ConvTokenUp(esTx, contractAddress, supply, supplyFloat)
+	fn_test := func(esTx *EsTx, contractAddress []byte, tokenType tx.TokenType, supply string, supplyFloat float32, decimals uint8, esTokenUpExpect *EsTokenUpSupply) {
+		esTokenUpConv := ConvTokenUp(esTx, contractAddress, tokenType, supply, supplyFloat, decimals)
 		require.Equal(t, esTokenUpExpect, esTokenUpConv)
 	}
 
@@ -127,10 +307,11 @@ func TestConvTokenUp(t *testing.T) {
 		BlockNo:    95022525,
 		Type:       uint64(types.TxType_CALL),
 		Category:   tx.TxCall,
-	}, decodeAddr("AmhUUoFqF4GxjFxxUZrRUieUCRoWnBHT9ESekVAFbif3jU4Zo5ks"), "1", 1, &EsTokenUpSupply{
-		BaseEsType:  &BaseEsType{Id: "AmhUUoFqF4GxjFxxUZrRUieUCRoWnBHT9ESekVAFbif3jU4Zo5ks"},
-		Supply:      "1",
-		SupplyFloat: 1,
+	}, decodeAddr("AmhUUoFqF4GxjFxxUZrRUieUCRoWnBHT9ESekVAFbif3jU4Zo5ks"), tx.TokenARC1, "1", 1, 0, &EsTokenUpSupply{
+		BaseEsType:   &BaseEsType{Id: "AmhUUoFqF4GxjFxxUZrRUieUCRoWnBHT9ESekVAFbif3jU4Zo5ks"},
+		Supply:       "1",
+		SupplyFloat:  1,
+		SupplyPadded: strings.Repeat("0", 77) + "1" + strings.Repeat("0", 18),
 	})
 	fn_test(&EsTx{
 		BaseEsType: &BaseEsType{Id: "5Cd2ofFgwFQKSU9H4mDctKLCoQcrcAsY8XXcozCL6a2u"},
@@ -138,11 +319,115 @@ func TestConvTokenUp(t *testing.T) {
 		BlockNo:    95022525,
 		Type:       uint64(types.TxType_CALL),
 		Category:   tx.TxCall,
-	}, decodeAddr("AmhUUoFqF4GxjFxxUZrRUieUCRoWnBHT9ESekVAFbif3jU4Zo5ks"), "100000000000000", 100000000000000, &EsTokenUpSupply{
-		BaseEsType:  &BaseEsType{Id: "AmhUUoFqF4GxjFxxUZrRUieUCRoWnBHT9ESekVAFbif3jU4Zo5ks"},
-		Supply:      "100000000000000",
-		SupplyFloat: 100000000000000,
+	}, decodeAddr("AmhUUoFqF4GxjFxxUZrRUieUCRoWnBHT9ESekVAFbif3jU4Zo5ks"), tx.TokenARC1, "100000000000000", 100000000000000, 18, &EsTokenUpSupply{
+		BaseEsType:   &BaseEsType{Id: "AmhUUoFqF4GxjFxxUZrRUieUCRoWnBHT9ESekVAFbif3jU4Zo5ks"},
+		Supply:       "100000000000000",
+		SupplyFloat:  100000000000000,
+		SupplyPadded: strings.Repeat("0", 81) + "100000000000000",
 	})
+	// supply of nft is not adjusted by decimals, as in ConvToken
+	fn_test(&EsTx{BlockNo: 95022525}, decodeAddr("AmhUUoFqF4GxjFxxUZrRUieUCRoWnBHT9ESekVAFbif3jU4Zo5ks"), tx.TokenARC2, "3", 3, 18, &EsTokenUpSupply{
+		BaseEsType:   &BaseEsType{Id: "AmhUUoFqF4GxjFxxUZrRUieUCRoWnBHT9ESekVAFbif3jU4Zo5ks"},
+		Supply:       "3",
+		SupplyFloat:  3,
+		SupplyPadded: strings.Repeat("0", 77) + "3" + strings.Repeat("0", 18),
+	})
+}
+
+func TestTokenHolders(t *testing.T) {
+	balance := func(account string, amount float32) *EsAccountTokens {
+		return &EsAccountTokens{Account: account, Balance: fmt.Sprint(amount), BalanceFloat: amount}
+	}
+	holders := NewTokenHolders(2, 0, nil)
+	fn_test := func(prev, next *EsAccountTokens, expectCount uint64, expectTop []string, expectStale bool) {
+		holders.Apply(prev, next)
+		top := make([]string, 0, len(holders.Top))
+		for _, holder := range holders.Top {
+			top = append(top, holder.Account)
+		}
+		require.Equal(t, expectCount, holders.Count)
+		require.Equal(t, expectTop, top)
+		require.Equal(t, expectStale, holders.Stale)
+	}
+
+	fn_test(nil, balance("a", 10), 1, []string{"a"}, false)
+	fn_test(nil, balance("b", 30), 2, []string{"b", "a"}, false)
+	fn_test(nil, balance("c", 5), 3, []string{"b", "a"}, false)
+	fn_test(balance("c", 5), balance("c", 20), 3, []string{"b", "c"}, false)
+	fn_test(nil, balance("d", 0), 3, []string{"b", "c"}, false)
+	// a top holder increases
+	fn_test(balance("c", 20), balance("c", 40), 3, []string{"c", "b"}, false)
+	// a top holder decreases while others are out of the list
+	fn_test(balance("b", 30), balance("b", 1), 3, []string{"c", "b"}, true)
+
+	// recount
+	holders.Reset(3, []*EsAccountTokens{balance("c", 40), balance("a", 10), balance("b", 1)})
+	require.Equal(t, 2, len(holders.Top))
+	require.False(t, holders.Stale)
+	// a top holder leaves
+	fn_test(balance("c", 40), balance("c", 0), 2, []string{"a"}, true)
+
+	// balances which are equal in float32 are ranked by the exact balance
+	exact := func(account string, amount string) *EsAccountTokens {
+		return &EsAccountTokens{Account: account, Balance: amount, BalanceFloat: 1e18}
+	}
+	holders = NewTokenHolders(2, 0, nil)
+	fn_test(nil, exact("a", "1000000000000000001"), 1, []string{"a"}, false)
+	fn_test(nil, exact("b", "1000000000000000003"), 2, []string{"b", "a"}, false)
+	fn_test(nil, exact("c", "1000000000000000002"), 3, []string{"b", "c"}, false)
+	fn_test(exact("b", "1000000000000000003"), exact("b", "1000000000000000000"), 3, []string{"c", "b"}, true)
+
+	// shares against supply
+	holders = NewTokenHolders(2, 3, []*EsTokenHolder{{Account: "a", Balance: "250", BalanceFloat: 250}, {Account: "b", Balance: "500", BalanceFloat: 500}})
+	require.Equal(t, &EsTokenUpHolders{
+		BaseEsType:  &BaseEsType{Id: "token"},
+		HolderCount: 3,
+		TopHolders: []*EsTokenHolder{
+			{Account: "b", Balance: "500", BalanceFloat: 500, Share: 0.5},
+			{Account: "a", Balance: "250", BalanceFloat: 250, Share: 0.25},
+		},
+		TopHoldersShare: 0.75,
+	}, ConvTokenUpHolders("token", holders, "1000"))
+	require.Equal(t, float32(0), ConvTokenUpHolders("token", holders, "").TopHoldersShare)
+}
+
+func TestBpStats(t *testing.T) {
+	block := func(no uint64, size, txs uint64, reward string) *EsBlock {
+		return &EsBlock{BlockNo: no, Timestamp: time.Unix(int64(no), 0), Size: size, TxCount: txs, RewardAmount: reward}
+	}
+	stats := NewBpStats("bp")
+	stats.Apply(block(20, 300, 3, "160000000000000000"))
+	stats.Apply(block(10, 100, 1, "160000000000000000"))
+	stats.Apply(block(30, 200, 0, ""))
+	require.Equal(t, uint64(3), stats.Blocks)
+	require.Equal(t, uint64(10), stats.FirstBlock)
+	require.Equal(t, uint64(30), stats.LastBlock)
+	require.Equal(t, float64(200), stats.AvgSize)
+	require.Equal(t, float64(4)/3, stats.AvgTxs)
+	require.Equal(t, "320000000000000000", stats.Reward)
+	require.Equal(t, float32(0.32), stats.RewardFloat)
+
+	stats.AddMissed(2)
+	stats.AddMissed(-1)
+	require.Equal(t, uint64(1), stats.MissedSlots)
+	stats.AddMissed(-5)
+	require.Equal(t, uint64(0), stats.MissedSlots)
+
+	// rollback of the last block
+	stats.Revert(block(30, 200, 0, ""))
+	stats.SetLast(block(20, 300, 3, "160000000000000000"))
+	require.Equal(t, uint64(2), stats.Blocks)
+	require.Equal(t, uint64(20), stats.LastBlock)
+	require.Equal(t, float64(200), stats.AvgSize)
+	require.Equal(t, float64(2), stats.AvgTxs)
+
+	stats.Revert(block(20, 300, 3, "160000000000000000"))
+	stats.Revert(block(10, 100, 1, "160000000000000000"))
+	stats.SetLast(nil)
+	require.Equal(t, uint64(0), stats.Blocks)
+	require.Equal(t, uint64(0), stats.LastBlock)
+	require.Equal(t, float64(0), stats.AvgSize)
+	require.Equal(t, "0", stats.Reward)
 }
 
 func TestConvToken(t *testing.T) {
@@ -171,12 +456,13 @@ func TestConvToken(t *testing.T) {
 		Decimals:     18,
 		Supply:       "100000000",
 		SupplyFloat:  100000000,
+		SupplyPadded: strings.Repeat("0", 87) + "100000000",
 	})
 }
 
 func TestConvName(t *testing.T) {
 	fn_test := func(aergoTx *types.Tx, blockNumber uint64, esNameExpect *EsName) {
-		esNameConv := ConvName(aergoTx, blockNumber)
+		esNameConv := ConvName(aergoTx, blockNumber, nil)
 		require.Equal(t, esNameExpect, esNameConv)
 	}
 
@@ -232,9 +518,77 @@ func TestConvNFT(t *testing.T) {
 	)
 }
 
+func TestConvNFTHistory(t *testing.T) {
+	ttDoc := &EsTokenTransfer{
+		BaseEsType:   &BaseEsType{Id: "8Zj68cFzrzUtwPe6kZF8qPgVp9LbsefjdTsi4C3hVY8-2"},
+		TxId:         "8Zj68cFzrzUtwPe6kZF8qPgVp9LbsefjdTsi4C3hVY8",
+		Timestamp:    time.Unix(0, 1668652376002288214),
+		BlockNo:      1000,
+		TokenAddress: "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA",
+		From:         "AmgKtCaGjH4XkXwny2Jb1YH5gdsJGJh78ibWEgLmRWBS5LMfQuTf",
+		To:           "AmM25FKSK1gCqSdUPjnvESsauESNgfZUauHWp7R8Un3zHffEQWBm",
+		TokenId:      "7",
+	}
+	historyDoc := ConvNFTHistory(&EsTx{TxIdx: 3}, ttDoc, 2, tx.EventTransfer)
+	require.Equal(t, &EsNFTHistory{
+		BaseEsType:   &BaseEsType{Id: "8Zj68cFzrzUtwPe6kZF8qPgVp9LbsefjdTsi4C3hVY8-2"},
+		NftId:        "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA-7",
+		TokenAddress: "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA",
+		TokenId:      "7",
+		Event:        "transfer",
+		From:         "AmgKtCaGjH4XkXwny2Jb1YH5gdsJGJh78ibWEgLmRWBS5LMfQuTf",
+		To:           "AmM25FKSK1gCqSdUPjnvESsauESNgfZUauHWp7R8Un3zHffEQWBm",
+		TxId:         "8Zj68cFzrzUtwPe6kZF8qPgVp9LbsefjdTsi4C3hVY8",
+		BlockNo:      1000,
+		Timestamp:    time.Unix(0, 1668652376002288214),
+		Order:        1000<<32 | 3<<16 | 2,
+	}, historyDoc)
+	require.Less(t, historyDoc.Order, ConvNFTHistory(&EsTx{TxIdx: 0}, &EsTokenTransfer{BaseEsType: &BaseEsType{}, BlockNo: 1001}, 0, tx.EventBurn).Order)
+
+	// restore nft from history
+	nftDoc := ConvNFTFromHistory(historyDoc, "https://example.com/7.json", "")
+	require.Equal(t, historyDoc.NftId, nftDoc.Id)
+	require.Equal(t, "AmM25FKSK1gCqSdUPjnvESsauESNgfZUauHWp7R8Un3zHffEQWBm", nftDoc.Account)
+	historyDoc.Event = "burn"
+	require.Equal(t, "BURN", ConvNFTFromHistory(historyDoc, "", "").Account)
+}
+
+func TestConvNFTMetadata(t *testing.T) {
+	fetchedAt := time.Unix(0, 1668652376002288214)
+	metadataDoc := ConvNFTMetadata("AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA-1", &metadata.Metadata{
+		Name:       "Aergo #1",
+		Image:      "ipfs://QmImage",
+		Attributes: []metadata.Attribute{{TraitType: "level", Value: "3"}},
+		Hash:       "f1a2",
+		FetchedAt:  fetchedAt,
+	}, nil)
+	require.Equal(t, &EsNFTMetadata{
+		BaseEsType:    &BaseEsType{Id: "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA-1"},
+		Name:          "Aergo #1",
+		MetadataImage: "ipfs://QmImage",
+		Attributes:    []metadata.Attribute{{TraitType: "level", Value: "3"}},
+		MetadataHash:  "f1a2",
+		MetadataTs:    fetchedAt,
+	}, metadataDoc)
+
+	// failed to resolve
+	metadataDoc = ConvNFTMetadata("AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA-1", nil, errors.New("unexpected status 404"))
+	require.Equal(t, "unexpected status 404", metadataDoc.MetadataError)
+	require.Empty(t, metadataDoc.MetadataHash)
+	require.False(t, metadataDoc.MetadataTs.IsZero())
+
+	// embedded in nft doc
+	raw, err := json.Marshal(&EsNFT{BaseEsType: &BaseEsType{Id: "nft"}, TokenId: "1", EsNFTMetadata: metadataDoc})
+	require.NoError(t, err)
+	require.Contains(t, string(raw), `"metadata_error":"unexpected status 404"`)
+	raw, err = json.Marshal(&EsNFT{BaseEsType: &BaseEsType{Id: "nft"}, TokenId: "1"})
+	require.NoError(t, err)
+	require.NotContains(t, string(raw), "metadata_ts")
+}
+
 func TestConvTokenTransfer(t *testing.T) {
-	fn_test := func(contractAddress []byte, txDoc *EsTx, idx int, from string, to string, tokenId string, amount string, amountFloat float32, esTokenTransferExpect *EsTokenTransfer) {
-		esTokenTransferConv := ConvTokenTransfer(contractAddress, txDoc, idx, from, to, tokenId, amount, amountFloat)
+	fn_test := func(contractAddress []byte, txDoc *EsTx, idx int, from string, to string, tokenId string, amount string, amountFloat float32, decimals uint8, esTokenTransferExpect *EsTokenTransfer) {
+		esTokenTransferConv := ConvTokenTransfer(contractAddress, txDoc, idx, from, to, tokenId, amount, amountFloat, decimals)
 		require.Equal(t, esTokenTransferExpect, esTokenTransferConv)
 	}
 
@@ -246,7 +600,7 @@ func TestConvTokenTransfer(t *testing.T) {
 			Type:       uint64(types.TxType_FEEDELEGATION),
 			Category:   tx.TxCall,
 			Account:    "AmPEHmsGApC19jtNsvuKrfcruxouAAmVDHg8VK32XamWdcGUmeFA",
-		}, 27, "MINT", "AmPEHmsGApC19jtNsvuKrfcruxouAAmVDHg8VK32XamWdcGUmeFA", "a6d6d055488d443d29952c1ca276b34ca_28", "AmPEHmsGApC19jtNsvuKrfcruxouAAmVDHg8VK32XamWdcGUmeFA", 1, &EsTokenTransfer{
+		}, 27, "MINT", "AmPEHmsGApC19jtNsvuKrfcruxouAAmVDHg8VK32XamWdcGUmeFA", "a6d6d055488d443d29952c1ca276b34ca_28", "AmPEHmsGApC19jtNsvuKrfcruxouAAmVDHg8VK32XamWdcGUmeFA", 1, 0, &EsTokenTransfer{
 			BaseEsType:   &BaseEsType{Id: fmt.Sprintf("%s-%d", "34yeCGMt2UxFqrztewP2qgJqATQVRdnsu71faJhaWdCA", 27)},
 			Timestamp:    time.Unix(0, 1668652376002288214),
 			BlockNo:      105810874,
@@ -260,11 +614,311 @@ func TestConvTokenTransfer(t *testing.T) {
 			TokenId:      "a6d6d055488d443d29952c1ca276b34ca_28",
 		},
 	)
+
+	// multicall, attributed to the sub call of token contract
+	fn_test(
+		decodeAddr("Amg5KQVkBcX1rR1nmKFPyZPnU8CeGWnZkqAiqp3v4fgSL6KmcCuF"), &EsTx{
+			BaseEsType: &BaseEsType{Id: "34yeCGMt2UxFqrztewP2qgJqATQVRdnsu71faJhaWdCA"},
+			Timestamp:  time.Unix(0, 1668652376002288214),
+			BlockNo:    105810874,
+			Type:       uint64(types.TxType_MULTICALL),
+			Category:   tx.TxMultiCall,
+			Account:    "AmPEHmsGApC19jtNsvuKrfcruxouAAmVDHg8VK32XamWdcGUmeFA",
+			Ops: []tx.MulticallOp{
+				{Idx: 0, Command: "call", Contract: "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA", Method: "swap"},
+				{Idx: 1, Command: "call", Contract: "Amg5KQVkBcX1rR1nmKFPyZPnU8CeGWnZkqAiqp3v4fgSL6KmcCuF", Method: "safeTransferFrom"},
+			},
+		}, 2, "AmPEHmsGApC19jtNsvuKrfcruxouAAmVDHg8VK32XamWdcGUmeFA", "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA", "", "100", 100, 2, &EsTokenTransfer{
+			BaseEsType:     &BaseEsType{Id: fmt.Sprintf("%s-%d", "34yeCGMt2UxFqrztewP2qgJqATQVRdnsu71faJhaWdCA", 2)},
+			Timestamp:      time.Unix(0, 1668652376002288214),
+			BlockNo:        105810874,
+			TxId:           "34yeCGMt2UxFqrztewP2qgJqATQVRdnsu71faJhaWdCA",
+			TokenAddress:   "Amg5KQVkBcX1rR1nmKFPyZPnU8CeGWnZkqAiqp3v4fgSL6KmcCuF",
+			From:           "AmPEHmsGApC19jtNsvuKrfcruxouAAmVDHg8VK32XamWdcGUmeFA",
+			To:             "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA",
+			Sender:         "AmPEHmsGApC19jtNsvuKrfcruxouAAmVDHg8VK32XamWdcGUmeFA",
+			Method:         "safeTransferFrom",
+			Amount:         "100",
+			AmountFloat:    100,
+			AmountAdjusted: 1,
+			AmountPadded:   strings.Repeat("0", 77) + "1" + strings.Repeat("0", 18),
+		},
+	)
+}
+
+func TestConvTokenStats(t *testing.T) {
+	ts := time.Date(2023, 3, 15, 13, 45, 10, 0, time.UTC)
+	require.Equal(t, time.Date(2023, 3, 15, 13, 0, 0, 0, time.UTC), PeriodHour.Start(ts))
+	require.Equal(t, time.Date(2023, 3, 15, 14, 0, 0, 0, time.UTC), PeriodHour.End(PeriodHour.Start(ts)))
+	require.Equal(t, time.Date(2023, 3, 15, 0, 0, 0, 0, time.UTC), PeriodDay.Start(ts.In(time.FixedZone("KST", 9*3600))))
+	require.Equal(t, time.Date(2023, 3, 16, 0, 0, 0, 0, time.UTC), PeriodDay.End(PeriodDay.Start(ts)))
+
+	transfer := func(from, to, amount string) *EsTokenTransfer {
+		return &EsTokenTransfer{BaseEsType: &BaseEsType{Id: from + "-" + to}, From: from, To: to, Amount: amount}
+	}
+	start := PeriodHour.Start(ts)
+	require.Equal(t, &EsTokenStats{
+		BaseEsType:      &BaseEsType{Id: "token-hour-1678885200"},
+		TokenAddress:    "token",
+		Period:          PeriodHour,
+		Timestamp:       start,
+		TransferCount:   3,
+		Senders:         2,
+		Receivers:       3,
+		Volume:          "100000000000000000001500000000000000000",
+		VolumeFloat:     100000000000000000001.5,
+		MintCount:       1,
+		MintAmount:      "5000000000000000000",
+		MintAmountFloat: 5,
+		BurnCount:       1,
+		BurnAmount:      "250000000000000000",
+		BurnAmountFloat: 0.25,
+	}, ConvTokenStats("token", PeriodHour, start, 18, []*EsTokenTransfer{
+		transfer("MINT", "a", "5000000000000000000"),
+		transfer("a", "b", "1000000000000000000"),
+		transfer("a", "c", "500000000000000000"),
+		transfer("b", "d", "100000000000000000000000000000000000000"),
+		transfer("b", "BURN", "250000000000000000"),
+	}))
+
+	// nft counts ids
+	nft := func(from, to, tokenId string) *EsTokenTransfer {
+		return &EsTokenTransfer{BaseEsType: &BaseEsType{Id: from + "-" + to}, From: from, To: to, Amount: to, TokenId: tokenId}
+	}
+	tokenStatsDoc := ConvTokenStats("nft", PeriodDay, PeriodDay.Start(ts), 0, []*EsTokenTransfer{nft("a", "b", "1"), nft("b", "a", "1")})
+	require.Equal(t, uint64(2), tokenStatsDoc.TransferCount)
+	require.Equal(t, "2", tokenStatsDoc.Volume)
+	require.Equal(t, float64(2), tokenStatsDoc.VolumeFloat)
+	require.Equal(t, "0", tokenStatsDoc.MintAmount)
+}
+
+func TestConvChainStats(t *testing.T) {
+	start := PeriodDay.Start(time.Date(2023, 3, 15, 13, 45, 10, 0, time.UTC))
+	txs := []*EsTx{
+		{Account: "a", Category: tx.TxTransfer, GasUsed: 100000, FeeUsed: "5000000000000000"},
+		{Account: "a", Category: tx.TxCall, GasUsed: 250000, FeeUsed: "12500000000000000"},
+		{Account: "b", Category: tx.TxTransfer, GasUsed: 100000, FeeUsed: "5000000000000000"},
+		{Account: "c", Category: tx.TxDeploy, GasUsed: 0, FeeUsed: ""},
+	}
+	require.Equal(t, &EsChainStats{
+		BaseEsType:     &BaseEsType{Id: "chain-day-1678838400"},
+		Period:         PeriodDay,
+		Timestamp:      start,
+		BlockCount:     86400,
+		TxCount:        4,
+		ActiveAccounts: 3,
+		NewAccounts:    2,
+		NewContracts:   1,
+		NewTokens:      0,
+		TxCategories: []*EsCategoryCount{
+			{Category: tx.TxCall, Count: 1},
+			{Category: tx.TxDeploy, Count: 1},
+			{Category: tx.TxTransfer, Count: 2},
+		},
+		GasUsed:         450000,
+		FeesBurned:      "22500000000000000",
+		FeesBurnedFloat: 0.0225,
+	}, ConvChainStats(PeriodDay, start, 86400, txs, 2, 1, 0))
+}
+
+func TestChainStatsAcc(t *testing.T) {
+	start := PeriodHour.Start(time.Date(2023, 3, 15, 13, 45, 10, 0, time.UTC))
+	acc := NewChainStatsAcc(PeriodHour, start)
+
+	// accumulated block by block, as the rollup of the same blocks and txs
+	acc.AddBlock(&EsBlock{BlockNo: 11})
+	require.Equal(t, []string{"a", "b"}, acc.AddTx(&EsTx{Account: "a", Recipient: "b", Category: tx.TxTransfer, GasUsed: 100000, FeeUsed: "5000000000000000"}))
+	require.Equal(t, []string(nil), acc.AddTx(&EsTx{Account: "b", Recipient: "a", Category: tx.TxTransfer, GasUsed: 100000, FeeUsed: "5000000000000000"}))
+	acc.AddNewAccounts(2)
+	acc.AddBlock(&EsBlock{BlockNo: 10})
+	require.Equal(t, []string{"c"}, acc.AddTx(&EsTx{Account: "c", Category: tx.TxDeploy}))
+	require.Equal(t, uint64(10), acc.FirstBlock)
+	require.Equal(t, uint64(11), acc.LastBlock)
+
+	require.Equal(t, ConvChainStats(PeriodHour, start, 2, []*EsTx{
+		{Account: "a", Recipient: "b", Category: tx.TxTransfer, GasUsed: 100000, FeeUsed: "5000000000000000"},
+		{Account: "b", Recipient: "a", Category: tx.TxTransfer, GasUsed: 100000, FeeUsed: "5000000000000000"},
+		{Account: "c", Category: tx.TxDeploy},
+	}, 2, 1, 0), acc.Stats(1, 0))
+
+	// blocks mined again are not added twice
+	require.False(t, acc.AccumulateBlock(&EsBlock{BlockNo: 11}))
+	require.False(t, acc.AccumulateBlock(&EsBlock{BlockNo: 10}))
+	require.True(t, acc.AccumulateBlock(&EsBlock{BlockNo: 12}))
+	require.Equal(t, uint64(3), acc.Stats(1, 0).BlockCount)
+}
+
+func TestTokenStatsAcc(t *testing.T) {
+	start := PeriodDay.Start(time.Date(2023, 3, 15, 13, 45, 10, 0, time.UTC))
+	transfers := []*EsTokenTransfer{
+		{BaseEsType: &BaseEsType{Id: "t1"}, BlockNo: 1, From: "MINT", To: "a", Amount: "5000000000000000000"},
+		{BaseEsType: &BaseEsType{Id: "t2"}, BlockNo: 2, From: "a", To: "b", Amount: "1000000000000000000"},
+		{BaseEsType: &BaseEsType{Id: "t3"}, BlockNo: 2, From: "b", To: "BURN", Amount: "250000000000000000"},
+	}
+
+	acc := NewTokenStatsAcc("token", PeriodDay, start)
+	for _, transfer := range transfers {
+		acc.AddTransfer(transfer)
+	}
+	require.Equal(t, ConvTokenStats("token", PeriodDay, start, 18, transfers), acc.Stats(18))
+
+	// a transfer added later updates the flushed stats
+	require.True(t, acc.AccumulateTransfer(&EsTokenTransfer{BaseEsType: &BaseEsType{Id: "t4"}, BlockNo: 2, From: "a", To: "c", Amount: "1000000000000000000"}))
+	require.Equal(t, uint64(2), acc.Stats(18).TransferCount)
+	require.Equal(t, uint64(2), acc.Stats(18).Receivers)
+
+	// transfers of blocks mined again are not added twice
+	require.False(t, acc.AccumulateTransfer(transfers[1]))
+	require.False(t, acc.AccumulateTransfer(transfers[0]))
+	require.True(t, acc.AccumulateTransfer(&EsTokenTransfer{BaseEsType: &BaseEsType{Id: "t5"}, BlockNo: 3, From: "a", To: "d", Amount: "1000000000000000000"}))
+	require.False(t, acc.AccumulateTransfer(&EsTokenTransfer{BaseEsType: &BaseEsType{Id: "t5"}, BlockNo: 3, From: "a", To: "d", Amount: "1000000000000000000"}))
+	require.Equal(t, uint64(3), acc.Stats(18).TransferCount)
+}
+
+func TestFeeSponsor(t *testing.T) {
+	sender := "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA"
+	contract := "AmgKtCaGjH4XkXwny2Jb1YH5gdsJGJh78ibWEgLmRWBS5LMfQuTf"
+
+	// the contract pays the fee of fee delegation
+	txDoc := ConvTx(0, &types.Tx{
+		Hash: decodeBase58("8Zj68cFzrzUtwPe6kZF8qPgVp9LbsefjdTsi4C3hVY8"),
+		Body: &types.TxBody{
+			Account:   decodeAddr(sender),
+			Recipient: decodeAddr(contract),
+			Type:      types.TxType_FEEDELEGATION,
+		},
+	}, &types.Receipt{FeeDelegation: true, GasUsed: 100, FeeUsed: big.NewInt(5000).Bytes()}, &EsBlock{BaseEsType: &BaseEsType{Id: "B1"}, BlockNo: 1}, "50", nil)
+	require.Equal(t, contract, txDoc.FeePayer)
+
+	txDoc = ConvTx(0, &types.Tx{
+		Hash: decodeBase58("8Zj68cFzrzUtwPe6kZF8qPgVp9LbsefjdTsi4C3hVY8"),
+		Body: &types.TxBody{Account: decodeAddr(sender), Recipient: decodeAddr(contract), Type: types.TxType_CALL},
+	}, &types.Receipt{GasUsed: 100, FeeUsed: big.NewInt(5000).Bytes()}, &EsBlock{BaseEsType: &BaseEsType{Id: "B1"}, BlockNo: 1}, "50", nil)
+	require.Equal(t, sender, txDoc.FeePayer)
+
+	txs := []*EsTx{
+		{BlockNo: 1, Timestamp: time.Unix(1, 0), Account: "a", Method: "claim", FeeDelegation: true, FeePayer: contract, GasUsed: 10, FeeUsed: "1000000000000000000"},
+		{BlockNo: 3, Timestamp: time.Unix(3, 0), Account: "b", Method: "claim", FeeDelegation: true, FeePayer: contract, GasUsed: 20, FeeUsed: "2000000000000000000"},
+		{BlockNo: 2, Timestamp: time.Unix(2, 0), Account: "a", Method: "claim", FeeDelegation: true, FeePayer: contract, GasUsed: 30, FeeUsed: "500000000000000000"},
+	}
+	require.Nil(t, FeeSponsorsOf(&EsTx{BlockNo: 4, Account: "a", Method: "claim", FeePayer: "a", GasUsed: 40, FeeUsed: "1"}))
+
+	sponsors := make(map[string]*EsFeeSponsor)
+	for _, txDoc := range txs {
+		for _, sponsor := range FeeSponsorsOf(txDoc) {
+			if _, exist := sponsors[sponsor.Id]; !exist {
+				sponsors[sponsor.Id] = sponsor
+			}
+			sponsors[sponsor.Id].Apply(txDoc)
+		}
+	}
+	require.Equal(t, map[string]*EsFeeSponsor{
+		contract:              {BaseEsType: &BaseEsType{Id: contract}, Contract: contract, TxCount: 3, GasUsed: 60, Fee: "3500000000000000000", FeeFloat: 3.5, LastBlock: 3, LastTs: time.Unix(3, 0)},
+		contract + "-a-claim": {BaseEsType: &BaseEsType{Id: contract + "-a-claim"}, Contract: contract, Account: "a", Method: "claim", TxCount: 2, GasUsed: 40, Fee: "1500000000000000000", FeeFloat: 1.5, LastBlock: 2, LastTs: time.Unix(2, 0)},
+		contract + "-b-claim": {BaseEsType: &BaseEsType{Id: contract + "-b-claim"}, Contract: contract, Account: "b", Method: "claim", TxCount: 1, GasUsed: 20, Fee: "2000000000000000000", FeeFloat: 2, LastBlock: 3, LastTs: time.Unix(3, 0)},
+	}, sponsors)
+
+	// revert the tx of block 3
+	sponsors[contract].Revert(txs[1])
+	sponsors[contract+"-b-claim"].Revert(txs[1])
+	require.Equal(t, &EsFeeSponsor{BaseEsType: &BaseEsType{Id: contract}, Contract: contract, TxCount: 2, GasUsed: 40, Fee: "1500000000000000000", FeeFloat: 1.5, LastBlock: 3, LastTs: time.Unix(3, 0)}, sponsors[contract])
+	require.Equal(t, uint64(0), sponsors[contract+"-b-claim"].TxCount)
+	require.Equal(t, "0", sponsors[contract+"-b-claim"].Fee)
+
+	// no sponsored tx
+	require.Equal(t, &EsFeeSponsor{BaseEsType: &BaseEsType{Id: contract}, Contract: contract, Fee: "0"}, NewFeeSponsor(contract, "", ""))
+}
+
+func TestParseEventIndexConfig(t *testing.T) {
+	fn_test := func(config string, expectErr bool) {
+		_, err := ParseEventIndexConfig([]byte(config))
+		require.Equal(t, expectErr, err != nil, config)
+	}
+
+	fn_test(`{"indices":[{"index":"swap","contracts":["*"],"event":"swap","fields":[{"name":"amount","path":"1","type":"bignum"}]}]}`, false)
+	fn_test(`{"indices":[{"index":"Swap","contracts":["*"],"event":"swap"}]}`, true)
+	fn_test(`{"indices":[{"index":"swap","contracts":[],"event":"swap"}]}`, true)
+	fn_test(`{"indices":[{"index":"swap","contracts":["AmgQqVWX3JADRBEVkVCM4CyWdoeXuumeYGGJJxEeoAukRC26hxmw","aergo.system"],"event":"swap"}]}`, false)
+	fn_test(`{"indices":[{"index":"swap","contracts":["swapcontract"],"event":"swap"}]}`, true)
+	fn_test(`{"indices":[{"index":"swap","contracts":[""],"event":"swap"}]}`, true)
+	fn_test(`{"indices":[{"index":"swap","contracts":["*"],"event":""}]}`, true)
+	fn_test(`{"indices":[{"index":"swap","contracts":["*"],"event":"swap","fields":[{"name":"blockno","path":"0","type":"long"}]}]}`, true)
+	fn_test(`{"indices":[{"index":"swap","contracts":["*"],"event":"swap","fields":[{"name":"a","path":"0","type":"text"}]}]}`, true)
+	fn_test(`{"indices":[{"index":"swap","contracts":["*"],"event":"swap","fields":[{"name":"a","path":"","type":"long"}]}]}`, true)
+	fn_test(`{"indices":[{"index":"swap","contracts":["*"],"event":"swap","fields":[{"name":"a","path":"0","type":"bignum"},{"name":"a_float","path":"1","type":"double"}]}]}`, true)
+	fn_test(`{"indices":[{"index":"swap","contracts":["*"],"event":"swap","id":"{tx_id}-{pool}"}]}`, true)
+	fn_test(`{"indices":[{"index":"swap","contracts":["*"],"event":"swap"},{"index":"swap","contracts":["*"],"event":"sync"}]}`, true)
+	fn_test(`{"indices":`, true)
+}
+
+func TestConvEventIndex(t *testing.T) {
+	config, err := ParseEventIndexConfig([]byte(`{"indices":[{
+		"index": "swap",
+		"contracts": ["AmgQqVWX3JADRBEVkVCM4CyWdoeXuumeYGGJJxEeoAukRC26hxmw"],
+		"event": "swap",
+		"id": "{tx_id}-{pool}",
+		"fields": [
+			{"name": "pool", "path": "0", "type": "keyword"},
+			{"name": "amount_in", "path": "1.in", "type": "bignum"},
+			{"name": "amount_out", "path": "1.out", "type": "long"},
+			{"name": "price", "path": "2", "type": "double"},
+			{"name": "exact", "path": "3", "type": "boolean"},
+			{"name": "memo", "path": "4", "type": "keyword"}
+		]
+	}]}`))
+	require.NoError(t, err)
+	spec := config.Indices[0]
+
+	eventDoc := &EsEvent{
+		Contract:  "AmgQqVWX3JADRBEVkVCM4CyWdoeXuumeYGGJJxEeoAukRC26hxmw",
+		BlockNo:   10,
+		TxId:      "tx",
+		TxIdx:     1,
+		EventIdx:  2,
+		EventName: "swap",
+		EventArgs: `["pool1",{"in":{"_bignum":"1000000000000000000"},"out":"x"},1.5,true]`,
+	}
+	require.True(t, spec.Matches(eventDoc))
+	require.False(t, spec.Matches(&EsEvent{Contract: eventDoc.Contract, EventName: "sync"}))
+	require.False(t, spec.Matches(&EsEvent{Contract: "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA", EventName: "swap"}))
+
+	ts := time.Unix(100, 0)
+	eventIndexDoc, err := ConvEventIndex(spec, eventDoc, ts)
+	require.NoError(t, err)
+	require.Equal(t, &EsEventIndex{
+		BaseEsType: &BaseEsType{Id: "tx-pool1"},
+		Fields: map[string]interface{}{
+			"contract":        eventDoc.Contract,
+			"blockno":         uint64(10),
+			"ts":              ts,
+			"tx_id":           "tx",
+			"tx_idx":          uint64(1),
+			"event_idx":       uint64(2),
+			"event_name":      "swap",
+			"pool":            "pool1",
+			"amount_in":       "1000000000000000000",
+			"amount_in_float": float64(1e18),
+			"price":           float64(1.5),
+			"exact":           true,
+		},
+	}, eventIndexDoc)
+
+	// events without a field of the id are not converted, rather than sharing an id with other events
+	eventDoc.EventArgs = `[]`
+	_, err = ConvEventIndex(spec, eventDoc, ts)
+	require.Error(t, err)
+
+	var mapping map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(spec.Mapping()), &mapping))
+	properties := mapping["mappings"].(map[string]interface{})["properties"].(map[string]interface{})
+	require.Equal(t, map[string]interface{}{"enabled": false}, properties["amount_in"])
+	require.Equal(t, map[string]interface{}{"type": "double"}, properties["amount_in_float"])
+	require.Equal(t, map[string]interface{}{"type": "long"}, properties["amount_out"])
 }
 
 func TestConvAccountTokens(t *testing.T) {
-	fn_test := func(tokenType tx.TokenType, tokenAddress string, timestamp time.Time, account string, balance string, balanceFloat float32, esAccountTokensExpect *EsAccountTokens) {
-		esAccountTokensConv := ConvAccountTokens(tokenType, tokenAddress, timestamp, account, balance, balanceFloat)
+	fn_test := func(tokenType tx.TokenType, tokenAddress string, timestamp time.Time, account string, balance string, balanceFloat float32, decimals uint8, esAccountTokensExpect *EsAccountTokens) {
+		esAccountTokensConv := ConvAccountTokens(tokenType, tokenAddress, timestamp, account, balance, balanceFloat, decimals)
 		require.Equal(t, esAccountTokensExpect, esAccountTokensConv)
 	}
 
@@ -272,14 +926,280 @@ func TestConvAccountTokens(t *testing.T) {
 		tx.TokenARC2,
 		"Amg5KQVkBcX1rR1nmKFPyZPnU8CeGWnZkqAiqp3v4fgSL6KmcCuF",
 		time.Unix(0, 1668652376002288214),
-		"AmQLCGCaNqguH9CRuvBLUoYf2dSo77wXeCWyJh5p3mRYqY8o6vZD", "7364", 7364, &EsAccountTokens{
-			BaseEsType:   &BaseEsType{Id: fmt.Sprintf("%s-%s", "AmQLCGCaNqguH9CRuvBLUoYf2dSo77wXeCWyJh5p3mRYqY8o6vZD", "Amg5KQVkBcX1rR1nmKFPyZPnU8CeGWnZkqAiqp3v4fgSL6KmcCuF")},
-			Account:      "AmQLCGCaNqguH9CRuvBLUoYf2dSo77wXeCWyJh5p3mRYqY8o6vZD",
-			TokenAddress: "Amg5KQVkBcX1rR1nmKFPyZPnU8CeGWnZkqAiqp3v4fgSL6KmcCuF",
-			Type:         tx.TokenARC2,
-			Timestamp:    time.Unix(0, 1668652376002288214),
-			Balance:      "7364",
-			BalanceFloat: 7364,
+		"AmQLCGCaNqguH9CRuvBLUoYf2dSo77wXeCWyJh5p3mRYqY8o6vZD", "7364", 7364, 0, &EsAccountTokens{
+			BaseEsType:      &BaseEsType{Id: fmt.Sprintf("%s-%s", "AmQLCGCaNqguH9CRuvBLUoYf2dSo77wXeCWyJh5p3mRYqY8o6vZD", "Amg5KQVkBcX1rR1nmKFPyZPnU8CeGWnZkqAiqp3v4fgSL6KmcCuF")},
+			Account:         "AmQLCGCaNqguH9CRuvBLUoYf2dSo77wXeCWyJh5p3mRYqY8o6vZD",
+			TokenAddress:    "Amg5KQVkBcX1rR1nmKFPyZPnU8CeGWnZkqAiqp3v4fgSL6KmcCuF",
+			Type:            tx.TokenARC2,
+			Timestamp:       time.Unix(0, 1668652376002288214),
+			Balance:         "7364",
+			BalanceFloat:    7364,
+			BalanceAdjusted: 7364,
+			BalancePadded:   strings.Repeat("0", 74) + "7364" + strings.Repeat("0", 18),
 		},
 	)
 }
+
+func TestPadAmount(t *testing.T) {
+	amount := func(str string) *big.Int {
+		n, _ := new(big.Int).SetString(str, 10)
+		return n
+	}
+
+	// 1 token of 0 decimals > 0.999999 token of 18 decimals > 0.5 token of 1 decimal
+	one, lessThanOne, half := PadAmount(amount("1"), 0), PadAmount(amount("999999000000000000"), 18), PadAmount(amount("5"), 1)
+	require.Len(t, one, PaddedLength)
+	require.True(t, one > lessThanOne)
+	require.True(t, lessThanOne > half)
+
+	// decimals over 18 are truncated
+	require.Equal(t, PadAmount(amount("1"), 0), PadAmount(amount("1000000000000000000001"), 21))
+	require.Equal(t, "", PadAmount(amount("-1"), 0))
+	require.Equal(t, "", PadAmount(new(big.Int).Lsh(big.NewInt(1), 320), 0))
+}
+
+func TestConvBpVotes(t *testing.T) {
+	fn_test := func(blockDoc *EsBlock, candidate string, votes *big.Int, rank uint64, active bool, esBpVotesExpect *EsBpVotes) {
+		esBpVotesConv := ConvBpVotes(blockDoc, candidate, votes, rank, active)
+		require.Equal(t, esBpVotesExpect, esBpVotesConv)
+	}
+
+	votes, _ := new(big.Int).SetString("1234000000000000000000", 10)
+	fn_test(&EsBlock{
+		BlockNo:   104524962,
+		Timestamp: time.Unix(0, 1668652376002288214),
+	}, "16Uiu2HAmGiJ2QgVAWHMUtzLKKNM5eFUJ3Ds3FN7nYJq1mHN5ZPj9", votes, 1, true, &EsBpVotes{
+		BaseEsType: &BaseEsType{Id: "104524962-16Uiu2HAmGiJ2QgVAWHMUtzLKKNM5eFUJ3Ds3FN7nYJq1mHN5ZPj9"},
+		BlockNo:    104524962,
+		Timestamp:  time.Unix(0, 1668652376002288214),
+		Candidate:  "16Uiu2HAmGiJ2QgVAWHMUtzLKKNM5eFUJ3Ds3FN7nYJq1mHN5ZPj9",
+		Votes:      "1234000000000000000000",
+		VotesFloat: 1234,
+		Rank:       1,
+		Active:     true,
+	})
+}
+
+func TestConvAccountVotes(t *testing.T) {
+	fn_test := func(txDoc *EsTx, account string, voteInfo *types.AccountVoteInfo, esAccountVotesExpect *EsAccountVotes) {
+		esAccountVotesConv := ConvAccountVotes(txDoc, account, voteInfo)
+		require.Equal(t, esAccountVotesExpect, esAccountVotesConv)
+	}
+
+	fn_test(&EsTx{
+		BlockNo:   104524962,
+		Timestamp: time.Unix(0, 1668652376002288214),
+	}, "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA", &types.AccountVoteInfo{
+		Staking: &types.Staking{Amount: big.NewInt(0).Mul(big.NewInt(20000), big.NewInt(1e18)).Bytes()},
+		Voting: []*types.VoteInfo{
+			{Id: "voteBP", Candidates: []string{"16Uiu2HAmGiJ2QgVAWHMUtzLKKNM5eFUJ3Ds3FN7nYJq1mHN5ZPj9"}, Amount: "20000000000000000000000"},
+			{Id: "BPCOUNT", Candidates: []string{"23"}, Amount: "20000000000000000000000"},
+		},
+	}, &EsAccountVotes{
+		BaseEsType:   &BaseEsType{Id: "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA"},
+		BlockNo:      104524962,
+		Timestamp:    time.Unix(0, 1668652376002288214),
+		Candidates:   []string{"16Uiu2HAmGiJ2QgVAWHMUtzLKKNM5eFUJ3Ds3FN7nYJq1mHN5ZPj9"},
+		Amount:       "20000000000000000000000",
+		AmountFloat:  20000,
+		Staking:      "20000000000000000000000",
+		StakingFloat: 20000,
+	})
+}
+
+func TestNameStateHistory(t *testing.T) {
+	history := func(blockNo uint64, txId, operation, destination string) *EsNameHistory {
+		return ConvNameHistory(&EsTx{BaseEsType: &BaseEsType{Id: txId}, BlockNo: blockNo, Method: operation}, destination, destination)
+	}
+	fn_test := func(state *EsNameState, blockNo uint64, expectDestination string, expectOk bool) {
+		destination, ok := state.DestinationAt(blockNo)
+		require.Equal(t, expectOk, ok)
+		require.Equal(t, expectDestination, destination)
+	}
+
+	state := NewNameState("testnametest")
+	state.ApplyHistory(history(200, "tx2", "v1updatename", "AmMK3LZiR1oEf66xzXir7mA5SUVVHSinWUYmh5FwueoVmciH3CuJ"))
+	state.ApplyHistory(history(100, "tx1", "v1createname", "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA"))
+	state.ApplyHistory(history(200, "tx2", "v1updatename", "AmMK3LZiR1oEf66xzXir7mA5SUVVHSinWUYmh5FwueoVmciH3CuJ"))
+	require.Len(t, state.History, 2)
+	require.Equal(t, uint64(100), state.CreatedBlock)
+	require.Equal(t, uint64(200), state.BlockNo)
+	require.Equal(t, "tx2", state.UpdateTx)
+	require.Equal(t, "AmMK3LZiR1oEf66xzXir7mA5SUVVHSinWUYmh5FwueoVmciH3CuJ", state.Destination)
+
+	fn_test(state, 99, "", false)
+	fn_test(state, 100, "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA", true)
+	fn_test(state, 199, "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA", true)
+	fn_test(state, 200, "AmMK3LZiR1oEf66xzXir7mA5SUVVHSinWUYmh5FwueoVmciH3CuJ", true)
+
+	state.RollbackHistory(150)
+	require.Len(t, state.History, 1)
+	require.Equal(t, "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA", state.Destination)
+	fn_test(state, 200, "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA", true)
+
+	state.RollbackHistory(100)
+	require.Len(t, state.History, 0)
+	require.Equal(t, uint64(0), state.BlockNo)
+}
+
+func TestConvEnterpriseHistory(t *testing.T) {
+	fn_test := func(txDoc *EsTx, payload *tx.EnterprisePayload, progress *types.ConfChangeProgress, esHistoryExpect *EsEnterpriseHistory) {
+		esHistoryConv := ConvEnterpriseHistory(txDoc, payload, progress)
+		require.Equal(t, esHistoryExpect, esHistoryConv)
+	}
+
+	txDoc := &EsTx{
+		BaseEsType: &BaseEsType{Id: "8Zj68cFzrzUtwPe6kZF8qPgVp9LbsefjdTsi4C3hVY8"},
+		BlockNo:    1000,
+		Timestamp:  time.Unix(0, 1668652376002288214),
+		Account:    "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA",
+		Status:     "SUCCESS",
+	}
+	fn_test(txDoc, &tx.EnterprisePayload{
+		Name:   tx.EnterpriseAppendConf,
+		Key:    "P2PWHITE",
+		Values: []string{"16Uiu2HAmGiJ2QgVAWHMUtzLKKNM5eFUJ3Ds3FN7nYJq1mHN5ZPj9"},
+	}, nil, &EsEnterpriseHistory{
+		BaseEsType: &BaseEsType{Id: "8Zj68cFzrzUtwPe6kZF8qPgVp9LbsefjdTsi4C3hVY8"},
+		BlockNo:    1000,
+		Timestamp:  time.Unix(0, 1668652376002288214),
+		Sender:     "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA",
+		Operation:  tx.EnterpriseAppendConf,
+		Status:     "SUCCESS",
+		Key:        "P2PWHITE",
+		Values:     []string{"16Uiu2HAmGiJ2QgVAWHMUtzLKKNM5eFUJ3Ds3FN7nYJq1mHN5ZPj9"},
+	})
+	fn_test(txDoc, &tx.EnterprisePayload{
+		Name:    tx.EnterpriseChangeCluster,
+		Cluster: &tx.ClusterChange{Command: "remove", Name: "aergo4", ID: "aebe0b6ae1d8a39b"},
+	}, &types.ConfChangeProgress{
+		State:   types.ConfChangeState_CONF_CHANGE_STATE_APPLIED,
+		Members: []*types.MemberAttr{{Name: "aergo1"}, {Name: "aergo2"}, {Name: "aergo3"}},
+	}, &EsEnterpriseHistory{
+		BaseEsType:     &BaseEsType{Id: "8Zj68cFzrzUtwPe6kZF8qPgVp9LbsefjdTsi4C3hVY8"},
+		BlockNo:        1000,
+		Timestamp:      time.Unix(0, 1668652376002288214),
+		Sender:         "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA",
+		Operation:      tx.EnterpriseChangeCluster,
+		Status:         "SUCCESS",
+		ClusterCommand: "remove",
+		ClusterName:    "aergo4",
+		ClusterId:      "aebe0b6ae1d8a39b",
+		ClusterState:   "CONF_CHANGE_STATE_APPLIED",
+		ClusterMembers: []string{"aergo1", "aergo2", "aergo3"},
+	})
+}
+
+func TestEnterpriseConfHistory(t *testing.T) {
+	history := func(blockNo uint64, operation string, key string, status string, enable bool, values ...string) *EsEnterpriseHistory {
+		return &EsEnterpriseHistory{
+			BaseEsType: &BaseEsType{Id: fmt.Sprintf("tx%d", blockNo)},
+			BlockNo:    blockNo,
+			Operation:  operation,
+			Status:     status,
+			Key:        key,
+			Values:     values,
+			Enable:     enable,
+		}
+	}
+	fn_test := func(conf *EsEnterpriseConf, historyDoc *EsEnterpriseHistory, expectApplied bool, expectOn bool, expectValues ...string) {
+		require.Equal(t, expectApplied, conf.ApplyHistory(historyDoc))
+		require.Equal(t, expectOn, conf.On)
+		require.ElementsMatch(t, expectValues, conf.Values)
+	}
+
+	conf := NewEnterpriseConf("P2PWHITE")
+	fn_test(conf, history(10, tx.EnterpriseAppendConf, "P2PWHITE", "SUCCESS", false, "peer1"), true, false, "peer1")
+	fn_test(conf, history(11, tx.EnterpriseAppendConf, "P2PWHITE", "SUCCESS", false, "peer2", "peer1"), true, false, "peer1", "peer2")
+	fn_test(conf, history(12, tx.EnterpriseEnableConf, "P2PWHITE", "SUCCESS", true), true, true, "peer1", "peer2")
+	fn_test(conf, history(13, tx.EnterpriseRemoveConf, "P2PWHITE", "ERROR", false, "peer1"), false, true, "peer1", "peer2")
+	fn_test(conf, history(14, tx.EnterpriseRemoveConf, "P2PWHITE", "SUCCESS", false, "peer1"), true, true, "peer2")
+	fn_test(conf, history(15, tx.EnterpriseSetConf, "P2PWHITE", "SUCCESS", false, "peer3", "peer4"), true, true, "peer3", "peer4")
+	fn_test(conf, history(16, tx.EnterpriseSetConf, "ACCOUNTWHITE", "SUCCESS", false, "account1"), false, true, "peer3", "peer4")
+	require.Equal(t, uint64(15), conf.BlockNo)
+	require.Equal(t, "tx15", conf.UpdateTx)
+
+	admins := NewEnterpriseConf(tx.EnterpriseAdmins)
+	fn_test(admins, history(20, tx.EnterpriseAppendAdmin, tx.EnterpriseAdmins, "SUCCESS", false, "admin1"), true, true, "admin1")
+	fn_test(admins, history(21, tx.EnterpriseRemoveAdmin, tx.EnterpriseAdmins, "SUCCESS", false, "admin1"), true, false)
+}
+
+func TestConvRaftMember(t *testing.T) {
+	fn_test := func(blockDoc *EsBlock, event string, member tx.ConsensusBp, leader string, members []tx.ConsensusBp, updateTx string, esRaftMemberExpect *EsRaftMember) {
+		esRaftMemberConv := ConvRaftMember(blockDoc, event, member, leader, members, updateTx)
+		require.Equal(t, esRaftMemberExpect, esRaftMemberConv)
+	}
+
+	aergo1 := tx.ConsensusBp{Name: "aergo1", RaftID: "aebe0b6ae1d8a39b", PeerID: "16Uiu2HAmGiJ2QgVAWHMUtzLKKNM5eFUJ3Ds3FN7nYJq1mHN5ZPj9", Addr: "/ip4/127.0.0.1/tcp/11001"}
+	aergo2 := tx.ConsensusBp{Name: "aergo2", RaftID: "b1e2a7c1d2e3f4a5", PeerID: "16Uiu2HAkvJTHFuJXxr15rFEHsJWnyn1QvGatW2E9ED9Mvy4HWjVF", Addr: "/ip4/127.0.0.1/tcp/11002"}
+	blockDoc := &EsBlock{
+		BlockNo:   1000,
+		Timestamp: time.Unix(0, 1668652376002288214),
+	}
+
+	fn_test(blockDoc, "add", aergo2, "aergo1", []tx.ConsensusBp{aergo1, aergo2}, "8Zj68cFzrzUtwPe6kZF8qPgVp9LbsefjdTsi4C3hVY8", &EsRaftMember{
+		BaseEsType:  &BaseEsType{Id: "1000-add-16Uiu2HAkvJTHFuJXxr15rFEHsJWnyn1QvGatW2E9ED9Mvy4HWjVF"},
+		BlockNo:     1000,
+		Timestamp:   time.Unix(0, 1668652376002288214),
+		Event:       "add",
+		Name:        "aergo2",
+		RaftId:      "b1e2a7c1d2e3f4a5",
+		PeerId:      "16Uiu2HAkvJTHFuJXxr15rFEHsJWnyn1QvGatW2E9ED9Mvy4HWjVF",
+		Address:     "/ip4/127.0.0.1/tcp/11002",
+		Leader:      "aergo1",
+		Members:     []string{"16Uiu2HAkvJTHFuJXxr15rFEHsJWnyn1QvGatW2E9ED9Mvy4HWjVF", "16Uiu2HAmGiJ2QgVAWHMUtzLKKNM5eFUJ3Ds3FN7nYJq1mHN5ZPj9"},
+		MemberNames: []string{"aergo2", "aergo1"},
+		UpdateTx:    "8Zj68cFzrzUtwPe6kZF8qPgVp9LbsefjdTsi4C3hVY8",
+	})
+	fn_test(blockDoc, "leader", tx.ConsensusBp{}, "aergo2", []tx.ConsensusBp{aergo1}, "", &EsRaftMember{
+		BaseEsType:  &BaseEsType{Id: "1000-leader-aergo2"},
+		BlockNo:     1000,
+		Timestamp:   time.Unix(0, 1668652376002288214),
+		Event:       "leader",
+		Leader:      "aergo2",
+		Members:     []string{"16Uiu2HAmGiJ2QgVAWHMUtzLKKNM5eFUJ3Ds3FN7nYJq1mHN5ZPj9"},
+		MemberNames: []string{"aergo1"},
+	})
+}
+
+func TestConvContractAbi(t *testing.T) {
+	fn_test := func(txDoc *EsTx, contractAddress string, abi *types.ABI, esAbiExpect *EsContractAbi) {
+		esAbiConv := ConvContractAbi(txDoc, contractAddress, abi)
+		require.Equal(t, esAbiExpect, esAbiConv)
+	}
+
+	fn_test(&EsTx{
+		BaseEsType: &BaseEsType{Id: "8Zj68cFzrzUtwPe6kZF8qPgVp9LbsefjdTsi4C3hVY8"},
+		BlockNo:    1000,
+		Timestamp:  time.Unix(0, 1668652376002288214),
+	}, "Amg5KQVkBcX1rR1nmKFPyZPnU8CeGWnZkqAiqp3v4fgSL6KmcCuF", &types.ABI{
+		Version:  "0.2",
+		Language: "lua",
+		Functions: []*types.Function{
+			{Name: "transfer", Arguments: []*types.FnArgument{{Name: "to"}, {Name: "amount"}, {Name: "..."}}},
+			{Name: "balanceOf", Arguments: []*types.FnArgument{{Name: "owner"}}, View: true},
+			{Name: "default", Payable: true, FeeDelegation: true},
+		},
+		StateVariables: []*types.StateVar{
+			{Name: "_balances", Type: "map"},
+			{Name: "_totalSupply", Type: "value"},
+		},
+	}, &EsContractAbi{
+		BaseEsType: &BaseEsType{Id: "Amg5KQVkBcX1rR1nmKFPyZPnU8CeGWnZkqAiqp3v4fgSL6KmcCuF-8Zj68cFzrzUtwPe6kZF8qPgVp9LbsefjdTsi4C3hVY8"},
+		Contract:   "Amg5KQVkBcX1rR1nmKFPyZPnU8CeGWnZkqAiqp3v4fgSL6KmcCuF",
+		TxId:       "8Zj68cFzrzUtwPe6kZF8qPgVp9LbsefjdTsi4C3hVY8",
+		BlockNo:    1000,
+		Timestamp:  time.Unix(0, 1668652376002288214),
+		Language:   "lua",
+		Version:    "0.2",
+		Functions: []*EsAbiFunction{
+			{Name: "transfer", Arguments: []string{"to", "amount", "..."}},
+			{Name: "balanceOf", Arguments: []string{"owner"}, View: true},
+			{Name: "default", Arguments: []string{}, Payable: true, FeeDelegation: true},
+		},
+		StateVariables: []*EsAbiStateVar{
+			{Name: "_balances", Type: "map"},
+			{Name: "_totalSupply", Type: "value"},
+		},
+	})
+}
@@ -1,10 +1,12 @@
 package documents
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/aergoio/aergo-indexer-2.0/indexer/metadata"
 	tx "github.com/aergoio/aergo-indexer-2.0/indexer/transaction"
 )
 
@@ -41,41 +43,58 @@ type EsChainInfo struct {
 // EsBlock is a block stored in the database
 type EsBlock struct {
 	*BaseEsType
-	Timestamp     time.Time `json:"ts" db:"ts"`
-	BlockNo       uint64    `json:"no" db:"no"`
-	PreviousBlock string    `json:"previous_block" db:"previous_block"`
-	TxCount       uint64    `json:"txs" db:"txs"`
-	Size          uint64    `json:"size" db:"size"`
-	Coinbase      string    `json:"coinbase" db:"coinbase"`
-	BlockProducer string    `json:"block_producer" db:"block_producer"`
-	RewardAccount string    `json:"reward_account" db:"reward_account"`
-	RewardAmount  string    `json:"reward_amount" db:"reward_amount"`
+	Timestamp         time.Time `json:"ts" db:"ts"`
+	BlockNo           uint64    `json:"no" db:"no"`
+	PreviousBlock     string    `json:"previous_block" db:"previous_block"`
+	TxCount           uint64    `json:"txs" db:"txs"`
+	Size              uint64    `json:"size" db:"size"`
+	Coinbase          string    `json:"coinbase" db:"coinbase"`
+	BlockProducer     string    `json:"block_producer" db:"block_producer"`
+	RewardAccount     string    `json:"reward_account" db:"reward_account"`
+	RewardAmount      string    `json:"reward_amount" db:"reward_amount"`             // string of BigInt
+	RewardAmountFloat float32   `json:"reward_amount_float" db:"reward_amount_float"` // float for sorting
+	RewardSource      string    `json:"reward_source" db:"reward_source"`             // param or state
+}
+
+// EsBlockUpReward is a block reward update
+type EsBlockUpReward struct {
+	*BaseEsType
+	RewardAmount      string  `json:"reward_amount" db:"reward_amount"`
+	RewardAmountFloat float32 `json:"reward_amount_float" db:"reward_amount_float"`
+	RewardSource      string  `json:"reward_source" db:"reward_source"`
 }
 
 // EsTx is a transaction stored in the database
 type EsTx struct {
 	*BaseEsType
-	BlockNo       uint64        `json:"blockno" db:"blockno"`
-	BlockId       string        `json:"block_id" db:"block_id"`
-	Timestamp     time.Time     `json:"ts" db:"ts"`
-	TxIdx         uint64        `json:"tx_idx" db:"tx_idx"`
-	Payload       string        `json:"payload" db:"payload"`
-	Account       string        `json:"from" db:"from"`
-	Recipient     string        `json:"to" db:"to"`
-	Amount        string        `json:"amount" db:"amount"`             // string of BigInt
-	AmountFloat   float32       `json:"amount_float" db:"amount_float"` // float for sorting
-	Type          uint64        `json:"type" db:"type"`
-	Category      tx.TxCategory `json:"category" db:"category"`
-	Method        string        `json:"method" db:"method"`
-	Status        string        `json:"status" db:"status"`
-	Result        string        `json:"result" db:"result"`
-	Contract      string        `json:"contract" db:"contract"`
-	Nonce         uint64        `json:"nonce" db:"nonce"`
-	FeeDelegation bool          `json:"fee_delegation" db:"fee_delegation"`
-	GasPrice      string        `json:"gas_price" db:"gas_price"`
-	GasLimit      uint64        `json:"gas_limit" db:"gas_limit"`
-	GasUsed       uint64        `json:"gas_used" db:"gas_used"`
-	FeeUsed       string        `json:"fee_used" db:"fee_used"`
+	BlockNo       uint64           `json:"blockno" db:"blockno"`
+	BlockId       string           `json:"block_id" db:"block_id"`
+	Timestamp     time.Time        `json:"ts" db:"ts"`
+	TxIdx         uint64           `json:"tx_idx" db:"tx_idx"`
+	Payload       string           `json:"payload" db:"payload"`
+	Account       string           `json:"from" db:"from"`
+	Recipient     string           `json:"to" db:"to"`
+	RecipientAddr string           `json:"to_address" db:"to_address"`       // recipient resolved as of the block when it is a name
+	Amount        string           `json:"amount" db:"amount"`               // string of BigInt
+	AmountFloat   float32          `json:"amount_float" db:"amount_float"`   // float for sorting
+	AmountPadded  string           `json:"amount_padded" db:"amount_padded"` // padded amount for exact range queries and sorting
+	Type          uint64           `json:"type" db:"type"`
+	Category      tx.TxCategory    `json:"category" db:"category"`
+	Method        string           `json:"method" db:"method"`
+	Args          []tx.Arg         `json:"args" db:"args"`
+	Ops           []tx.MulticallOp `json:"ops" db:"ops"` // commands of multicall script
+	Status        string           `json:"status" db:"status"`
+	Result        string           `json:"result" db:"result"`
+	Contract      string           `json:"contract" db:"contract"`
+	Nonce         uint64           `json:"nonce" db:"nonce"`
+	FeeDelegation bool             `json:"fee_delegation" db:"fee_delegation"`
+	GasPrice      string           `json:"gas_price" db:"gas_price"`
+	GasLimit      uint64           `json:"gas_limit" db:"gas_limit"`
+	GasUsed       uint64           `json:"gas_used" db:"gas_used"`
+	FeeUsed       string           `json:"fee_used" db:"fee_used"`
+	FeeUsedFloat  float32          `json:"fee_used_float" db:"fee_used_float"` // float for sorting
+	FeePayer      string           `json:"fee_payer" db:"fee_payer"`           // contract which pays the fee if fee delegation, otherwise sender
+	FeeMismatch   bool             `json:"fee_mismatch" db:"fee_mismatch"`     // fee_used differs from gas_used * gas_price
 }
 
 type EsContract struct {
@@ -86,30 +105,83 @@ type EsContract struct {
 	Timestamp time.Time `json:"ts" db:"ts"`
 	Payload   string    `json:"payload" db:"payload"`
 
-	VerifiedStatus string `json:"verified_status" db:"verified_status"`
-	VerifiedToken  string `json:"verified_token" db:"verified_token"`
-	CodeUrl        string `json:"code_url" db:"code_url"`
-	Code           string `json:"code" db:"code"`
+	// current version
+	VersionTx      string `json:"version_tx" db:"version_tx"`
+	VersionBlockNo uint64 `json:"version_blockno" db:"version_blockno"`
+	PayloadHash    string `json:"payload_hash" db:"payload_hash"`
+	CodeHash       string `json:"code_hash" db:"code_hash"`
+
+	// detected token standard
+	TokenType       string   `json:"token_type" db:"token_type"`
+	Interfaces      []string `json:"interfaces" db:"interfaces"`
+	TokenConfidence float32  `json:"token_confidence" db:"token_confidence"`
+
+	VerifiedStatus   string `json:"verified_status" db:"verified_status"`
+	VerifiedToken    string `json:"verified_token" db:"verified_token"`
+	VerifiedCompiler string `json:"verified_compiler" db:"verified_compiler"`
+	VerifiedReason   string `json:"verified_reason" db:"verified_reason"`
+	CodeUrl          string `json:"code_url" db:"code_url"`
+	Code             string `json:"code" db:"code"`
 }
 
 type EsContractUp struct {
 	*BaseEsType
-	VerifiedStatus string `json:"verified_status" db:"verified_status"`
-	VerifiedToken  string `json:"verified_token" db:"verified_token"`
-	CodeUrl        string `json:"code_url" db:"code_url"`
-	Code           string `json:"code" db:"code"`
+	VerifiedStatus   string `json:"verified_status" db:"verified_status"`
+	VerifiedToken    string `json:"verified_token" db:"verified_token"`
+	VerifiedCompiler string `json:"verified_compiler" db:"verified_compiler"`
+	VerifiedReason   string `json:"verified_reason" db:"verified_reason"`
+	CodeUrl          string `json:"code_url" db:"code_url"`
+	Code             string `json:"code" db:"code"`
+}
+
+// EsContractUpVersion updates the current version of a contract
+type EsContractUpVersion struct {
+	*BaseEsType
+	Payload        string `json:"payload" db:"payload"`
+	VersionTx      string `json:"version_tx" db:"version_tx"`
+	VersionBlockNo uint64 `json:"version_blockno" db:"version_blockno"`
+	PayloadHash    string `json:"payload_hash" db:"payload_hash"`
+	CodeHash       string `json:"code_hash" db:"code_hash"`
+}
+
+// EsContractVersion is a deploy or redeploy of a contract. The id is contract address + tx hash.
+type EsContractVersion struct {
+	*BaseEsType
+	Contract     string        `json:"contract" db:"contract"`
+	TxId         string        `json:"tx_id" db:"tx_id"`
+	BlockNo      uint64        `json:"blockno" db:"blockno"`
+	Timestamp    time.Time     `json:"ts" db:"ts"`
+	Deployer     string        `json:"deployer" db:"deployer"`
+	Category     tx.TxCategory `json:"category" db:"category"` // deploy or redeploy
+	Payload      string        `json:"payload" db:"payload"`
+	PayloadHash  string        `json:"payload_hash" db:"payload_hash"`
+	CodeHash     string        `json:"code_hash" db:"code_hash"`
+	AbiId        string        `json:"abi_id" db:"abi_id"` // id of contract_abi
+	AbiFunctions []string      `json:"abi_functions" db:"abi_functions"`
 }
 
 // EsEvent is a contract-event mapping stored in the database
 type EsEvent struct {
 	*BaseEsType
-	Contract  string `json:"contract" db:"contract"`
-	BlockNo   uint64 `json:"blockno" db:"blockno"`
-	TxId      string `json:"tx_id" db:"tx_id"`
-	TxIdx     uint64 `json:"tx_idx" db:"tx_idx"`
-	EventIdx  uint64 `json:"event_idx" db:"event_idx"`
-	EventName string `json:"event_name" db:"event_name"`
-	EventArgs string `json:"event_args" db:"event_args"`
+	Contract  string                  `json:"contract" db:"contract"`
+	BlockNo   uint64                  `json:"blockno" db:"blockno"`
+	TxId      string                  `json:"tx_id" db:"tx_id"`
+	TxIdx     uint64                  `json:"tx_idx" db:"tx_idx"`
+	EventIdx  uint64                  `json:"event_idx" db:"event_idx"`
+	EventName string                  `json:"event_name" db:"event_name"`
+	EventArgs string                  `json:"event_args" db:"event_args"`
+	Args      map[string]*tx.EventArg `json:"args" db:"args"` // leading arguments by position, e.g. args.0.addr
+}
+
+// EsEventIndex is an event document of custom event index, which fields are declared by config
+type EsEventIndex struct {
+	*BaseEsType
+	Fields map[string]interface{} `db:"fields"`
+}
+
+// MarshalJSON encodes the fields as the document
+func (e *EsEventIndex) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.Fields)
 }
 
 // EsName is a name-address mapping stored in the database
@@ -135,8 +207,9 @@ type EsToken struct {
 	Decimals     uint8        `json:"decimals" db:"decimals"`
 
 	// update values
-	Supply      string  `json:"supply" db:"supply"`
-	SupplyFloat float32 `json:"supply_float" db:"supply_float"`
+	Supply       string  `json:"supply" db:"supply"`
+	SupplyFloat  float32 `json:"supply_float" db:"supply_float"`
+	SupplyPadded string  `json:"supply_padded" db:"supply_padded"` // padded supply in 10^-18, sortable across tokens
 
 	// verified values
 	VerifiedStatus string `json:"verified_status" db:"verified_status"`
@@ -148,12 +221,33 @@ type EsToken struct {
 	ImageUrl       string `json:"image_url" db:"image_url"`
 	HomepageUrl    string `json:"homepage_url" db:"homepage_url"`
 	TotalTransfer  uint64 `json:"total_transfer" db:"total_transfer"`
+
+	// holder values
+	HolderCount     uint64           `json:"holder_count" db:"holder_count"`
+	TopHolders      []*EsTokenHolder `json:"top_holders" db:"top_holders"`
+	TopHoldersShare float32          `json:"top_holders_share" db:"top_holders_share"`
 }
 
 type EsTokenUpSupply struct {
 	*BaseEsType
-	Supply      string  `json:"supply" db:"supply"`
-	SupplyFloat float32 `json:"supply_float" db:"supply_float"`
+	Supply       string  `json:"supply" db:"supply"`
+	SupplyFloat  float32 `json:"supply_float" db:"supply_float"`
+	SupplyPadded string  `json:"supply_padded" db:"supply_padded"`
+}
+
+// EsTokenHolder is an account among the top holders of a token
+type EsTokenHolder struct {
+	Account      string  `json:"account" db:"account"`
+	Balance      string  `json:"balance" db:"balance"`
+	BalanceFloat float32 `json:"balance_float" db:"balance_float"`
+	Share        float32 `json:"share" db:"share"`
+}
+
+type EsTokenUpHolders struct {
+	*BaseEsType
+	HolderCount     uint64           `json:"holder_count" db:"holder_count"`
+	TopHolders      []*EsTokenHolder `json:"top_holders" db:"top_holders"`
+	TopHoldersShare float32          `json:"top_holders_share" db:"top_holders_share"`
 }
 
 type EsTokenUpVerified struct {
@@ -172,27 +266,106 @@ type EsTokenUpVerified struct {
 // EsTokenTransfer is a transfer of a token
 type EsTokenTransfer struct {
 	*BaseEsType
-	TxId         string    `json:"tx_id" db:"tx_id"`
-	Timestamp    time.Time `json:"ts" db:"ts"`
-	BlockNo      uint64    `json:"blockno" db:"blockno"`
-	TokenAddress string    `json:"address" db:"address"`
-	From         string    `json:"from" db:"from"`
-	To           string    `json:"to" db:"to"`
-	Sender       string    `json:"sender" db:"sender"`
-	Amount       string    `json:"amount" db:"amount"`             // string of BigInt
-	AmountFloat  float32   `json:"amount_float" db:"amount_float"` // float for sorting
-	TokenId      string    `json:"token_id" db:"token_id"`
+	TxId           string    `json:"tx_id" db:"tx_id"`
+	Timestamp      time.Time `json:"ts" db:"ts"`
+	BlockNo        uint64    `json:"blockno" db:"blockno"`
+	TokenAddress   string    `json:"address" db:"address"`
+	From           string    `json:"from" db:"from"`
+	To             string    `json:"to" db:"to"`
+	Sender         string    `json:"sender" db:"sender"`
+	Method         string    `json:"method" db:"method"`                   // called method which caused the transfer
+	Amount         string    `json:"amount" db:"amount"`                   // string of BigInt
+	AmountFloat    float32   `json:"amount_float" db:"amount_float"`       // float for sorting
+	AmountAdjusted float64   `json:"amount_adjusted" db:"amount_adjusted"` // amount / 10^decimals
+	AmountPadded   string    `json:"amount_padded" db:"amount_padded"`     // padded amount in 10^-18, sortable across tokens
+	TokenId        string    `json:"token_id" db:"token_id"`
+}
+
+// EsTokenStats is the transfer activity of a token in an hour or a day. The id is address-period-start.
+type EsTokenStats struct {
+	*BaseEsType
+	TokenAddress    string      `json:"address" db:"address"`
+	Period          StatsPeriod `json:"period" db:"period"`
+	Timestamp       time.Time   `json:"ts" db:"ts"` // start of period
+	TransferCount   uint64      `json:"transfer_count" db:"transfer_count"`
+	Senders         uint64      `json:"senders" db:"senders"`
+	Receivers       uint64      `json:"receivers" db:"receivers"`
+	Volume          string      `json:"volume" db:"volume"`             // string of BigInt
+	VolumeFloat     float64     `json:"volume_float" db:"volume_float"` // decimal-adjusted
+	MintCount       uint64      `json:"mint_count" db:"mint_count"`
+	MintAmount      string      `json:"mint_amount" db:"mint_amount"`
+	MintAmountFloat float64     `json:"mint_amount_float" db:"mint_amount_float"`
+	BurnCount       uint64      `json:"burn_count" db:"burn_count"`
+	BurnAmount      string      `json:"burn_amount" db:"burn_amount"`
+	BurnAmountFloat float64     `json:"burn_amount_float" db:"burn_amount_float"`
+}
+
+// EsChainStats is the chain activity in an hour or a day. The id is chain-period-start.
+type EsChainStats struct {
+	*BaseEsType
+	Period          StatsPeriod        `json:"period" db:"period"`
+	Timestamp       time.Time          `json:"ts" db:"ts"` // start of period
+	BlockCount      uint64             `json:"block_count" db:"block_count"`
+	TxCount         uint64             `json:"tx_count" db:"tx_count"`
+	TxCategories    []*EsCategoryCount `json:"tx_categories" db:"tx_categories"`
+	ActiveAccounts  uint64             `json:"active_accounts" db:"active_accounts"` // unique tx senders
+	NewAccounts     uint64             `json:"new_accounts" db:"new_accounts"`       // accounts which sent or received the first tx
+	NewContracts    uint64             `json:"new_contracts" db:"new_contracts"`
+	NewTokens       uint64             `json:"new_tokens" db:"new_tokens"`
+	GasUsed         uint64             `json:"gas_used" db:"gas_used"`
+	FeesBurned      string             `json:"fees_burned" db:"fees_burned"`             // string of BigInt
+	FeesBurnedFloat float64            `json:"fees_burned_float" db:"fees_burned_float"` // in aergo
+}
+
+type EsCategoryCount struct {
+	Category tx.TxCategory `json:"category" db:"category"`
+	Count    uint64        `json:"count" db:"count"`
+}
+
+// EsFeeSponsor is the fee a contract paid by fee delegation for the txs of an account calling a method. The id is contract-account-method.
+// The total of the contract has the contract as id, with empty account and method.
+type EsFeeSponsor struct {
+	*BaseEsType
+	Contract  string    `json:"contract" db:"contract"`
+	Account   string    `json:"account" db:"account"`
+	Method    string    `json:"method" db:"method"`
+	TxCount   uint64    `json:"tx_count" db:"tx_count"`
+	GasUsed   uint64    `json:"gas_used" db:"gas_used"`
+	Fee       string    `json:"fee" db:"fee"`             // string of BigInt
+	FeeFloat  float64   `json:"fee_float" db:"fee_float"` // in aergo
+	LastBlock uint64    `json:"blockno" db:"blockno"`     // block of the last sponsored tx
+	LastTs    time.Time `json:"ts" db:"ts"`
+}
+
+// EsBpStats is the block production of a block producer. The id is the peer id.
+type EsBpStats struct {
+	*BaseEsType
+	Bp          string    `json:"bp" db:"bp"`
+	Blocks      uint64    `json:"blocks" db:"blocks"`
+	FirstBlock  uint64    `json:"first_block" db:"first_block"`
+	FirstTs     time.Time `json:"first_ts" db:"first_ts"`
+	LastBlock   uint64    `json:"last_block" db:"last_block"`
+	LastTs      time.Time `json:"last_ts" db:"last_ts"`
+	TotalSize   uint64    `json:"total_size" db:"total_size"`
+	TotalTxs    uint64    `json:"total_txs" db:"total_txs"`
+	AvgSize     float64   `json:"avg_size" db:"avg_size"`
+	AvgTxs      float64   `json:"avg_txs" db:"avg_txs"`
+	Reward      string    `json:"reward" db:"reward"`             // string of BigInt
+	RewardFloat float32   `json:"reward_float" db:"reward_float"` // float for sorting
+	MissedSlots uint64    `json:"missed_slots" db:"missed_slots"` // slots of the bp without a block
 }
 
 // EsAccountTokens is meta data of a token of an account. The id is account_token address.
 type EsAccountTokens struct {
 	*BaseEsType
-	Account      string       `json:"account" db:"account"`
-	TokenAddress string       `json:"address" db:"address"`
-	Type         tx.TokenType `json:"type" db:"type"`
-	Timestamp    time.Time    `json:"ts" db:"ts"`
-	Balance      string       `json:"balance" db:"balance"`
-	BalanceFloat float32      `json:"balance_float" db:"balance_float"`
+	Account         string       `json:"account" db:"account"`
+	TokenAddress    string       `json:"address" db:"address"`
+	Type            tx.TokenType `json:"type" db:"type"`
+	Timestamp       time.Time    `json:"ts" db:"ts"`
+	Balance         string       `json:"balance" db:"balance"`
+	BalanceFloat    float32      `json:"balance_float" db:"balance_float"`
+	BalanceAdjusted float64      `json:"balance_adjusted" db:"balance_adjusted"` // balance / 10^decimals
+	BalancePadded   string       `json:"balance_padded" db:"balance_padded"`     // padded balance in 10^-18, sortable across tokens
 }
 
 type EsAccountTokensUp struct {
@@ -205,12 +378,14 @@ type EsAccountTokensUp struct {
 // EsAccountBalance is meta data of a balance of an account. The id is account_balance address.
 type EsAccountBalance struct {
 	*BaseEsType
-	BlockNo      uint64    `json:"blockno" db:"blockno"`
-	Timestamp    time.Time `json:"ts" db:"ts"`
-	Balance      string    `json:"balance" db:"balance"`
-	BalanceFloat float32   `json:"balance_float" db:"balance_float"`
-	Staking      string    `json:"staking" db:"staking"`
-	StakingFloat float32   `json:"staking_float" db:"staking_float"`
+	BlockNo       uint64    `json:"blockno" db:"blockno"`
+	Timestamp     time.Time `json:"ts" db:"ts"`
+	Balance       string    `json:"balance" db:"balance"`
+	BalanceFloat  float32   `json:"balance_float" db:"balance_float"`
+	BalancePadded string    `json:"balance_padded" db:"balance_padded"` // padded balance for exact range queries and sorting
+	Staking       string    `json:"staking" db:"staking"`
+	StakingFloat  float32   `json:"staking_float" db:"staking_float"`
+	StakingPadded string    `json:"staking_padded" db:"staking_padded"`
 }
 
 type EsNFT struct {
@@ -222,13 +397,52 @@ type EsNFT struct {
 	Timestamp    time.Time `json:"ts" db:"ts"`
 	TokenUri     string    `json:"token_uri" db:"token_uri"`
 	ImageUrl     string    `json:"image_url" db:"image_url"`
+
+	// off-chain metadata of token uri
+	*EsNFTMetadata
+}
+
+// EsNFTMetadata is the off-chain metadata of nft, fetched from token uri
+type EsNFTMetadata struct {
+	*BaseEsType   `json:"-"`
+	Name          string               `json:"name,omitempty" db:"name"`
+	Description   string               `json:"description,omitempty" db:"description"`
+	MetadataImage string               `json:"metadata_image,omitempty" db:"metadata_image"`
+	Attributes    []metadata.Attribute `json:"attributes,omitempty" db:"attributes"`
+	MetadataHash  string               `json:"metadata_hash,omitempty" db:"metadata_hash"`
+	MetadataError string               `json:"metadata_error" db:"metadata_error"`
+	MetadataTs    time.Time            `json:"metadata_ts" db:"metadata_ts"`
+}
+
+// EsNFTHistory is a mint, transfer or burn of a nft. The id is tx hash + event index.
+type EsNFTHistory struct {
+	*BaseEsType
+	NftId        string    `json:"nft_id" db:"nft_id"`
+	TokenAddress string    `json:"address" db:"address"`
+	TokenId      string    `json:"token_id" db:"token_id"`
+	Event        string    `json:"event" db:"event"`
+	From         string    `json:"from" db:"from"`
+	To           string    `json:"to" db:"to"`
+	TxId         string    `json:"tx_id" db:"tx_id"`
+	BlockNo      uint64    `json:"blockno" db:"blockno"`
+	Timestamp    time.Time `json:"ts" db:"ts"`
+	Order        uint64    `json:"order" db:"order"` // sort key of block number, tx index and event index
 }
 
+// EsNFTUp is a partial update of nft, which keeps the off-chain metadata
 type EsNFTUp struct {
 	*BaseEsType
 	Account   string    `json:"account" db:"account"`
 	BlockNo   uint64    `json:"blockno" db:"blockno"`
 	Timestamp time.Time `json:"ts" db:"ts"`
+	TokenUri  string    `json:"token_uri" db:"token_uri"`
+	ImageUrl  string    `json:"image_url" db:"image_url"`
+}
+
+// EsNFTOwnerUp is a partial update of the owner of nft only
+type EsNFTOwnerUp struct {
+	*BaseEsType
+	Account string `json:"account" db:"account"`
 }
 
 type EsWhitelist struct {
@@ -237,6 +451,155 @@ type EsWhitelist struct {
 	Type     string `json:"type" db:"type"`
 }
 
+// EsBpVotes is a snapshot of the vote tally of a block producer candidate. The id is blockno + candidate.
+type EsBpVotes struct {
+	*BaseEsType
+	BlockNo    uint64    `json:"blockno" db:"blockno"`
+	Timestamp  time.Time `json:"ts" db:"ts"`
+	Candidate  string    `json:"candidate" db:"candidate"`
+	Votes      string    `json:"votes" db:"votes"`             // string of BigInt
+	VotesFloat float32   `json:"votes_float" db:"votes_float"` // float for sorting
+	Rank       uint64    `json:"rank" db:"rank"`
+	Active     bool      `json:"active" db:"active"`
+}
+
+// EsBpChange is a change of the active block producer set. The id is blockno.
+type EsBpChange struct {
+	*BaseEsType
+	BlockNo   uint64    `json:"blockno" db:"blockno"`
+	Timestamp time.Time `json:"ts" db:"ts"`
+	Bps       []string  `json:"bps" db:"bps"`
+	Schedule  []string  `json:"schedule" db:"schedule"` // peer ids in the order of slots, empty if unknown
+	Added     []string  `json:"added" db:"added"`
+	Removed   []string  `json:"removed" db:"removed"`
+}
+
+// BpScheduleHistory is the changes of bp set sorted by block number
+type BpScheduleHistory []*EsBpChange
+
+// EsAccountVotes is the latest bp voting of an account. The id is account address.
+type EsAccountVotes struct {
+	*BaseEsType
+	BlockNo      uint64    `json:"blockno" db:"blockno"`
+	Timestamp    time.Time `json:"ts" db:"ts"`
+	Candidates   []string  `json:"candidates" db:"candidates"`
+	Amount       string    `json:"amount" db:"amount"`             // string of BigInt
+	AmountFloat  float32   `json:"amount_float" db:"amount_float"` // float for sorting
+	Staking      string    `json:"staking" db:"staking"`
+	StakingFloat float32   `json:"staking_float" db:"staking_float"`
+}
+
+// EsChainParam is a change of a chain parameter, applied from the block. The id is name-blockno.
+type EsChainParam struct {
+	*BaseEsType
+	Name       string    `json:"name" db:"name"`
+	BlockNo    uint64    `json:"blockno" db:"blockno"`
+	Timestamp  time.Time `json:"ts" db:"ts"`
+	Value      string    `json:"value" db:"value"`             // string of BigInt
+	ValueFloat float32   `json:"value_float" db:"value_float"` // float for sorting
+}
+
+// ChainParamHistory is the changes of a chain parameter sorted by block number
+type ChainParamHistory []*EsChainParam
+
+// EsNameState is the current state of a name with its update history. The id is name.
+type EsNameState struct {
+	*BaseEsType
+	Name         string           `json:"name" db:"name"`
+	Owner        string           `json:"owner" db:"owner"`
+	Destination  string           `json:"destination" db:"destination"`
+	CreatedBlock uint64           `json:"created_blockno" db:"created_blockno"`
+	BlockNo      uint64           `json:"blockno" db:"blockno"` // last updated block
+	UpdateTx     string           `json:"tx" db:"tx"`
+	History      []*EsNameHistory `json:"history" db:"history"`
+}
+
+// EsNameHistory is an update of a name, stored in the history of name state
+type EsNameHistory struct {
+	BlockNo     uint64 `json:"blockno" db:"blockno"`
+	UpdateTx    string `json:"tx" db:"tx"`
+	Operation   string `json:"operation" db:"operation"`
+	Owner       string `json:"owner" db:"owner"`
+	Destination string `json:"destination" db:"destination"`
+}
+
+// EsEnterpriseConf is the current state of an enterprise config key. The id is config key.
+type EsEnterpriseConf struct {
+	*BaseEsType
+	Key       string    `json:"key" db:"key"`
+	On        bool      `json:"on" db:"on"`
+	Values    []string  `json:"values" db:"values"`
+	BlockNo   uint64    `json:"blockno" db:"blockno"` // last changed block
+	Timestamp time.Time `json:"ts" db:"ts"`
+	UpdateTx  string    `json:"tx" db:"tx"`
+}
+
+// EsEnterpriseHistory is a change of enterprise config or cluster. The id is tx hash.
+type EsEnterpriseHistory struct {
+	*BaseEsType
+	BlockNo        uint64    `json:"blockno" db:"blockno"`
+	Timestamp      time.Time `json:"ts" db:"ts"`
+	Sender         string    `json:"sender" db:"sender"`
+	Operation      string    `json:"operation" db:"operation"`
+	Status         string    `json:"status" db:"status"`
+	Key            string    `json:"key" db:"key"`
+	Values         []string  `json:"values" db:"values"`
+	Enable         bool      `json:"enable" db:"enable"`
+	ClusterCommand string    `json:"cluster_command" db:"cluster_command"`
+	ClusterName    string    `json:"cluster_name" db:"cluster_name"`
+	ClusterId      string    `json:"cluster_id" db:"cluster_id"`
+	ClusterPeerId  string    `json:"cluster_peer_id" db:"cluster_peer_id"`
+	ClusterAddress string    `json:"cluster_address" db:"cluster_address"`
+	ClusterState   string    `json:"cluster_state" db:"cluster_state"`
+	ClusterError   string    `json:"cluster_error" db:"cluster_error"`
+	ClusterMembers []string  `json:"cluster_members" db:"cluster_members"`
+}
+
+// EsRaftMember is a change of raft cluster membership or leader. The id is blockno + event + member.
+type EsRaftMember struct {
+	*BaseEsType
+	BlockNo     uint64    `json:"blockno" db:"blockno"`
+	Timestamp   time.Time `json:"ts" db:"ts"`
+	Event       string    `json:"event" db:"event"` // snapshot, add, remove, leader
+	Name        string    `json:"name" db:"name"`
+	RaftId      string    `json:"raft_id" db:"raft_id"`
+	PeerId      string    `json:"peer_id" db:"peer_id"`
+	Address     string    `json:"address" db:"address"`
+	Leader      string    `json:"leader" db:"leader"`
+	Members     []string  `json:"members" db:"members"` // peer ids of members after the change
+	MemberNames []string  `json:"member_names" db:"member_names"`
+	UpdateTx    string    `json:"tx" db:"tx"` // changeCluster tx which triggered the change
+}
+
+// EsContractAbi is the abi of a contract version. The id is contract address + deploy tx hash.
+type EsContractAbi struct {
+	*BaseEsType
+	Contract       string           `json:"contract" db:"contract"`
+	TxId           string           `json:"tx_id" db:"tx_id"` // deploy or redeploy tx
+	BlockNo        uint64           `json:"blockno" db:"blockno"`
+	Timestamp      time.Time        `json:"ts" db:"ts"`
+	Language       string           `json:"language" db:"language"`
+	Version        string           `json:"version" db:"version"`
+	Functions      []*EsAbiFunction `json:"functions" db:"functions"`
+	StateVariables []*EsAbiStateVar `json:"state_variables" db:"state_variables"`
+}
+
+// EsAbiFunction is a function of contract abi
+type EsAbiFunction struct {
+	Name          string   `json:"name" db:"name"`
+	Arguments     []string `json:"arguments" db:"arguments"`
+	Payable       bool     `json:"payable" db:"payable"`
+	View          bool     `json:"view" db:"view"`
+	FeeDelegation bool     `json:"fee_delegation" db:"fee_delegation"`
+}
+
+// EsAbiStateVar is a state variable of contract abi
+type EsAbiStateVar struct {
+	Name string `json:"name" db:"name"`
+	Type string `json:"type" db:"type"`
+	Len  int32  `json:"len" db:"len"`
+}
+
 var EsMappings map[string]string
 
 func InitEsMappings(clusterMode bool) {
@@ -301,7 +664,13 @@ func InitEsMappings(clusterMode bool) {
 							"type": "keyword"
 						},
 						"reward_amount": {
+							"enabled": false
+						},
+						"reward_amount_float": {
 							"type": "float"
+						},
+						"reward_source": {
+							"type": "keyword"
 						}
 					}
 				}
@@ -335,12 +704,18 @@ func InitEsMappings(clusterMode bool) {
 						"to": {
 							"type": "keyword"
 						},
+						"to_address": {
+							"type": "keyword"
+						},
 						"amount": {
 							"enabled": false
 						},
 						"amount_float": {
 							"type": "float"
 						},
+						"amount_padded": {
+							"type": "keyword"
+						},
 						"type": {
 							"type": "long"
 						},
@@ -350,6 +725,51 @@ func InitEsMappings(clusterMode bool) {
 						"method": {
 							"type": "keyword"
 						},
+						"ops": {
+							"type": "nested",
+							"properties": {
+								"idx": {
+									"type": "integer"
+								},
+								"depth": {
+									"type": "integer"
+								},
+								"command": {
+									"type": "keyword"
+								},
+								"contract": {
+									"type": "keyword"
+								},
+								"method": {
+									"type": "keyword"
+								},
+								"args": {
+									"type": "keyword",
+									"ignore_above": 1024
+								}
+							}
+						},
+						"args": {
+							"type": "nested",
+							"properties": {
+								"idx": {
+									"type": "integer"
+								},
+								"name": {
+									"type": "keyword"
+								},
+								"type": {
+									"type": "keyword"
+								},
+								"value": {
+									"type": "keyword",
+									"ignore_above": 1024
+								},
+								"num": {
+									"type": "double"
+								}
+							}
+						},
 						"token_transfers": {
 							"type": "long"
 						},
@@ -379,6 +799,15 @@ func InitEsMappings(clusterMode bool) {
 						},
 						"fee_used": {
 							"type": "keyword"
+						},
+						"fee_used_float": {
+							"type": "float"
+						},
+						"fee_payer": {
+							"type": "keyword"
+						},
+						"fee_mismatch": {
+							"type": "boolean"
 						}
 					}
 				}
@@ -406,12 +835,39 @@ func InitEsMappings(clusterMode bool) {
 						"payload": {
 							"type": "text"
 						},
+						"version_tx": {
+							"type": "keyword"
+						},
+						"version_blockno": {
+							"type": "long"
+						},
+						"payload_hash": {
+							"type": "keyword"
+						},
+						"code_hash": {
+							"type": "keyword"
+						},
 						"verified_status": {
 							"type": "keyword"
 						},
 						"verified_token": {
 							"type": "keyword"
 						},
+						"verified_compiler": {
+							"type": "keyword"
+						},
+						"verified_reason": {
+							"type": "keyword"
+						},
+						"token_type": {
+							"type": "keyword"
+						},
+						"interfaces": {
+							"type": "keyword"
+						},
+						"token_confidence": {
+							"type": "float"
+						},
 						"code_url": {
 							"type": "keyword"
 						},
@@ -428,6 +884,33 @@ func InitEsMappings(clusterMode bool) {
 					"index.max_result_window": 100000
 				},
 				"mappings": {
+					"dynamic_templates": [
+						{
+							"event_args_str": {
+								"path_match": "args.*.str",
+								"mapping": {
+									"type": "keyword",
+									"ignore_above": 256
+								}
+							}
+						},
+						{
+							"event_args_num": {
+								"path_match": "args.*.num",
+								"mapping": {
+									"type": "double"
+								}
+							}
+						},
+						{
+							"event_args_addr": {
+								"path_match": "args.*.addr",
+								"mapping": {
+									"type": "keyword"
+								}
+							}
+						}
+					],
 					"properties": {
 						"contract": {
 							"type": "keyword" 
@@ -449,6 +932,9 @@ func InitEsMappings(clusterMode bool) {
 						},
 						"event_args": {
 							"type": "keyword"
+						},
+						"args": {
+							"type": "object"
 						}
 					}
 				}
@@ -516,6 +1002,9 @@ func InitEsMappings(clusterMode bool) {
 						"supply_float": {
 							"type": "float"
 						},
+						"supply_padded": {
+							"type": "keyword"
+						},
 						"verified_status": {
 							"type": "keyword"
 						},
@@ -542,6 +1031,29 @@ func InitEsMappings(clusterMode bool) {
 						},
 						"total_transfer": {
 							"type": "long"
+						},
+						"holder_count": {
+							"type": "long"
+						},
+						"top_holders": {
+							"type": "nested",
+							"properties": {
+								"account": {
+									"type": "keyword"
+								},
+								"balance": {
+									"enabled": false
+								},
+								"balance_float": {
+									"type": "float"
+								},
+								"share": {
+									"type": "float"
+								}
+							}
+						},
+						"top_holders_share": {
+							"type": "float"
 						}
 					}
 				}
@@ -578,11 +1090,20 @@ func InitEsMappings(clusterMode bool) {
 						"sender": {
 							"type": "keyword"
 						},
+						"method": {
+							"type": "keyword"
+						},
 						"amount": {
 							"enabled": false
 						},
 						"amount_float": {
 							"type": "float"
+						},
+						"amount_adjusted": {
+							"type": "double"
+						},
+						"amount_padded": {
+							"type": "keyword"
 						}
 					}
 				}
@@ -612,6 +1133,12 @@ func InitEsMappings(clusterMode bool) {
 						},
 						"balance_float": {
 							"type": "float"
+						},
+						"balance_adjusted": {
+							"type": "double"
+						},
+						"balance_padded": {
+							"type": "keyword"
 						}
 					}
 				}
@@ -643,6 +1170,37 @@ func InitEsMappings(clusterMode bool) {
 						},
 						"image_url": {
 							"type": "keyword"
+						},
+						"name": {
+							"type": "keyword"
+						},
+						"description": {
+							"type": "text"
+						},
+						"metadata_image": {
+							"type": "keyword"
+						},
+						"attributes": {
+							"type": "nested",
+							"properties": {
+								"trait_type": {
+									"type": "keyword"
+								},
+								"value": {
+									"type": "keyword",
+									"ignore_above": 1024
+								}
+							}
+						},
+						"metadata_hash": {
+							"type": "keyword"
+						},
+						"metadata_error": {
+							"type": "keyword",
+							"ignore_above": 1024
+						},
+						"metadata_ts": {
+							"type": "date"
 						}
 					}
 				}
@@ -669,11 +1227,17 @@ func InitEsMappings(clusterMode bool) {
 						"balance_float": {
 							"type": "float"
 						},
+						"balance_padded": {
+							"type": "keyword"
+						},
 						"staking": {
 							"enabled": false
 						},
 						"staking_float": {
 							"type": "float"
+						},
+						"staking_padded": {
+							"type": "keyword"
 						}
 					}
 				}
@@ -694,74 +1258,68 @@ func InitEsMappings(clusterMode bool) {
 					}
 				}
 			}`,
-		}
-	} else {
-		EsMappings = map[string]string{
-			"chain_info": `{
+			"bp_votes": `{
 				"settings": {
-					"number_of_shards": 3,
+					"number_of_shards": 10,
 					"number_of_replicas": 1,
 					"index.max_result_window": 100000
 				},
 				"mappings": {
 					"properties": {
-						"id": {
-							"type": "keyword"
-						},
-						"public": {
-							"type": "boolean"
+						"blockno": {
+							"type": "long"
 						},
-						"mainnet": {
-							"type": "boolean"
+						"ts": {
+							"type": "date"
 						},
-						"consensus": {
+						"candidate": {
 							"type": "keyword"
 						},
-						"version": {
+						"votes": {
+							"enabled": false
+						},
+						"votes_float": {
+							"type": "float"
+						},
+						"rank": {
 							"type": "long"
+						},
+						"active": {
+							"type": "boolean"
 						}
 					}
 				}
 			}`,
-			"block": `{
+			"bp_change": `{
 				"settings": {
-					"number_of_shards": 20,
+					"number_of_shards": 3,
 					"number_of_replicas": 1,
 					"index.max_result_window": 100000
 				},
 				"mappings": {
 					"properties": {
+						"blockno": {
+							"type": "long"
+						},
 						"ts": {
 							"type": "date"
 						},
-						"no": {
-							"type": "long"
-						},
-						"previous_block": {
+						"bps": {
 							"type": "keyword"
 						},
-						"txs": {
-							"type": "long"
-						},
-						"size": {
-							"type": "long"
-						},
-						"coinbase": {
+						"schedule": {
 							"type": "keyword"
 						},
-						"block_producer": {
+						"added": {
 							"type": "keyword"
 						},
-						"reward_account": {
+						"removed": {
 							"type": "keyword"
-						},
-						"reward_amount": {
-							"type": "float"
 						}
 					}
 				}
 			}`,
-			"tx": `{
+			"account_votes": `{
 				"settings": {
 					"number_of_shards": 10,
 					"number_of_replicas": 1,
@@ -772,22 +1330,10 @@ func InitEsMappings(clusterMode bool) {
 						"blockno": {
 							"type": "long"
 						},
-						"block_id": {
-							"type": "keyword"
-						},
 						"ts": {
 							"type": "date"
 						},
-						"tx_idx": {
-							"type": "long"
-						},
-						"payload": {
-							"type": "text"
-						},
-						"from": {
-							"type": "keyword"
-						},
-						"to": {
+						"candidates": {
 							"type": "keyword"
 						},
 						"amount": {
@@ -796,60 +1342,79 @@ func InitEsMappings(clusterMode bool) {
 						"amount_float": {
 							"type": "float"
 						},
-						"type": {
-							"type": "long"
-						},
-						"category": {
-							"type": "keyword"
-						},
-						"method": {
-							"type": "keyword"
+						"staking": {
+							"enabled": false
 						},
-						"token_transfers": {
-							"type": "long"
-						},
-						"status": {
-							"type": "keyword"
-						},
-						"result": {
+						"staking_float": {
+							"type": "float"
+						}
+					}
+				}
+			}`,
+			"name_state": `{
+				"settings": {
+					"number_of_shards": 2,
+					"number_of_replicas": 1,
+					"index.max_result_window": 100000
+				},
+				"mappings": {
+					"properties": {
+						"name": {
 							"type": "keyword"
 						},
-						"nonce": {
-							"type": "long"
-						},
-						"contract": {
+						"owner": {
 							"type": "keyword"
 						},
-						"fee_delegation": {
-							"type": "boolean"
-						},
-						"gas_price": {
+						"destination": {
 							"type": "keyword"
 						},
-						"gas_used": {
+						"created_blockno": {
 							"type": "long"
 						},
-						"gas_limit": {
+						"blockno": {
 							"type": "long"
 						},
-						"fee_used": {
+						"tx": {
 							"type": "keyword"
+						},
+						"history": {
+							"type": "nested",
+							"properties": {
+								"blockno": {
+									"type": "long"
+								},
+								"tx": {
+									"type": "keyword"
+								},
+								"operation": {
+									"type": "keyword"
+								},
+								"owner": {
+									"type": "keyword"
+								},
+								"destination": {
+									"type": "keyword"
+								}
+							}
 						}
 					}
 				}
 			}`,
-			"contract": `{
+			"enterprise_conf": `{
 				"settings": {
-					"number_of_shards": 10,
+					"number_of_shards": 1,
 					"number_of_replicas": 1,
 					"index.max_result_window": 100000
 				},
 				"mappings": {
 					"properties": {
-						"tx_id": {
+						"key": {
 							"type": "keyword"
 						},
-						"creator": {
+						"on": {
+							"type": "boolean"
+						},
+						"values": {
 							"type": "keyword"
 						},
 						"blockno": {
@@ -858,57 +1423,72 @@ func InitEsMappings(clusterMode bool) {
 						"ts": {
 							"type": "date"
 						},
-						"payload": {
-							"type": "text"
-						},
-						"verified_status": {
-							"type": "keyword"
-						},
-						"verified_token": {
-							"type": "keyword"
-						},
-						"code_url": {
+						"tx": {
 							"type": "keyword"
-						},
-						"code": {
-							"type": "text"
 						}
 					}
 				}
 			}`,
-			"event": `{
+			"enterprise_history": `{
 				"settings": {
-					"number_of_shards": 10,
+					"number_of_shards": 3,
 					"number_of_replicas": 1,
 					"index.max_result_window": 100000
 				},
 				"mappings": {
 					"properties": {
-						"contract": {
-							"type": "keyword" 
-						},
 						"blockno": {
 							"type": "long"
 						},
-						"tx_id": {
+						"ts": {
+							"type": "date"
+						},
+						"sender": {
 							"type": "keyword"
 						},
-						"tx_idx": {
-							"type": "long"
+						"operation": {
+							"type": "keyword"
 						},
-						"event_idx": {
-							"type": "long"
+						"status": {
+							"type": "keyword"
 						},
-						"event_name": {
+						"key": {
 							"type": "keyword"
 						},
-						"event_args": {
+						"values": {
+							"type": "keyword"
+						},
+						"enable": {
+							"type": "boolean"
+						},
+						"cluster_command": {
+							"type": "keyword"
+						},
+						"cluster_name": {
+							"type": "keyword"
+						},
+						"cluster_id": {
+							"type": "keyword"
+						},
+						"cluster_peer_id": {
+							"type": "keyword"
+						},
+						"cluster_address": {
+							"type": "keyword"
+						},
+						"cluster_state": {
+							"type": "keyword"
+						},
+						"cluster_error": {
+							"type": "keyword"
+						},
+						"cluster_members": {
 							"type": "keyword"
 						}
 					}
 				}
 			}`,
-			"name": `{
+			"raft_member": `{
 				"settings": {
 					"number_of_shards": 1,
 					"number_of_replicas": 1,
@@ -916,14 +1496,35 @@ func InitEsMappings(clusterMode bool) {
 				},
 				"mappings": {
 					"properties": {
+						"blockno": {
+							"type": "long"
+						},
+						"ts": {
+							"type": "date"
+						},
+						"event": {
+							"type": "keyword"
+						},
 						"name": {
 							"type": "keyword"
 						},
+						"raft_id": {
+							"type": "keyword"
+						},
+						"peer_id": {
+							"type": "keyword"
+						},
 						"address": {
 							"type": "keyword"
 						},
-						"blockno": {
-							"type": "long"
+						"leader": {
+							"type": "keyword"
+						},
+						"members": {
+							"type": "keyword"
+						},
+						"member_names": {
+							"type": "keyword"
 						},
 						"tx": {
 							"type": "keyword"
@@ -931,222 +1532,1674 @@ func InitEsMappings(clusterMode bool) {
 					}
 				}
 			}`,
-			"token": `{
+			"contract_abi": `{
 				"settings": {
-					"number_of_shards": 3,
+					"number_of_shards": 5,
 					"number_of_replicas": 1,
 					"index.max_result_window": 100000
 				},
 				"mappings": {
 					"properties": {
+						"contract": {
+							"type": "keyword"
+						},
 						"tx_id": {
 							"type": "keyword"
 						},
 						"blockno": {
 							"type": "long"
 						},
-						"creator": {
-							"type": "keyword"
-						},
-						"type": {
-							"type": "keyword"
+						"ts": {
+							"type": "date"
 						},
-						"name": {
+						"language": {
 							"type": "keyword"
 						},
-						"name_lower": {
+						"version": {
 							"type": "keyword"
 						},
-						"symbol": {
+						"functions": {
+							"type": "nested",
+							"properties": {
+								"name": {
+									"type": "keyword"
+								},
+								"arguments": {
+									"type": "keyword"
+								},
+								"payable": {
+									"type": "boolean"
+								},
+								"view": {
+									"type": "boolean"
+								},
+								"fee_delegation": {
+									"type": "boolean"
+								}
+							}
+						},
+						"state_variables": {
+							"type": "nested",
+							"properties": {
+								"name": {
+									"type": "keyword"
+								},
+								"type": {
+									"type": "keyword"
+								},
+								"len": {
+									"type": "integer"
+								}
+							}
+						}
+					}
+				}
+			}`,
+			"contract_version": `{
+				"settings": {
+					"number_of_shards": 5,
+					"number_of_replicas": 1,
+					"index.max_result_window": 100000
+				},
+				"mappings": {
+					"properties": {
+						"contract": {
 							"type": "keyword"
 						},
-						"symbol_lower": {
+						"tx_id": {
 							"type": "keyword"
 						},
-						"decimals": {
-							"type": "short"
-						},
-						"supply": {
-							"enabled": false
-						},
-						"supply_float": {
-							"type": "float"
+						"blockno": {
+							"type": "long"
 						},
-						"verified_status": {
-							"type": "keyword"
+						"ts": {
+							"type": "date"
 						},
-						"token_address": {
+						"deployer": {
 							"type": "keyword"
 						},
-						"owner": {
+						"category": {
 							"type": "keyword"
 						},
-						"comment": {
-							"type": "keyword"
+						"payload": {
+							"enabled": false
 						},
-						"email": {
+						"payload_hash": {
 							"type": "keyword"
 						},
-						"regdate": {
+						"code_hash": {
 							"type": "keyword"
 						},
-						"homepage_url": {
+						"abi_id": {
 							"type": "keyword"
 						},
-						"image_url": {
+						"abi_functions": {
 							"type": "keyword"
-						},
-						"total_transfer": {
-							"type": "long"
 						}
 					}
 				}
 			}`,
-			"token_transfer": `{
+			"nft_history": `{
 				"settings": {
-					"number_of_shards": 3,
+					"number_of_shards": 30,
 					"number_of_replicas": 1,
 					"index.max_result_window": 100000
 				},
 				"mappings": {
 					"properties": {
-						"tx_id": {
+						"nft_id": {
 							"type": "keyword"
 						},
-						"blockno": {
-							"type": "long"
-						},
-						"ts": {
-							"type": "date"
-						},
 						"address": {
 							"type": "keyword"
 						},
 						"token_id": {
 							"type": "keyword"
 						},
+						"event": {
+							"type": "keyword"
+						},
 						"from": {
 							"type": "keyword"
 						},
 						"to": {
 							"type": "keyword"
 						},
-						"sender": {
+						"tx_id": {
 							"type": "keyword"
 						},
-						"amount": {
-							"enabled": false
+						"blockno": {
+							"type": "long"
 						},
-						"amount_float": {
-							"type": "float"
+						"ts": {
+							"type": "date"
+						},
+						"order": {
+							"type": "long"
 						}
 					}
 				}
 			}`,
-			"account_tokens": `{
+			"token_stats": `{
 				"settings": {
 					"number_of_shards": 3,
-					"number_of_replicas": 1
+					"number_of_replicas": 1,
+					"index.max_result_window": 100000
 				},
 				"mappings": {
 					"properties": {
-						"account": {
-							"type": "keyword"
-						},
 						"address": {
 							"type": "keyword"
 						},
-						"type": {
+						"period": {
 							"type": "keyword"
 						},
 						"ts": {
 							"type": "date"
 						},
-						"balance": {
+						"transfer_count": {
+							"type": "long"
+						},
+						"senders": {
+							"type": "long"
+						},
+						"receivers": {
+							"type": "long"
+						},
+						"volume": {
 							"enabled": false
 						},
-						"balance_float": {
-							"type": "float"
+						"volume_float": {
+							"type": "double"
+						},
+						"mint_count": {
+							"type": "long"
+						},
+						"mint_amount": {
+							"enabled": false
+						},
+						"mint_amount_float": {
+							"type": "double"
+						},
+						"burn_count": {
+							"type": "long"
+						},
+						"burn_amount": {
+							"enabled": false
+						},
+						"burn_amount_float": {
+							"type": "double"
 						}
 					}
 				}
 			}`,
-			"account_balance": `{
+			"chain_stats": `{
 				"settings": {
-					"number_of_shards": 3,
+					"number_of_shards": 1,
 					"number_of_replicas": 1,
 					"index.max_result_window": 100000
 				},
 				"mappings": {
 					"properties": {
-						"id": {
+						"period": {
 							"type": "keyword"
 						},
-						"blockno": {
-							"type": "long"
-						},
 						"ts": {
 							"type": "date"
 						},
-						"balance": {
-							"enabled": false
+						"block_count": {
+							"type": "long"
 						},
-						"balance_float": {
-							"type": "float"
+						"tx_count": {
+							"type": "long"
 						},
-						"staking": {
+						"tx_categories": {
+							"type": "nested",
+							"properties": {
+								"category": {
+									"type": "keyword"
+								},
+								"count": {
+									"type": "long"
+								}
+							}
+						},
+						"active_accounts": {
+							"type": "long"
+						},
+						"new_accounts": {
+							"type": "long"
+						},
+						"new_contracts": {
+							"type": "long"
+						},
+						"new_tokens": {
+							"type": "long"
+						},
+						"gas_used": {
+							"type": "long"
+						},
+						"fees_burned": {
 							"enabled": false
 						},
-						"staking_float": {
-							"type": "float"
+						"fees_burned_float": {
+							"type": "double"
 						}
 					}
 				}
 			}`,
-			"nft": `{
+			"chain_param": `{
 				"settings": {
-					"number_of_shards": 3,
+					"number_of_shards": 1,
 					"number_of_replicas": 1,
 					"index.max_result_window": 100000
 				},
 				"mappings": {
 					"properties": {
-						"address": {
+						"name": {
+							"type": "keyword"
+						},
+						"blockno": {
+							"type": "long"
+						},
+						"ts": {
+							"type": "date"
+						},
+						"value": {
+							"type": "keyword"
+						},
+						"value_float": {
+							"type": "float"
+						}
+					}
+				}
+			}`,
+			"bp_stats": `{
+				"settings": {
+					"number_of_shards": 1,
+					"number_of_replicas": 1,
+					"index.max_result_window": 100000
+				},
+				"mappings": {
+					"properties": {
+						"bp": {
+							"type": "keyword"
+						},
+						"blocks": {
+							"type": "long"
+						},
+						"first_block": {
+							"type": "long"
+						},
+						"first_ts": {
+							"type": "date"
+						},
+						"last_block": {
+							"type": "long"
+						},
+						"last_ts": {
+							"type": "date"
+						},
+						"total_size": {
+							"type": "long"
+						},
+						"total_txs": {
+							"type": "long"
+						},
+						"avg_size": {
+							"type": "double"
+						},
+						"avg_txs": {
+							"type": "double"
+						},
+						"reward": {
+							"enabled": false
+						},
+						"reward_float": {
+							"type": "float"
+						},
+						"missed_slots": {
+							"type": "long"
+						}
+					}
+				}
+			}`,
+			"fee_sponsor": `{
+				"settings": {
+					"number_of_shards": 1,
+					"number_of_replicas": 1,
+					"index.max_result_window": 100000
+				},
+				"mappings": {
+					"properties": {
+						"contract": {
+							"type": "keyword"
+						},
+						"account": {
+							"type": "keyword"
+						},
+						"method": {
+							"type": "keyword"
+						},
+						"tx_count": {
+							"type": "long"
+						},
+						"gas_used": {
+							"type": "long"
+						},
+						"fee": {
+							"enabled": false
+						},
+						"fee_float": {
+							"type": "double"
+						},
+						"blockno": {
+							"type": "long"
+						},
+						"ts": {
+							"type": "date"
+						}
+					}
+				}
+			}`,
+		}
+	} else {
+		EsMappings = map[string]string{
+			"chain_info": `{
+				"settings": {
+					"number_of_shards": 3,
+					"number_of_replicas": 1,
+					"index.max_result_window": 100000
+				},
+				"mappings": {
+					"properties": {
+						"id": {
+							"type": "keyword"
+						},
+						"public": {
+							"type": "boolean"
+						},
+						"mainnet": {
+							"type": "boolean"
+						},
+						"consensus": {
+							"type": "keyword"
+						},
+						"version": {
+							"type": "long"
+						}
+					}
+				}
+			}`,
+			"block": `{
+				"settings": {
+					"number_of_shards": 20,
+					"number_of_replicas": 1,
+					"index.max_result_window": 100000
+				},
+				"mappings": {
+					"properties": {
+						"ts": {
+							"type": "date"
+						},
+						"no": {
+							"type": "long"
+						},
+						"previous_block": {
+							"type": "keyword"
+						},
+						"txs": {
+							"type": "long"
+						},
+						"size": {
+							"type": "long"
+						},
+						"coinbase": {
+							"type": "keyword"
+						},
+						"block_producer": {
+							"type": "keyword"
+						},
+						"reward_account": {
+							"type": "keyword"
+						},
+						"reward_amount": {
+							"enabled": false
+						},
+						"reward_amount_float": {
+							"type": "float"
+						},
+						"reward_source": {
+							"type": "keyword"
+						}
+					}
+				}
+			}`,
+			"tx": `{
+				"settings": {
+					"number_of_shards": 10,
+					"number_of_replicas": 1,
+					"index.max_result_window": 100000
+				},
+				"mappings": {
+					"properties": {
+						"blockno": {
+							"type": "long"
+						},
+						"block_id": {
+							"type": "keyword"
+						},
+						"ts": {
+							"type": "date"
+						},
+						"tx_idx": {
+							"type": "long"
+						},
+						"payload": {
+							"type": "text"
+						},
+						"from": {
+							"type": "keyword"
+						},
+						"to": {
+							"type": "keyword"
+						},
+						"to_address": {
+							"type": "keyword"
+						},
+						"amount": {
+							"enabled": false
+						},
+						"amount_float": {
+							"type": "float"
+						},
+						"amount_padded": {
+							"type": "keyword"
+						},
+						"type": {
+							"type": "long"
+						},
+						"category": {
+							"type": "keyword"
+						},
+						"method": {
+							"type": "keyword"
+						},
+						"ops": {
+							"type": "nested",
+							"properties": {
+								"idx": {
+									"type": "integer"
+								},
+								"depth": {
+									"type": "integer"
+								},
+								"command": {
+									"type": "keyword"
+								},
+								"contract": {
+									"type": "keyword"
+								},
+								"method": {
+									"type": "keyword"
+								},
+								"args": {
+									"type": "keyword",
+									"ignore_above": 1024
+								}
+							}
+						},
+						"args": {
+							"type": "nested",
+							"properties": {
+								"idx": {
+									"type": "integer"
+								},
+								"name": {
+									"type": "keyword"
+								},
+								"type": {
+									"type": "keyword"
+								},
+								"value": {
+									"type": "keyword",
+									"ignore_above": 1024
+								},
+								"num": {
+									"type": "double"
+								}
+							}
+						},
+						"token_transfers": {
+							"type": "long"
+						},
+						"status": {
+							"type": "keyword"
+						},
+						"result": {
+							"type": "keyword"
+						},
+						"nonce": {
+							"type": "long"
+						},
+						"contract": {
+							"type": "keyword"
+						},
+						"fee_delegation": {
+							"type": "boolean"
+						},
+						"gas_price": {
+							"type": "keyword"
+						},
+						"gas_used": {
+							"type": "long"
+						},
+						"gas_limit": {
+							"type": "long"
+						},
+						"fee_used": {
+							"type": "keyword"
+						},
+						"fee_used_float": {
+							"type": "float"
+						},
+						"fee_payer": {
+							"type": "keyword"
+						},
+						"fee_mismatch": {
+							"type": "boolean"
+						}
+					}
+				}
+			}`,
+			"contract": `{
+				"settings": {
+					"number_of_shards": 10,
+					"number_of_replicas": 1,
+					"index.max_result_window": 100000
+				},
+				"mappings": {
+					"properties": {
+						"tx_id": {
+							"type": "keyword"
+						},
+						"creator": {
+							"type": "keyword"
+						},
+						"blockno": {
+							"type": "long"
+						},
+						"ts": {
+							"type": "date"
+						},
+						"payload": {
+							"type": "text"
+						},
+						"version_tx": {
+							"type": "keyword"
+						},
+						"version_blockno": {
+							"type": "long"
+						},
+						"payload_hash": {
+							"type": "keyword"
+						},
+						"code_hash": {
+							"type": "keyword"
+						},
+						"verified_status": {
+							"type": "keyword"
+						},
+						"verified_token": {
+							"type": "keyword"
+						},
+						"verified_compiler": {
+							"type": "keyword"
+						},
+						"verified_reason": {
+							"type": "keyword"
+						},
+						"token_type": {
+							"type": "keyword"
+						},
+						"interfaces": {
+							"type": "keyword"
+						},
+						"token_confidence": {
+							"type": "float"
+						},
+						"code_url": {
+							"type": "keyword"
+						},
+						"code": {
+							"type": "text"
+						}
+					}
+				}
+			}`,
+			"event": `{
+				"settings": {
+					"number_of_shards": 10,
+					"number_of_replicas": 1,
+					"index.max_result_window": 100000
+				},
+				"mappings": {
+					"dynamic_templates": [
+						{
+							"event_args_str": {
+								"path_match": "args.*.str",
+								"mapping": {
+									"type": "keyword",
+									"ignore_above": 256
+								}
+							}
+						},
+						{
+							"event_args_num": {
+								"path_match": "args.*.num",
+								"mapping": {
+									"type": "double"
+								}
+							}
+						},
+						{
+							"event_args_addr": {
+								"path_match": "args.*.addr",
+								"mapping": {
+									"type": "keyword"
+								}
+							}
+						}
+					],
+					"properties": {
+						"contract": {
+							"type": "keyword" 
+						},
+						"blockno": {
+							"type": "long"
+						},
+						"tx_id": {
+							"type": "keyword"
+						},
+						"tx_idx": {
+							"type": "long"
+						},
+						"event_idx": {
+							"type": "long"
+						},
+						"event_name": {
+							"type": "keyword"
+						},
+						"event_args": {
+							"type": "keyword"
+						},
+						"args": {
+							"type": "object"
+						}
+					}
+				}
+			}`,
+			"name": `{
+				"settings": {
+					"number_of_shards": 1,
+					"number_of_replicas": 1,
+					"index.max_result_window": 100000
+				},
+				"mappings": {
+					"properties": {
+						"name": {
+							"type": "keyword"
+						},
+						"address": {
+							"type": "keyword"
+						},
+						"blockno": {
+							"type": "long"
+						},
+						"tx": {
+							"type": "keyword"
+						}
+					}
+				}
+			}`,
+			"token": `{
+				"settings": {
+					"number_of_shards": 3,
+					"number_of_replicas": 1,
+					"index.max_result_window": 100000
+				},
+				"mappings": {
+					"properties": {
+						"tx_id": {
+							"type": "keyword"
+						},
+						"blockno": {
+							"type": "long"
+						},
+						"creator": {
+							"type": "keyword"
+						},
+						"type": {
+							"type": "keyword"
+						},
+						"name": {
+							"type": "keyword"
+						},
+						"name_lower": {
+							"type": "keyword"
+						},
+						"symbol": {
+							"type": "keyword"
+						},
+						"symbol_lower": {
+							"type": "keyword"
+						},
+						"decimals": {
+							"type": "short"
+						},
+						"supply": {
+							"enabled": false
+						},
+						"supply_float": {
+							"type": "float"
+						},
+						"supply_padded": {
+							"type": "keyword"
+						},
+						"verified_status": {
+							"type": "keyword"
+						},
+						"token_address": {
+							"type": "keyword"
+						},
+						"owner": {
+							"type": "keyword"
+						},
+						"comment": {
+							"type": "keyword"
+						},
+						"email": {
+							"type": "keyword"
+						},
+						"regdate": {
+							"type": "keyword"
+						},
+						"homepage_url": {
+							"type": "keyword"
+						},
+						"image_url": {
+							"type": "keyword"
+						},
+						"total_transfer": {
+							"type": "long"
+						},
+						"holder_count": {
+							"type": "long"
+						},
+						"top_holders": {
+							"type": "nested",
+							"properties": {
+								"account": {
+									"type": "keyword"
+								},
+								"balance": {
+									"enabled": false
+								},
+								"balance_float": {
+									"type": "float"
+								},
+								"share": {
+									"type": "float"
+								}
+							}
+						},
+						"top_holders_share": {
+							"type": "float"
+						}
+					}
+				}
+			}`,
+			"token_transfer": `{
+				"settings": {
+					"number_of_shards": 3,
+					"number_of_replicas": 1,
+					"index.max_result_window": 100000
+				},
+				"mappings": {
+					"properties": {
+						"tx_id": {
+							"type": "keyword"
+						},
+						"blockno": {
+							"type": "long"
+						},
+						"ts": {
+							"type": "date"
+						},
+						"address": {
+							"type": "keyword"
+						},
+						"token_id": {
+							"type": "keyword"
+						},
+						"from": {
+							"type": "keyword"
+						},
+						"to": {
+							"type": "keyword"
+						},
+						"sender": {
+							"type": "keyword"
+						},
+						"method": {
+							"type": "keyword"
+						},
+						"amount": {
+							"enabled": false
+						},
+						"amount_float": {
+							"type": "float"
+						},
+						"amount_adjusted": {
+							"type": "double"
+						},
+						"amount_padded": {
+							"type": "keyword"
+						}
+					}
+				}
+			}`,
+			"account_tokens": `{
+				"settings": {
+					"number_of_shards": 3,
+					"number_of_replicas": 1
+				},
+				"mappings": {
+					"properties": {
+						"account": {
+							"type": "keyword"
+						},
+						"address": {
+							"type": "keyword"
+						},
+						"type": {
+							"type": "keyword"
+						},
+						"ts": {
+							"type": "date"
+						},
+						"balance": {
+							"enabled": false
+						},
+						"balance_float": {
+							"type": "float"
+						},
+						"balance_adjusted": {
+							"type": "double"
+						},
+						"balance_padded": {
+							"type": "keyword"
+						}
+					}
+				}
+			}`,
+			"account_balance": `{
+				"settings": {
+					"number_of_shards": 3,
+					"number_of_replicas": 1,
+					"index.max_result_window": 100000
+				},
+				"mappings": {
+					"properties": {
+						"id": {
+							"type": "keyword"
+						},
+						"blockno": {
+							"type": "long"
+						},
+						"ts": {
+							"type": "date"
+						},
+						"balance": {
+							"enabled": false
+						},
+						"balance_float": {
+							"type": "float"
+						},
+						"balance_padded": {
+							"type": "keyword"
+						},
+						"staking": {
+							"enabled": false
+						},
+						"staking_float": {
+							"type": "float"
+						},
+						"staking_padded": {
+							"type": "keyword"
+						}
+					}
+				}
+			}`,
+			"nft": `{
+				"settings": {
+					"number_of_shards": 3,
+					"number_of_replicas": 1,
+					"index.max_result_window": 100000
+				},
+				"mappings": {
+					"properties": {
+						"address": {
+							"type": "keyword"
+						},
+						"token_id": {
+							"type": "keyword"
+						},
+						"blockno": {
+							"type": "long"
+						},
+						"ts": {
+							"type": "date"
+						},
+						"account": {
+							"type": "keyword"
+						},
+						"token_uri": {
+							"type": "keyword"
+						},
+						"image_url": {
+							"type": "keyword"
+						},
+						"name": {
+							"type": "keyword"
+						},
+						"description": {
+							"type": "text"
+						},
+						"metadata_image": {
+							"type": "keyword"
+						},
+						"attributes": {
+							"type": "nested",
+							"properties": {
+								"trait_type": {
+									"type": "keyword"
+								},
+								"value": {
+									"type": "keyword",
+									"ignore_above": 1024
+								}
+							}
+						},
+						"metadata_hash": {
+							"type": "keyword"
+						},
+						"metadata_error": {
+							"type": "keyword",
+							"ignore_above": 1024
+						},
+						"metadata_ts": {
+							"type": "date"
+						}
+					}
+				}
+			}`,
+			"whitelist": `{
+				"settings": {
+					"number_of_shards": 10,
+					"number_of_replicas": 1
+				},
+				"mappings": {
+					"properties": {
+						"contract": {
+							"type": "keyword"
+						},
+						"type": {
+							"type": "keyword"
+						}
+					}
+				}
+			}`,
+			"bp_votes": `{
+				"settings": {
+					"number_of_shards": 3,
+					"number_of_replicas": 1,
+					"index.max_result_window": 100000
+				},
+				"mappings": {
+					"properties": {
+						"blockno": {
+							"type": "long"
+						},
+						"ts": {
+							"type": "date"
+						},
+						"candidate": {
+							"type": "keyword"
+						},
+						"votes": {
+							"enabled": false
+						},
+						"votes_float": {
+							"type": "float"
+						},
+						"rank": {
+							"type": "long"
+						},
+						"active": {
+							"type": "boolean"
+						}
+					}
+				}
+			}`,
+			"bp_change": `{
+				"settings": {
+					"number_of_shards": 1,
+					"number_of_replicas": 1,
+					"index.max_result_window": 100000
+				},
+				"mappings": {
+					"properties": {
+						"blockno": {
+							"type": "long"
+						},
+						"ts": {
+							"type": "date"
+						},
+						"bps": {
+							"type": "keyword"
+						},
+						"schedule": {
+							"type": "keyword"
+						},
+						"added": {
+							"type": "keyword"
+						},
+						"removed": {
+							"type": "keyword"
+						}
+					}
+				}
+			}`,
+			"account_votes": `{
+				"settings": {
+					"number_of_shards": 3,
+					"number_of_replicas": 1,
+					"index.max_result_window": 100000
+				},
+				"mappings": {
+					"properties": {
+						"blockno": {
+							"type": "long"
+						},
+						"ts": {
+							"type": "date"
+						},
+						"candidates": {
+							"type": "keyword"
+						},
+						"amount": {
+							"enabled": false
+						},
+						"amount_float": {
+							"type": "float"
+						},
+						"staking": {
+							"enabled": false
+						},
+						"staking_float": {
+							"type": "float"
+						}
+					}
+				}
+			}`,
+			"name_state": `{
+				"settings": {
+					"number_of_shards": 1,
+					"number_of_replicas": 1,
+					"index.max_result_window": 100000
+				},
+				"mappings": {
+					"properties": {
+						"name": {
+							"type": "keyword"
+						},
+						"owner": {
+							"type": "keyword"
+						},
+						"destination": {
+							"type": "keyword"
+						},
+						"created_blockno": {
+							"type": "long"
+						},
+						"blockno": {
+							"type": "long"
+						},
+						"tx": {
+							"type": "keyword"
+						},
+						"history": {
+							"type": "nested",
+							"properties": {
+								"blockno": {
+									"type": "long"
+								},
+								"tx": {
+									"type": "keyword"
+								},
+								"operation": {
+									"type": "keyword"
+								},
+								"owner": {
+									"type": "keyword"
+								},
+								"destination": {
+									"type": "keyword"
+								}
+							}
+						}
+					}
+				}
+			}`,
+			"enterprise_conf": `{
+				"settings": {
+					"number_of_shards": 1,
+					"number_of_replicas": 1,
+					"index.max_result_window": 100000
+				},
+				"mappings": {
+					"properties": {
+						"key": {
+							"type": "keyword"
+						},
+						"on": {
+							"type": "boolean"
+						},
+						"values": {
+							"type": "keyword"
+						},
+						"blockno": {
+							"type": "long"
+						},
+						"ts": {
+							"type": "date"
+						},
+						"tx": {
+							"type": "keyword"
+						}
+					}
+				}
+			}`,
+			"enterprise_history": `{
+				"settings": {
+					"number_of_shards": 1,
+					"number_of_replicas": 1,
+					"index.max_result_window": 100000
+				},
+				"mappings": {
+					"properties": {
+						"blockno": {
+							"type": "long"
+						},
+						"ts": {
+							"type": "date"
+						},
+						"sender": {
+							"type": "keyword"
+						},
+						"operation": {
+							"type": "keyword"
+						},
+						"status": {
+							"type": "keyword"
+						},
+						"key": {
+							"type": "keyword"
+						},
+						"values": {
+							"type": "keyword"
+						},
+						"enable": {
+							"type": "boolean"
+						},
+						"cluster_command": {
+							"type": "keyword"
+						},
+						"cluster_name": {
+							"type": "keyword"
+						},
+						"cluster_id": {
+							"type": "keyword"
+						},
+						"cluster_peer_id": {
+							"type": "keyword"
+						},
+						"cluster_address": {
+							"type": "keyword"
+						},
+						"cluster_state": {
+							"type": "keyword"
+						},
+						"cluster_error": {
+							"type": "keyword"
+						},
+						"cluster_members": {
+							"type": "keyword"
+						}
+					}
+				}
+			}`,
+			"raft_member": `{
+				"settings": {
+					"number_of_shards": 1,
+					"number_of_replicas": 1,
+					"index.max_result_window": 100000
+				},
+				"mappings": {
+					"properties": {
+						"blockno": {
+							"type": "long"
+						},
+						"ts": {
+							"type": "date"
+						},
+						"event": {
+							"type": "keyword"
+						},
+						"name": {
+							"type": "keyword"
+						},
+						"raft_id": {
+							"type": "keyword"
+						},
+						"peer_id": {
+							"type": "keyword"
+						},
+						"address": {
+							"type": "keyword"
+						},
+						"leader": {
+							"type": "keyword"
+						},
+						"members": {
+							"type": "keyword"
+						},
+						"member_names": {
+							"type": "keyword"
+						},
+						"tx": {
+							"type": "keyword"
+						}
+					}
+				}
+			}`,
+			"contract_abi": `{
+				"settings": {
+					"number_of_shards": 1,
+					"number_of_replicas": 1,
+					"index.max_result_window": 100000
+				},
+				"mappings": {
+					"properties": {
+						"contract": {
+							"type": "keyword"
+						},
+						"tx_id": {
+							"type": "keyword"
+						},
+						"blockno": {
+							"type": "long"
+						},
+						"ts": {
+							"type": "date"
+						},
+						"language": {
+							"type": "keyword"
+						},
+						"version": {
+							"type": "keyword"
+						},
+						"functions": {
+							"type": "nested",
+							"properties": {
+								"name": {
+									"type": "keyword"
+								},
+								"arguments": {
+									"type": "keyword"
+								},
+								"payable": {
+									"type": "boolean"
+								},
+								"view": {
+									"type": "boolean"
+								},
+								"fee_delegation": {
+									"type": "boolean"
+								}
+							}
+						},
+						"state_variables": {
+							"type": "nested",
+							"properties": {
+								"name": {
+									"type": "keyword"
+								},
+								"type": {
+									"type": "keyword"
+								},
+								"len": {
+									"type": "integer"
+								}
+							}
+						}
+					}
+				}
+			}`,
+			"contract_version": `{
+				"settings": {
+					"number_of_shards": 1,
+					"number_of_replicas": 1,
+					"index.max_result_window": 100000
+				},
+				"mappings": {
+					"properties": {
+						"contract": {
+							"type": "keyword"
+						},
+						"tx_id": {
+							"type": "keyword"
+						},
+						"blockno": {
+							"type": "long"
+						},
+						"ts": {
+							"type": "date"
+						},
+						"deployer": {
+							"type": "keyword"
+						},
+						"category": {
+							"type": "keyword"
+						},
+						"payload": {
+							"enabled": false
+						},
+						"payload_hash": {
+							"type": "keyword"
+						},
+						"code_hash": {
+							"type": "keyword"
+						},
+						"abi_id": {
+							"type": "keyword"
+						},
+						"abi_functions": {
+							"type": "keyword"
+						}
+					}
+				}
+			}`,
+			"nft_history": `{
+				"settings": {
+					"number_of_shards": 3,
+					"number_of_replicas": 1,
+					"index.max_result_window": 100000
+				},
+				"mappings": {
+					"properties": {
+						"nft_id": {
+							"type": "keyword"
+						},
+						"address": {
 							"type": "keyword"
 						},
 						"token_id": {
 							"type": "keyword"
 						},
+						"event": {
+							"type": "keyword"
+						},
+						"from": {
+							"type": "keyword"
+						},
+						"to": {
+							"type": "keyword"
+						},
+						"tx_id": {
+							"type": "keyword"
+						},
 						"blockno": {
 							"type": "long"
 						},
 						"ts": {
 							"type": "date"
 						},
-						"account": {
+						"order": {
+							"type": "long"
+						}
+					}
+				}
+			}`,
+			"token_stats": `{
+				"settings": {
+					"number_of_shards": 1,
+					"number_of_replicas": 1,
+					"index.max_result_window": 100000
+				},
+				"mappings": {
+					"properties": {
+						"address": {
 							"type": "keyword"
 						},
-						"token_uri": {
+						"period": {
 							"type": "keyword"
 						},
-						"image_url": {
+						"ts": {
+							"type": "date"
+						},
+						"transfer_count": {
+							"type": "long"
+						},
+						"senders": {
+							"type": "long"
+						},
+						"receivers": {
+							"type": "long"
+						},
+						"volume": {
+							"enabled": false
+						},
+						"volume_float": {
+							"type": "double"
+						},
+						"mint_count": {
+							"type": "long"
+						},
+						"mint_amount": {
+							"enabled": false
+						},
+						"mint_amount_float": {
+							"type": "double"
+						},
+						"burn_count": {
+							"type": "long"
+						},
+						"burn_amount": {
+							"enabled": false
+						},
+						"burn_amount_float": {
+							"type": "double"
+						}
+					}
+				}
+			}`,
+			"chain_stats": `{
+				"settings": {
+					"number_of_shards": 1,
+					"number_of_replicas": 1,
+					"index.max_result_window": 100000
+				},
+				"mappings": {
+					"properties": {
+						"period": {
 							"type": "keyword"
+						},
+						"ts": {
+							"type": "date"
+						},
+						"block_count": {
+							"type": "long"
+						},
+						"tx_count": {
+							"type": "long"
+						},
+						"tx_categories": {
+							"type": "nested",
+							"properties": {
+								"category": {
+									"type": "keyword"
+								},
+								"count": {
+									"type": "long"
+								}
+							}
+						},
+						"active_accounts": {
+							"type": "long"
+						},
+						"new_accounts": {
+							"type": "long"
+						},
+						"new_contracts": {
+							"type": "long"
+						},
+						"new_tokens": {
+							"type": "long"
+						},
+						"gas_used": {
+							"type": "long"
+						},
+						"fees_burned": {
+							"enabled": false
+						},
+						"fees_burned_float": {
+							"type": "double"
 						}
 					}
 				}
 			}`,
-			"whitelist": `{
+			"chain_param": `{
 				"settings": {
-					"number_of_shards": 10,
-					"number_of_replicas": 1
+					"number_of_shards": 1,
+					"number_of_replicas": 1,
+					"index.max_result_window": 100000
+				},
+				"mappings": {
+					"properties": {
+						"name": {
+							"type": "keyword"
+						},
+						"blockno": {
+							"type": "long"
+						},
+						"ts": {
+							"type": "date"
+						},
+						"value": {
+							"type": "keyword"
+						},
+						"value_float": {
+							"type": "float"
+						}
+					}
+				}
+			}`,
+			"bp_stats": `{
+				"settings": {
+					"number_of_shards": 1,
+					"number_of_replicas": 1,
+					"index.max_result_window": 100000
+				},
+				"mappings": {
+					"properties": {
+						"bp": {
+							"type": "keyword"
+						},
+						"blocks": {
+							"type": "long"
+						},
+						"first_block": {
+							"type": "long"
+						},
+						"first_ts": {
+							"type": "date"
+						},
+						"last_block": {
+							"type": "long"
+						},
+						"last_ts": {
+							"type": "date"
+						},
+						"total_size": {
+							"type": "long"
+						},
+						"total_txs": {
+							"type": "long"
+						},
+						"avg_size": {
+							"type": "double"
+						},
+						"avg_txs": {
+							"type": "double"
+						},
+						"reward": {
+							"enabled": false
+						},
+						"reward_float": {
+							"type": "float"
+						},
+						"missed_slots": {
+							"type": "long"
+						}
+					}
+				}
+			}`,
+			"fee_sponsor": `{
+				"settings": {
+					"number_of_shards": 1,
+					"number_of_replicas": 1,
+					"index.max_result_window": 100000
 				},
 				"mappings": {
 					"properties": {
 						"contract": {
 							"type": "keyword"
 						},
-						"type": {
+						"account": {
+							"type": "keyword"
+						},
+						"method": {
 							"type": "keyword"
+						},
+						"tx_count": {
+							"type": "long"
+						},
+						"gas_used": {
+							"type": "long"
+						},
+						"fee": {
+							"enabled": false
+						},
+						"fee_float": {
+							"type": "double"
+						},
+						"blockno": {
+							"type": "long"
+						},
+						"ts": {
+							"type": "date"
 						}
 					}
 				}
@@ -0,0 +1,299 @@
+package documents
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/aergoio/aergo-indexer-2.0/indexer/transaction"
+)
+
+// types of fields in custom event index
+const (
+	FieldKeyword = "keyword"
+	FieldLong    = "long"
+	FieldDouble  = "double"
+	FieldBoolean = "boolean"
+	FieldBignum  = "bignum" // exact integer string, with <name>_float for range queries
+
+	// AnyContract matches events of all contracts
+	AnyContract = "*"
+	// DefaultEventIndexId is the id template used if not set, which is unique per event
+	DefaultEventIndexId = "{blockno}-{tx_idx}-{event_idx}"
+)
+
+var (
+	eventIndexName  = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	idPlaceholder   = regexp.MustCompile(`\{([a-z0-9_]*)\}`)
+	eventBaseFields = []string{"contract", "blockno", "ts", "tx_id", "tx_idx", "event_idx", "event_name"}
+)
+
+// EventIndexConfig declares custom indices which are populated with events
+type EventIndexConfig struct {
+	Indices []*EventIndexSpec `json:"indices"`
+}
+
+// EventIndexSpec declares a custom index and the events which go into it
+type EventIndexSpec struct {
+	Index     string            `json:"index"`     // type name of index, created with the prefix of other indices
+	Contracts []string          `json:"contracts"` // contract addresses, or "*" for all contracts
+	Event     string            `json:"event"`     // event name
+	Id        string            `json:"id"`        // id template of documents, e.g. "{tx_id}-{event_idx}"
+	Fields    []EventIndexField `json:"fields"`
+}
+
+// EventIndexField is a field extracted from event arguments
+type EventIndexField struct {
+	Name string `json:"name"`
+	Path string `json:"path"` // position of argument followed by keys of objects or indices of arrays, e.g. "1.amount"
+	Type string `json:"type"`
+}
+
+// LoadEventIndexConfig reads and validates the config file of custom event indices
+func LoadEventIndexConfig(path string) (*EventIndexConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseEventIndexConfig(data)
+}
+
+// ParseEventIndexConfig decodes and validates the config of custom event indices
+func ParseEventIndexConfig(data []byte) (*EventIndexConfig, error) {
+	config := new(EventIndexConfig)
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+	indices := make(map[string]bool)
+	for _, spec := range config.Indices {
+		if err := spec.Validate(); err != nil {
+			return nil, err
+		}
+		if indices[spec.Index] == true {
+			return nil, fmt.Errorf("duplicated event index: %s", spec.Index)
+		}
+		indices[spec.Index] = true
+	}
+	return config, nil
+}
+
+// Validate checks the spec, and sets the default id template if not set
+func (s *EventIndexSpec) Validate() error {
+	if eventIndexName.MatchString(s.Index) != true {
+		return fmt.Errorf("invalid event index name: %q", s.Index)
+	}
+	if s.Event == "" {
+		return fmt.Errorf("event name is not set: %s", s.Index)
+	}
+	if len(s.Contracts) == 0 {
+		return fmt.Errorf("contracts are not set: %s", s.Index)
+	}
+	// names are not accepted, since the contract of a name changes over blocks while events carry the address
+	for _, contract := range s.Contracts {
+		if contract != AnyContract && transaction.IsAddress(contract) != true && transaction.IsInternalName(contract) != true {
+			return fmt.Errorf("invalid contract address: %s %q", s.Index, contract)
+		}
+	}
+
+	names := make(map[string]bool)
+	for _, name := range eventBaseFields {
+		names[name] = true
+	}
+	for _, field := range s.Fields {
+		if eventIndexName.MatchString(field.Name) != true || names[field.Name] == true {
+			return fmt.Errorf("invalid field name: %s.%q", s.Index, field.Name)
+		}
+		names[field.Name] = true
+		switch field.Type {
+		case FieldKeyword, FieldLong, FieldDouble, FieldBoolean:
+		case FieldBignum:
+			if names[field.Name+"_float"] == true {
+				return fmt.Errorf("invalid field name: %s.%q", s.Index, field.Name+"_float")
+			}
+			names[field.Name+"_float"] = true
+		default:
+			return fmt.Errorf("invalid field type: %s.%s %q", s.Index, field.Name, field.Type)
+		}
+		if field.Path == "" {
+			return fmt.Errorf("path is not set: %s.%s", s.Index, field.Name)
+		}
+	}
+
+	if s.Id == "" {
+		s.Id = DefaultEventIndexId
+	}
+	for _, match := range idPlaceholder.FindAllStringSubmatch(s.Id, -1) {
+		if names[match[1]] != true {
+			return fmt.Errorf("invalid id placeholder: %s %q", s.Index, match[0])
+		}
+	}
+	return nil
+}
+
+// Matches checks if the event goes into the index
+func (s *EventIndexSpec) Matches(eventDoc *EsEvent) bool {
+	if eventDoc.EventName != s.Event {
+		return false
+	}
+	for _, contract := range s.Contracts {
+		if contract == AnyContract || contract == eventDoc.Contract {
+			return true
+		}
+	}
+	return false
+}
+
+// Mapping returns the index mapping of the spec
+func (s *EventIndexSpec) Mapping() string {
+	properties := map[string]interface{}{
+		"contract":   map[string]string{"type": "keyword"},
+		"blockno":    map[string]string{"type": "long"},
+		"ts":         map[string]string{"type": "date"},
+		"tx_id":      map[string]string{"type": "keyword"},
+		"tx_idx":     map[string]string{"type": "long"},
+		"event_idx":  map[string]string{"type": "long"},
+		"event_name": map[string]string{"type": "keyword"},
+	}
+	for _, field := range s.Fields {
+		if field.Type == FieldBignum {
+			properties[field.Name] = map[string]bool{"enabled": false}
+			properties[field.Name+"_float"] = map[string]string{"type": "double"}
+		} else {
+			properties[field.Name] = map[string]string{"type": field.Type}
+		}
+	}
+	mapping, _ := json.Marshal(map[string]interface{}{
+		"settings": map[string]interface{}{
+			"number_of_shards":        1,
+			"number_of_replicas":      1,
+			"index.max_result_window": 100000,
+		},
+		"mappings": map[string]interface{}{
+			"properties": properties,
+		},
+	})
+	return string(mapping)
+}
+
+// RegisterEventIndex adds the mapping of custom index. returns an error if the name is taken by another index
+func RegisterEventIndex(spec *EventIndexSpec) error {
+	mapping := spec.Mapping()
+	if registered, exist := EsMappings[spec.Index]; exist == true && registered != mapping {
+		return fmt.Errorf("event index name is already used: %s", spec.Index)
+	}
+	EsMappings[spec.Index] = mapping
+	return nil
+}
+
+// ConvEventIndex converts an event into the document of custom index. fields which are missing or not convertible are left out.
+// returns an error if a field of the id is missing, since the id would collide with ids of other events
+func ConvEventIndex(spec *EventIndexSpec, eventDoc *EsEvent, ts time.Time) (*EsEventIndex, error) {
+	fields := map[string]interface{}{
+		"contract":   eventDoc.Contract,
+		"blockno":    eventDoc.BlockNo,
+		"ts":         ts,
+		"tx_id":      eventDoc.TxId,
+		"tx_idx":     eventDoc.TxIdx,
+		"event_idx":  eventDoc.EventIdx,
+		"event_name": eventDoc.EventName,
+	}
+	args, _ := transaction.UnmarshalJsonArgs(eventDoc.EventArgs)
+	for _, field := range spec.Fields {
+		arg, ok := transaction.ArgAtPath(args, field.Path)
+		if !ok {
+			continue
+		}
+		value, ok := convEventIndexField(field.Type, arg)
+		if !ok {
+			continue
+		}
+		if bignum, isBignum := value.(*big.Int); isBignum {
+			fields[field.Name] = bignum.String()
+			fields[field.Name+"_float"], _ = new(big.Float).SetInt(bignum).Float64()
+		} else {
+			fields[field.Name] = value
+		}
+	}
+
+	for _, match := range idPlaceholder.FindAllStringSubmatch(spec.Id, -1) {
+		if _, exist := fields[match[1]]; !exist {
+			return nil, fmt.Errorf("id field is missing: %s.%s", spec.Index, match[1])
+		}
+	}
+	id := idPlaceholder.ReplaceAllStringFunc(spec.Id, func(placeholder string) string {
+		return fmt.Sprint(fields[placeholder[1:len(placeholder)-1]])
+	})
+	return &EsEventIndex{
+		BaseEsType: &BaseEsType{Id: id},
+		Fields:     fields,
+	}, nil
+}
+
+func convEventIndexField(fieldType string, arg interface{}) (interface{}, bool) {
+	switch fieldType {
+	case FieldKeyword:
+		switch data := arg.(type) {
+		case string:
+			return data, true
+		case json.Number:
+			return data.String(), true
+		case bool:
+			return strconv.FormatBool(data), true
+		case map[string]interface{}:
+			if bignum, ok := transaction.ConvertBignumJson(data); ok {
+				return bignum.String(), true
+			}
+		}
+		encoded, err := json.Marshal(arg)
+		return string(encoded), err == nil
+	case FieldLong:
+		switch data := arg.(type) {
+		case string:
+			value, err := strconv.ParseInt(data, 10, 64)
+			return value, err == nil
+		case json.Number:
+			value, err := data.Int64()
+			return value, err == nil
+		case map[string]interface{}:
+			if bignum, ok := transaction.ConvertBignumJson(data); ok && bignum.IsInt64() {
+				return bignum.Int64(), true
+			}
+		}
+	case FieldDouble:
+		switch data := arg.(type) {
+		case string:
+			value, err := strconv.ParseFloat(data, 64)
+			return value, err == nil
+		case json.Number:
+			value, err := data.Float64()
+			return value, err == nil
+		case map[string]interface{}:
+			if bignum, ok := transaction.ConvertBignumJson(data); ok {
+				value, _ := new(big.Float).SetInt(bignum).Float64()
+				return value, true
+			}
+		}
+	case FieldBoolean:
+		switch data := arg.(type) {
+		case bool:
+			return data, true
+		case string:
+			value, err := strconv.ParseBool(data)
+			return value, err == nil
+		}
+	case FieldBignum:
+		switch data := arg.(type) {
+		case string:
+			return new(big.Int).SetString(data, 10)
+		case json.Number:
+			return new(big.Int).SetString(data.String(), 10)
+		case map[string]interface{}:
+			return transaction.ConvertBignumJson(data)
+		}
+	}
+	return nil, false
+}
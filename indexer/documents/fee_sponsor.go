@@ -0,0 +1,68 @@
+package documents
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// NewFeeSponsor returns empty fees sponsored by the contract for the txs of account calling method. the total of the contract has empty account and method
+func NewFeeSponsor(contract string, account string, method string) *EsFeeSponsor {
+	id := contract
+	if account != "" || method != "" {
+		id = fmt.Sprintf("%s-%s-%s", contract, account, method)
+	}
+	return &EsFeeSponsor{
+		BaseEsType: &BaseEsType{Id: id},
+		Contract:   contract,
+		Account:    account,
+		Method:     method,
+		Fee:        "0",
+	}
+}
+
+// FeeSponsorsOf returns empty fees sponsored for the tx, in total and for its account and method. nil if the tx is not fee delegated
+func FeeSponsorsOf(txDoc *EsTx) []*EsFeeSponsor {
+	if txDoc.FeeDelegation != true || txDoc.FeePayer == "" {
+		return nil
+	}
+	return []*EsFeeSponsor{
+		NewFeeSponsor(txDoc.FeePayer, "", ""),
+		NewFeeSponsor(txDoc.FeePayer, txDoc.Account, txDoc.Method),
+	}
+}
+
+// Apply adds the fee of a fee delegated tx
+func (s *EsFeeSponsor) Apply(txDoc *EsTx) {
+	s.TxCount++
+	s.GasUsed += txDoc.GasUsed
+	if txDoc.BlockNo >= s.LastBlock {
+		s.LastBlock, s.LastTs = txDoc.BlockNo, txDoc.Timestamp
+	}
+	s.addFee(txDoc.FeeUsed, 1)
+}
+
+// Revert removes the fee of a fee delegated tx. the last block is left to the caller, since it needs the other txs
+func (s *EsFeeSponsor) Revert(txDoc *EsTx) {
+	if s.TxCount == 0 {
+		return
+	}
+	s.TxCount--
+	s.GasUsed -= min64(s.GasUsed, txDoc.GasUsed)
+	s.addFee(txDoc.FeeUsed, -1)
+}
+
+func (s *EsFeeSponsor) addFee(amount string, sign int64) {
+	fee, ok := new(big.Int).SetString(amount, 10)
+	if !ok {
+		return
+	}
+	total, ok := new(big.Int).SetString(s.Fee, 10)
+	if !ok {
+		total = big.NewInt(0)
+	}
+	total.Add(total, fee.Mul(fee, big.NewInt(sign)))
+	if total.Sign() < 0 {
+		total.SetInt64(0)
+	}
+	s.Fee, s.FeeFloat = total.String(), adjustDecimals(total, 18)
+}
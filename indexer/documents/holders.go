@@ -0,0 +1,121 @@
+package documents
+
+import (
+	"math/big"
+	"sort"
+)
+
+const DefaultTopHolders = 10
+
+// TokenHolders tracks the holder count and the top holders of a token from balance changes of account tokens
+type TokenHolders struct {
+	Size  int              // max number of top holders
+	Count uint64           // number of accounts with positive balance
+	Top   []*EsTokenHolder // top holders sorted by balance desc
+	Stale bool             // top holders may miss an account, so they need a recount
+}
+
+// NewTokenHolders returns the tracker starting from the holder values of token doc
+func NewTokenHolders(size int, count uint64, top []*EsTokenHolder) *TokenHolders {
+	if size <= 0 {
+		size = DefaultTopHolders
+	}
+	holders := &TokenHolders{Size: size, Count: count}
+	for _, holder := range top {
+		if holder != nil && len(holders.Top) < size {
+			copied := *holder
+			holders.Top = append(holders.Top, &copied)
+		}
+	}
+	holders.sort()
+	return holders
+}
+
+// Apply applies the balance change of an account. prev is nil if the account had no balance before
+func (h *TokenHolders) Apply(prev, next *EsAccountTokens) {
+	wasHolder := prev != nil && balanceOf(prev.Balance).Sign() > 0
+	isHolder := balanceOf(next.Balance).Sign() > 0
+	if !wasHolder && isHolder {
+		h.Count++
+	} else if wasHolder && !isHolder && h.Count > 0 {
+		h.Count--
+	}
+
+	idx := -1
+	for i, holder := range h.Top {
+		if holder.Account == next.Account {
+			idx = i
+			break
+		}
+	}
+
+	switch {
+	case idx >= 0 && !isHolder:
+		// an account out of the list may take the empty place
+		h.Top = append(h.Top[:idx], h.Top[idx+1:]...)
+		if h.Count > uint64(len(h.Top)) {
+			h.Stale = true
+		}
+	case idx >= 0:
+		// an account out of the list may exceed the decreased balance
+		if compareBalance(next.Balance, h.Top[idx].Balance) < 0 && h.Count > uint64(len(h.Top)) {
+			h.Stale = true
+		}
+		h.Top[idx].Balance, h.Top[idx].BalanceFloat = next.Balance, next.BalanceFloat
+	case isHolder:
+		if len(h.Top) >= h.Size && compareBalance(next.Balance, h.Top[len(h.Top)-1].Balance) <= 0 {
+			return
+		}
+		h.Top = append(h.Top, &EsTokenHolder{Account: next.Account, Balance: next.Balance, BalanceFloat: next.BalanceFloat})
+	default:
+		return
+	}
+	h.sort()
+	if len(h.Top) > h.Size {
+		h.Top = h.Top[:h.Size]
+	}
+}
+
+// Reset replaces the holder values with a full recount
+func (h *TokenHolders) Reset(count uint64, top []*EsAccountTokens) {
+	h.Count, h.Top, h.Stale = count, nil, false
+	for _, accountTokens := range top {
+		if balanceOf(accountTokens.Balance).Sign() > 0 && len(h.Top) < h.Size {
+			h.Top = append(h.Top, &EsTokenHolder{Account: accountTokens.Account, Balance: accountTokens.Balance, BalanceFloat: accountTokens.BalanceFloat})
+		}
+	}
+	h.sort()
+}
+
+func (h *TokenHolders) sort() {
+	sort.SliceStable(h.Top, func(i, j int) bool {
+		return compareBalance(h.Top[i].Balance, h.Top[j].Balance) > 0
+	})
+}
+
+// balanceOf returns the exact balance. balance_float is not compared, since it loses precision
+func balanceOf(balance string) *big.Int {
+	value, ok := new(big.Int).SetString(balance, 10)
+	if !ok {
+		return new(big.Int)
+	}
+	return value
+}
+
+func compareBalance(a, b string) int {
+	return balanceOf(a).Cmp(balanceOf(b))
+}
+
+// share returns balance / supply. returns 0 if supply is unknown
+func share(balance, supply string) float32 {
+	total, ok := new(big.Float).SetString(supply)
+	if !ok || total.Sign() <= 0 {
+		return 0
+	}
+	amount, ok := new(big.Float).SetString(balance)
+	if !ok {
+		return 0
+	}
+	ratio, _ := new(big.Float).Quo(amount, total).Float32()
+	return ratio
+}
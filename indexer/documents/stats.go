@@ -0,0 +1,277 @@
+package documents
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aergoio/aergo-indexer-2.0/indexer/transaction"
+)
+
+// StatsPeriod is the time unit of rollups
+type StatsPeriod string
+
+const (
+	PeriodHour StatsPeriod = "hour"
+	PeriodDay  StatsPeriod = "day"
+)
+
+var StatsPeriods = []StatsPeriod{PeriodHour, PeriodDay}
+
+// Start returns the start of the period which ts belongs to, in UTC
+func (p StatsPeriod) Start(ts time.Time) time.Time {
+	ts = ts.UTC()
+	if p == PeriodDay {
+		return time.Date(ts.Year(), ts.Month(), ts.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return ts.Truncate(time.Hour)
+}
+
+// End returns the start of the next period
+func (p StatsPeriod) End(start time.Time) time.Time {
+	if p == PeriodDay {
+		return start.AddDate(0, 0, 1)
+	}
+	return start.Add(time.Hour)
+}
+
+// StatsId returns the id of a rollup document of the key in the period
+func StatsId(key string, period StatsPeriod, start time.Time) string {
+	return fmt.Sprintf("%s-%s-%d", key, period, start.Unix())
+}
+
+// ChainStatsAcc accumulates the chain activity of a period block by block. new accounts, contracts and tokens are counted by the caller
+type ChainStatsAcc struct {
+	Period      StatsPeriod
+	Start       time.Time
+	FirstBlock  uint64 // block range accumulated
+	LastBlock   uint64
+	blocks      uint64
+	txs         uint64
+	gasUsed     uint64
+	newAccounts uint64
+	fees        *big.Int
+	senders     map[string]bool
+	accounts    map[string]bool // senders and recipients seen in the period
+	categories  map[transaction.TxCategory]uint64
+}
+
+// NewChainStatsAcc returns an empty accumulator of the period
+func NewChainStatsAcc(period StatsPeriod, start time.Time) *ChainStatsAcc {
+	return &ChainStatsAcc{
+		Period:     period,
+		Start:      start,
+		fees:       big.NewInt(0),
+		senders:    make(map[string]bool),
+		accounts:   make(map[string]bool),
+		categories: make(map[transaction.TxCategory]uint64),
+	}
+}
+
+// AccumulateBlock adds a block mined after the blocks added. returns false if the block is added already, such as a block mined again
+func (a *ChainStatsAcc) AccumulateBlock(blockDoc *EsBlock) bool {
+	if a.blocks > 0 && blockDoc.BlockNo <= a.LastBlock {
+		return false
+	}
+	a.AddBlock(blockDoc)
+	return true
+}
+
+// AddBlock adds a block of the period
+func (a *ChainStatsAcc) AddBlock(blockDoc *EsBlock) {
+	if a.blocks == 0 || blockDoc.BlockNo < a.FirstBlock {
+		a.FirstBlock = blockDoc.BlockNo
+	}
+	if blockDoc.BlockNo > a.LastBlock {
+		a.LastBlock = blockDoc.BlockNo
+	}
+	a.blocks++
+}
+
+// AddTx adds a tx of the period. returns the sender and recipient seen first in the period, which can be new accounts
+func (a *ChainStatsAcc) AddTx(txDoc *EsTx) (unseen []string) {
+	a.txs++
+	a.senders[txDoc.Account] = true
+	a.categories[txDoc.Category]++
+	a.gasUsed += txDoc.GasUsed
+	if fee, ok := new(big.Int).SetString(txDoc.FeeUsed, 10); ok {
+		a.fees.Add(a.fees, fee)
+	}
+	for _, account := range []string{txDoc.Account, txDoc.Recipient} {
+		if account == "" || a.accounts[account] == true {
+			continue
+		}
+		a.accounts[account] = true
+		unseen = append(unseen, account)
+	}
+	return unseen
+}
+
+// AddNewAccounts adds accounts which sent or received the first tx in the period
+func (a *ChainStatsAcc) AddNewAccounts(count uint64) {
+	a.newAccounts += count
+}
+
+// Stats returns the rollup of the period
+func (a *ChainStatsAcc) Stats(newContracts, newTokens uint64) *EsChainStats {
+	chainStatsDoc := &EsChainStats{
+		BaseEsType:     &BaseEsType{Id: StatsId("chain", a.Period, a.Start)},
+		Period:         a.Period,
+		Timestamp:      a.Start,
+		BlockCount:     a.blocks,
+		TxCount:        a.txs,
+		ActiveAccounts: uint64(len(a.senders)),
+		NewAccounts:    a.newAccounts,
+		NewContracts:   newContracts,
+		NewTokens:      newTokens,
+		GasUsed:        a.gasUsed,
+	}
+	chainStatsDoc.TxCategories = make([]*EsCategoryCount, 0, len(a.categories))
+	for category, count := range a.categories {
+		chainStatsDoc.TxCategories = append(chainStatsDoc.TxCategories, &EsCategoryCount{Category: category, Count: count})
+	}
+	sort.Slice(chainStatsDoc.TxCategories, func(i, j int) bool {
+		return chainStatsDoc.TxCategories[i].Category < chainStatsDoc.TxCategories[j].Category
+	})
+	chainStatsDoc.FeesBurned, chainStatsDoc.FeesBurnedFloat = a.fees.String(), adjustDecimals(a.fees, 18)
+	return chainStatsDoc
+}
+
+// TokenStatsAcc accumulates the transfers of a token in a period transfer by transfer
+type TokenStatsAcc struct {
+	TokenAddress string
+	Period       StatsPeriod
+	Start        time.Time
+	transfers    uint64
+	mints        uint64
+	burns        uint64
+	volume       *big.Int
+	minted       *big.Int
+	burned       *big.Int
+	senders      map[string]bool
+	receivers    map[string]bool
+	lastBlock    uint64          // last block of transfers added
+	lastIds      map[string]bool // transfers added in the last block
+}
+
+// NewTokenStatsAcc returns an empty accumulator of the token in the period
+func NewTokenStatsAcc(tokenAddress string, period StatsPeriod, start time.Time) *TokenStatsAcc {
+	return &TokenStatsAcc{
+		TokenAddress: tokenAddress,
+		Period:       period,
+		Start:        start,
+		volume:       big.NewInt(0),
+		minted:       big.NewInt(0),
+		burned:       big.NewInt(0),
+		senders:      make(map[string]bool),
+		receivers:    make(map[string]bool),
+		lastIds:      make(map[string]bool),
+	}
+}
+
+// AccumulateTransfer adds a transfer mined after the transfers added. returns false if the transfer is added already, such as a transfer of a block mined again
+func (a *TokenStatsAcc) AccumulateTransfer(transfer *EsTokenTransfer) bool {
+	if transfer.BlockNo < a.lastBlock || (transfer.BlockNo == a.lastBlock && a.lastIds[transfer.Id] == true) {
+		return false
+	}
+	a.AddTransfer(transfer)
+	return true
+}
+
+// AddTransfer adds a transfer of the token in the period
+func (a *TokenStatsAcc) AddTransfer(transfer *EsTokenTransfer) {
+	if transfer.BlockNo > a.lastBlock {
+		a.lastBlock = transfer.BlockNo
+		a.lastIds = make(map[string]bool)
+	}
+	if transfer.BlockNo == a.lastBlock {
+		a.lastIds[transfer.Id] = true
+	}
+
+	// amount of nft is the owner, so count a token id as 1
+	amount, ok := new(big.Int).SetString(transfer.Amount, 10)
+	if transfer.TokenId != "" {
+		amount = big.NewInt(1)
+	} else if !ok {
+		amount = big.NewInt(0)
+	}
+	switch {
+	case transfer.From == "MINT":
+		a.mints++
+		a.minted.Add(a.minted, amount)
+	case transfer.To == "BURN":
+		a.burns++
+		a.burned.Add(a.burned, amount)
+	default:
+		a.transfers++
+		a.volume.Add(a.volume, amount)
+		a.senders[transfer.From] = true
+		a.receivers[transfer.To] = true
+	}
+}
+
+// Stats returns the rollup of the token in the period, with amounts adjusted by decimals
+func (a *TokenStatsAcc) Stats(decimals uint8) *EsTokenStats {
+	tokenStatsDoc := &EsTokenStats{
+		BaseEsType:    &BaseEsType{Id: StatsId(a.TokenAddress, a.Period, a.Start)},
+		TokenAddress:  a.TokenAddress,
+		Period:        a.Period,
+		Timestamp:     a.Start,
+		TransferCount: a.transfers,
+		Senders:       uint64(len(a.senders)),
+		Receivers:     uint64(len(a.receivers)),
+		MintCount:     a.mints,
+		BurnCount:     a.burns,
+	}
+	tokenStatsDoc.Volume, tokenStatsDoc.VolumeFloat = a.volume.String(), adjustDecimals(a.volume, decimals)
+	tokenStatsDoc.MintAmount, tokenStatsDoc.MintAmountFloat = a.minted.String(), adjustDecimals(a.minted, decimals)
+	tokenStatsDoc.BurnAmount, tokenStatsDoc.BurnAmountFloat = a.burned.String(), adjustDecimals(a.burned, decimals)
+	return tokenStatsDoc
+}
+
+const (
+	// PaddedDecimals is the decimals of padded amounts, so that amounts of tokens with different decimals compare
+	PaddedDecimals = 18
+	// PaddedLength is the length of padded amounts, which fits uint256 with PaddedDecimals
+	PaddedLength = 96
+)
+
+// PadAmount returns amount / 10^decimals in units of 10^-PaddedDecimals, zero-padded to PaddedLength so that the strings sort as numbers.
+// returns "" for negative or too large amounts
+func PadAmount(amount *big.Int, decimals uint8) string {
+	if amount.Sign() < 0 {
+		return ""
+	}
+	scaled := new(big.Int).Set(amount)
+	if decimals < PaddedDecimals {
+		scaled.Mul(scaled, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(PaddedDecimals-decimals)), nil))
+	} else if decimals > PaddedDecimals {
+		scaled.Quo(scaled, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals-PaddedDecimals)), nil))
+	}
+	digits := scaled.String()
+	if len(digits) > PaddedLength {
+		return ""
+	}
+	return strings.Repeat("0", PaddedLength-len(digits)) + digits
+}
+
+// padAmountOf returns the padded amount of a BigInt string, or "" if it is not a number
+func padAmountOf(amount string, decimals uint8) string {
+	value, ok := new(big.Int).SetString(amount, 10)
+	if !ok {
+		return ""
+	}
+	return PadAmount(value, decimals)
+}
+
+// adjustDecimals returns amount / 10^decimals
+func adjustDecimals(amount *big.Int, decimals uint8) float64 {
+	value := new(big.Float).SetInt(amount)
+	if decimals > 0 {
+		value.Quo(value, new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)))
+	}
+	adjusted, _ := value.Float64()
+	return adjusted
+}
@@ -2,9 +2,11 @@ package indexer
 
 import (
 	"io"
+	"time"
 
 	"github.com/aergoio/aergo-indexer-2.0/indexer/db"
 	doc "github.com/aergoio/aergo-indexer-2.0/indexer/documents"
+	"github.com/aergoio/aergo-indexer-2.0/indexer/transaction"
 )
 
 func (ns *Indexer) addBlock(blockType BlockType, blockDoc *doc.EsBlock) {
@@ -67,10 +69,12 @@ func (ns *Indexer) addToken(tokenDoc *doc.EsToken) {
 
 func (ns *Indexer) addAccountTokens(blockType BlockType, accountTokensDoc *doc.EsAccountTokens) {
 	if blockType == BlockType_Bulk {
+		ns.cache.markHolders(accountTokensDoc.TokenAddress)
 		if ns.cache.getAccTokens(accountTokensDoc.Id) != true {
 			ns.bulk.BChannel.AccTokens <- ChanInfo{ChanType_Add, accountTokensDoc}
 		}
 	} else {
+		ns.MinerTokenHolders(accountTokensDoc)
 		err := ns.db.Insert(accountTokensDoc, ns.indexNamePrefix+"account_tokens")
 		if err != nil {
 			ns.log.Error().Err(err).Str("Id", accountTokensDoc.Id).Str("method", "insertAccountTokens").Msg("error while insert")
@@ -100,8 +104,10 @@ func (ns *Indexer) addAccountBalance(balanceDoc *doc.EsAccountBalance) {
 
 func (ns *Indexer) addTokenTransfer(blockType BlockType, tokenTransferDoc *doc.EsTokenTransfer) {
 	if blockType == BlockType_Bulk {
+		ns.markStats("token_stats", tokenTransferDoc.TokenAddress, tokenTransferDoc.Timestamp, tokenTransferDoc.BlockNo)
 		ns.bulk.BChannel.TokenTransfer <- ChanInfo{ChanType_Add, tokenTransferDoc}
 	} else {
+		ns.accumulateTokenStats(tokenTransferDoc)
 		err := ns.db.Insert(tokenTransferDoc, ns.indexNamePrefix+"token_transfer")
 		if err != nil {
 			ns.log.Error().Err(err).Str("Id", tokenTransferDoc.Id).Str("method", "insertTokenTransfer").Msg("error while insert")
@@ -109,6 +115,109 @@ func (ns *Indexer) addTokenTransfer(blockType BlockType, tokenTransferDoc *doc.E
 	}
 }
 
+func (ns *Indexer) addTokenStats(tokenStatsDoc *doc.EsTokenStats) {
+	err := ns.db.Insert(tokenStatsDoc, ns.indexNamePrefix+"token_stats")
+	if err != nil {
+		ns.log.Error().Err(err).Str("Id", tokenStatsDoc.Id).Str("method", "insertTokenStats").Msg("error while insert")
+	}
+}
+
+func (ns *Indexer) addChainParam(paramDoc *doc.EsChainParam) {
+	err := ns.db.Insert(paramDoc, ns.indexNamePrefix+"chain_param")
+	if err != nil {
+		ns.log.Error().Err(err).Str("Id", paramDoc.Id).Str("method", "insertChainParam").Msg("error while insert")
+	}
+}
+
+func (ns *Indexer) addChainStats(chainStatsDoc *doc.EsChainStats) {
+	err := ns.db.Insert(chainStatsDoc, ns.indexNamePrefix+"chain_stats")
+	if err != nil {
+		ns.log.Error().Err(err).Str("Id", chainStatsDoc.Id).Str("method", "insertChainStats").Msg("error while insert")
+	}
+}
+
+func (ns *Indexer) addBpStats(bpStatsDoc *doc.EsBpStats) {
+	err := ns.db.Insert(bpStatsDoc, ns.indexNamePrefix+"bp_stats")
+	if err != nil {
+		ns.log.Error().Err(err).Str("Id", bpStatsDoc.Id).Str("method", "insertBpStats").Msg("error while insert")
+	}
+}
+
+func (ns *Indexer) addEventIndex(blockType BlockType, typeName string, eventIndexDoc *doc.EsEventIndex) {
+	if blockType == BlockType_Bulk {
+		ns.bulk.BChannel.EventIndex[typeName] <- ChanInfo{ChanType_Add, eventIndexDoc}
+	} else {
+		err := ns.db.Insert(eventIndexDoc, ns.indexNamePrefix+typeName)
+		if err != nil {
+			ns.log.Error().Err(err).Str("Id", eventIndexDoc.Id).Str("typeName", typeName).Str("method", "insertEventIndex").Msg("error while insert")
+		}
+	}
+}
+
+func (ns *Indexer) addFeeSponsor(sponsorDoc *doc.EsFeeSponsor) {
+	err := ns.db.Insert(sponsorDoc, ns.indexNamePrefix+"fee_sponsor")
+	if err != nil {
+		ns.log.Error().Err(err).Str("Id", sponsorDoc.Id).Str("method", "insertFeeSponsor").Msg("error while insert")
+	}
+}
+
+func (ns *Indexer) deleteFeeSponsor(id string) {
+	_, err := ns.db.Delete(db.QueryParams{
+		IndexName: ns.indexNamePrefix + "fee_sponsor",
+		StringMatch: &db.StringMatchQuery{
+			Field: "_id",
+			Value: id,
+		},
+	})
+	if err != nil {
+		ns.log.Error().Err(err).Str("Id", id).Str("method", "deleteFeeSponsor").Msg("error while delete")
+	}
+}
+
+func (ns *Indexer) deleteStats(typeName string, id string) {
+	_, err := ns.db.Delete(db.QueryParams{
+		IndexName: ns.indexNamePrefix + typeName,
+		StringMatch: &db.StringMatchQuery{
+			Field: "_id",
+			Value: id,
+		},
+	})
+	if err != nil {
+		ns.log.Error().Err(err).Str("Id", id).Str("method", "deleteStats").Msg("error while delete")
+	}
+}
+
+func (ns *Indexer) restoreNFT(nftDoc doc.DocType) {
+	err := ns.db.Update(nftDoc, ns.indexNamePrefix+"nft", nftDoc.GetID())
+	if err != nil {
+		ns.log.Error().Err(err).Str("Id", nftDoc.GetID()).Str("method", "restoreNFT").Msg("error while update")
+	}
+}
+
+func (ns *Indexer) deleteNFT(id string) {
+	_, err := ns.db.Delete(db.QueryParams{
+		IndexName: ns.indexNamePrefix + "nft",
+		StringMatch: &db.StringMatchQuery{
+			Field: "_id",
+			Value: id,
+		},
+	})
+	if err != nil {
+		ns.log.Error().Err(err).Str("Id", id).Str("method", "deleteNFT").Msg("error while delete")
+	}
+}
+
+func (ns *Indexer) addNFTHistory(blockType BlockType, historyDoc *doc.EsNFTHistory) {
+	if blockType == BlockType_Bulk {
+		ns.bulk.BChannel.NftHistory <- ChanInfo{ChanType_Add, historyDoc}
+	} else {
+		err := ns.db.Insert(historyDoc, ns.indexNamePrefix+"nft_history")
+		if err != nil {
+			ns.log.Error().Err(err).Str("Id", historyDoc.Id).Str("method", "insertNFTHistory").Msg("error while insert")
+		}
+	}
+}
+
 func (ns *Indexer) addNFT(nftDoc *doc.EsNFT) {
 	document, err := ns.getNFT(nftDoc.Id)
 	if err != nil {
@@ -133,13 +242,124 @@ func (ns *Indexer) addWhitelist(whitelistDoc *doc.EsWhitelist) {
 	}
 }
 
+func (ns *Indexer) addBpVotes(bpVotesDoc *doc.EsBpVotes) {
+	err := ns.db.Insert(bpVotesDoc, ns.indexNamePrefix+"bp_votes")
+	if err != nil {
+		ns.log.Error().Err(err).Str("Id", bpVotesDoc.Id).Str("method", "insertBpVotes").Msg("error while insert")
+	}
+}
+
+func (ns *Indexer) addBpChange(bpChangeDoc *doc.EsBpChange) {
+	err := ns.db.Insert(bpChangeDoc, ns.indexNamePrefix+"bp_change")
+	if err != nil {
+		ns.log.Error().Err(err).Str("Id", bpChangeDoc.Id).Str("method", "insertBpChange").Msg("error while insert")
+	}
+}
+
+func (ns *Indexer) addAccountVotes(accountVotesDoc *doc.EsAccountVotes) {
+	err := ns.db.Insert(accountVotesDoc, ns.indexNamePrefix+"account_votes")
+	if err != nil {
+		ns.log.Error().Err(err).Str("Id", accountVotesDoc.Id).Str("method", "insertAccountVotes").Msg("error while insert")
+	}
+}
+
+func (ns *Indexer) addNameState(nameStateDoc *doc.EsNameState) {
+	err := ns.db.Insert(nameStateDoc, ns.indexNamePrefix+"name_state")
+	if err != nil {
+		ns.log.Error().Err(err).Str("Id", nameStateDoc.Id).Str("method", "insertNameState").Msg("error while insert")
+	}
+}
+
+func (ns *Indexer) deleteNameState(name string) {
+	_, err := ns.db.Delete(db.QueryParams{
+		IndexName: ns.indexNamePrefix + "name_state",
+		StringMatch: &db.StringMatchQuery{
+			Field: "name",
+			Value: name,
+		},
+	})
+	if err != nil {
+		ns.log.Error().Err(err).Str("Id", name).Str("method", "deleteNameState").Msg("error while delete")
+	}
+}
+
+func (ns *Indexer) addEnterpriseConf(confDoc *doc.EsEnterpriseConf) {
+	err := ns.db.Insert(confDoc, ns.indexNamePrefix+"enterprise_conf")
+	if err != nil {
+		ns.log.Error().Err(err).Str("Id", confDoc.Id).Str("method", "insertEnterpriseConf").Msg("error while insert")
+	}
+}
+
+func (ns *Indexer) deleteEnterpriseConf(key string) {
+	_, err := ns.db.Delete(db.QueryParams{
+		IndexName: ns.indexNamePrefix + "enterprise_conf",
+		StringMatch: &db.StringMatchQuery{
+			Field: "_id",
+			Value: key,
+		},
+	})
+	if err != nil {
+		ns.log.Error().Err(err).Str("Id", key).Str("method", "deleteEnterpriseConf").Msg("error while delete")
+	}
+}
+
+func (ns *Indexer) addEnterpriseHistory(historyDoc *doc.EsEnterpriseHistory) {
+	err := ns.db.Insert(historyDoc, ns.indexNamePrefix+"enterprise_history")
+	if err != nil {
+		ns.log.Error().Err(err).Str("Id", historyDoc.Id).Str("method", "insertEnterpriseHistory").Msg("error while insert")
+	}
+}
+
+func (ns *Indexer) addRaftMember(raftMemberDoc *doc.EsRaftMember) {
+	err := ns.db.Insert(raftMemberDoc, ns.indexNamePrefix+"raft_member")
+	if err != nil {
+		ns.log.Error().Err(err).Str("Id", raftMemberDoc.Id).Str("method", "insertRaftMember").Msg("error while insert")
+	}
+}
+
+func (ns *Indexer) addContractAbi(abiDoc *doc.EsContractAbi) {
+	err := ns.db.Insert(abiDoc, ns.indexNamePrefix+"contract_abi")
+	if err != nil {
+		ns.log.Error().Err(err).Str("Id", abiDoc.Id).Str("method", "insertContractAbi").Msg("error while insert")
+	}
+}
+
+func (ns *Indexer) addContractVersion(versionDoc *doc.EsContractVersion) {
+	err := ns.db.Insert(versionDoc, ns.indexNamePrefix+"contract_version")
+	if err != nil {
+		ns.log.Error().Err(err).Str("Id", versionDoc.Id).Str("method", "insertContractVersion").Msg("error while insert")
+	}
+}
+
+func (ns *Indexer) updateNFTMetadata(metadataDoc *doc.EsNFTMetadata) {
+	err := ns.db.Update(metadataDoc, ns.indexNamePrefix+"nft", metadataDoc.Id)
+	if err != nil {
+		ns.log.Error().Str("Id", metadataDoc.Id).Err(err).Str("method", "updateNFTMetadata").Msg("error while update")
+	}
+}
+
 func (ns *Indexer) updateToken(tokenDoc *doc.EsTokenUpSupply) {
+	ns.cache.storeTokenSupply(tokenDoc.Id, tokenDoc.Supply)
 	err := ns.db.Update(tokenDoc, ns.indexNamePrefix+"token", tokenDoc.Id)
 	if err != nil {
 		ns.log.Error().Str("Id", tokenDoc.Id).Err(err).Str("method", "updateToken").Msg("error while update")
 	}
 }
 
+func (ns *Indexer) updateBlockReward(blockDoc *doc.EsBlockUpReward) {
+	err := ns.db.Update(blockDoc, ns.indexNamePrefix+"block", blockDoc.Id)
+	if err != nil {
+		ns.log.Error().Str("Id", blockDoc.Id).Err(err).Str("method", "updateBlockReward").Msg("error while update")
+	}
+}
+
+func (ns *Indexer) updateTokenHolders(tokenDoc *doc.EsTokenUpHolders) {
+	err := ns.db.Update(tokenDoc, ns.indexNamePrefix+"token", tokenDoc.Id)
+	if err != nil {
+		ns.log.Error().Str("Id", tokenDoc.Id).Err(err).Str("method", "updateTokenHolders").Msg("error while update")
+	}
+}
+
 func (ns *Indexer) updateTokenVerified(tokenDoc *doc.EsTokenUpVerified) {
 	err := ns.db.Update(tokenDoc, ns.indexNamePrefix+"token", tokenDoc.Id)
 	if err != nil {
@@ -154,6 +374,13 @@ func (ns *Indexer) updateContract(contractDoc *doc.EsContractUp) {
 	}
 }
 
+func (ns *Indexer) updateContractVersion(contractDoc *doc.EsContractUpVersion) {
+	err := ns.db.Update(contractDoc, ns.indexNamePrefix+"contract", contractDoc.Id)
+	if err != nil {
+		ns.log.Error().Str("Id", contractDoc.Id).Err(err).Str("method", "updateContractVersion").Msg("error while update")
+	}
+}
+
 func (ns *Indexer) getContract(id string) (contractDoc *doc.EsContract, err error) {
 	document, err := ns.db.SelectOne(db.QueryParams{
 		IndexName: ns.indexNamePrefix + "contract",
@@ -196,6 +423,88 @@ func (ns *Indexer) getToken(contractAddr string) (tokenDoc *doc.EsToken, err err
 	return document.(*doc.EsToken), nil
 }
 
+func (ns *Indexer) getBpStats(bp string) (bpStatsDoc *doc.EsBpStats, err error) {
+	document, err := ns.db.SelectOne(db.QueryParams{
+		IndexName: ns.indexNamePrefix + "bp_stats",
+		StringMatch: &db.StringMatchQuery{
+			Field: "_id",
+			Value: bp,
+		},
+	}, func() doc.DocType {
+		bpStats := new(doc.EsBpStats)
+		bpStats.BaseEsType = new(doc.BaseEsType)
+		return bpStats
+	})
+	if err != nil {
+		ns.log.Error().Err(err).Str("bp", bp).Str("method", "getBpStats").Msg("error while select")
+		return nil, err
+	} else if document == nil {
+		return nil, nil
+	}
+	return document.(*doc.EsBpStats), nil
+}
+
+// getBlockOf returns the last block before blockNo, produced by bp if bp is not empty
+func (ns *Indexer) getBlockOf(bp string, blockNo uint64) (blockDoc *doc.EsBlock, err error) {
+	if blockNo == 0 {
+		return nil, nil
+	}
+	params := db.QueryParams{
+		IndexName:    ns.indexNamePrefix + "block",
+		IntegerRange: &db.IntegerRangeQuery{Field: "no", Min: 0, Max: blockNo - 1},
+		SortField:    "no",
+		SortAsc:      false,
+	}
+	if bp != "" {
+		params.StringMatch = &db.StringMatchQuery{Field: "block_producer", Value: bp}
+	}
+	document, err := ns.db.SelectOne(params, func() doc.DocType {
+		block := new(doc.EsBlock)
+		block.BaseEsType = new(doc.BaseEsType)
+		return block
+	})
+	if err != nil {
+		ns.log.Error().Err(err).Str("bp", bp).Uint64("blockNo", blockNo).Str("method", "getBlockOf").Msg("error while select")
+		return nil, err
+	} else if document == nil {
+		return nil, nil
+	}
+	return document.(*doc.EsBlock), nil
+}
+
+// getFeeSponsor returns the fees sponsored by id. it is realtime, so a change just inserted is found
+func (ns *Indexer) getFeeSponsor(id string) (sponsorDoc *doc.EsFeeSponsor, err error) {
+	document, err := ns.db.SelectById(ns.indexNamePrefix+"fee_sponsor", id, func() doc.DocType {
+		sponsor := new(doc.EsFeeSponsor)
+		sponsor.BaseEsType = new(doc.BaseEsType)
+		return sponsor
+	})
+	if err != nil {
+		ns.log.Error().Err(err).Str("Id", id).Str("method", "getFeeSponsor").Msg("error while select")
+		return nil, err
+	} else if document == nil {
+		return nil, nil
+	}
+	return document.(*doc.EsFeeSponsor), nil
+}
+
+// getAccountTokens returns the account tokens by id. it is realtime, so a balance change just inserted is found
+func (ns *Indexer) getAccountTokens(id string) (accountTokensDoc *doc.EsAccountTokens, err error) {
+	document, err := ns.db.SelectById(ns.indexNamePrefix+"account_tokens", id, func() doc.DocType {
+		accountTokens := new(doc.EsAccountTokens)
+		accountTokens.BaseEsType = new(doc.BaseEsType)
+		return accountTokens
+	})
+	if err != nil {
+		ns.log.Error().Err(err).Str("Id", id).Str("method", "getAccountTokens").Msg("error while select")
+		return nil, err
+	}
+	if document == nil {
+		return nil, nil
+	}
+	return document.(*doc.EsAccountTokens), nil
+}
+
 func (ns *Indexer) getNFT(id string) (nftDoc *doc.EsNFT, err error) {
 	document, err := ns.db.SelectOne(db.QueryParams{
 		IndexName: ns.indexNamePrefix + "nft",
@@ -240,86 +549,645 @@ func (ns *Indexer) getAccountBalance(id string) (contractDoc *doc.EsAccountBalan
 	return document.(*doc.EsAccountBalance), nil
 }
 
-func (ns *Indexer) cntTokenTransfer(id string) (ttCnt uint64, err error) {
-	cnt, err := ns.db.Count(db.QueryParams{
-		IndexName: ns.indexNamePrefix + "token_transfer",
-		StringMatch: &db.StringMatchQuery{
-			Field: "address",
-			Value: id,
-		},
+func (ns *Indexer) getEnterpriseConf(key string) (confDoc *doc.EsEnterpriseConf, err error) {
+	document, err := ns.db.SelectById(ns.indexNamePrefix+"enterprise_conf", key, func() doc.DocType {
+		conf := new(doc.EsEnterpriseConf)
+		conf.BaseEsType = new(doc.BaseEsType)
+		return conf
 	})
 	if err != nil {
-		ns.log.Error().Err(err).Str("Id", id).Str("method", "countTokenTransfer").Msg("error while count")
-		return 0, err
+		ns.log.Error().Err(err).Str("Id", key).Str("method", "getEnterpriseConf").Msg("error while select")
+		return nil, err
+	} else if document == nil {
+		return nil, nil
 	}
-	return uint64(cnt), nil
+	return document.(*doc.EsEnterpriseConf), nil
 }
 
-func (ns *Indexer) ScrollToken(fn func(*doc.EsToken)) error {
-	scroll := ns.db.Scroll(db.QueryParams{
-		IndexName: ns.indexNamePrefix + "token",
+func (ns *Indexer) getLastRaftMember() (raftMemberDoc *doc.EsRaftMember, err error) {
+	document, err := ns.db.SelectOne(db.QueryParams{
+		IndexName: ns.indexNamePrefix + "raft_member",
 		SortField: "blockno",
-		Size:      10000,
-		From:      0,
-		SortAsc:   true,
+		SortAsc:   false,
 	}, func() doc.DocType {
-		token := new(doc.EsToken)
-		token.BaseEsType = new(doc.BaseEsType)
-		return token
+		raftMember := new(doc.EsRaftMember)
+		raftMember.BaseEsType = new(doc.BaseEsType)
+		return raftMember
 	})
-	for {
-		document, err := scroll.Next()
-		if err == io.EOF {
-			break
-		}
-		if token, ok := document.(*doc.EsToken); ok {
-			fn(token)
-		}
+	if err != nil {
+		ns.log.Error().Err(err).Str("method", "getLastRaftMember").Msg("error while select")
+		return nil, err
+	} else if document == nil {
+		return nil, nil
 	}
-	return nil
+	return document.(*doc.EsRaftMember), nil
 }
 
-func (ns *Indexer) ScrollContract(fn func(*doc.EsContract)) error {
-	scroll := ns.db.Scroll(db.QueryParams{
-		IndexName: ns.indexNamePrefix + "contract",
+func (ns *Indexer) getLastClusterChange() (historyDoc *doc.EsEnterpriseHistory, err error) {
+	document, err := ns.db.SelectOne(db.QueryParams{
+		IndexName: ns.indexNamePrefix + "enterprise_history",
+		StringMatch: &db.StringMatchQuery{
+			Field: "operation",
+			Value: transaction.EnterpriseChangeCluster,
+		},
 		SortField: "blockno",
-		Size:      10000,
-		From:      0,
-		SortAsc:   true,
+		SortAsc:   false,
 	}, func() doc.DocType {
-		contract := new(doc.EsContract)
-		contract.BaseEsType = new(doc.BaseEsType)
-		return contract
+		history := new(doc.EsEnterpriseHistory)
+		history.BaseEsType = new(doc.BaseEsType)
+		return history
 	})
-	for {
-		document, err := scroll.Next()
-		if err == io.EOF {
-			break
-		}
-		if contract, ok := document.(*doc.EsContract); ok {
-			fn(contract)
-		}
+	if err != nil {
+		ns.log.Error().Err(err).Str("method", "getLastClusterChange").Msg("error while select")
+		return nil, err
+	} else if document == nil {
+		return nil, nil
 	}
-	return nil
+	return document.(*doc.EsEnterpriseHistory), nil
 }
 
-func (ns *Indexer) ScrollTokenTransfer(fn func(*doc.EsTokenTransfer)) error {
-	scroll := ns.db.Scroll(db.QueryParams{
-		IndexName: ns.indexNamePrefix + "token_transfer",
+func (ns *Indexer) getLastContractAbi(contract string) (abiDoc *doc.EsContractAbi, err error) {
+	document, err := ns.db.SelectOne(db.QueryParams{
+		IndexName: ns.indexNamePrefix + "contract_abi",
+		StringMatch: &db.StringMatchQuery{
+			Field: "contract",
+			Value: contract,
+		},
 		SortField: "blockno",
-		Size:      10000,
-		From:      0,
-		SortAsc:   true,
+		SortAsc:   false,
 	}, func() doc.DocType {
-		tokenTransfer := new(doc.EsTokenTransfer)
-		tokenTransfer.BaseEsType = new(doc.BaseEsType)
-		return tokenTransfer
+		abi := new(doc.EsContractAbi)
+		abi.BaseEsType = new(doc.BaseEsType)
+		return abi
 	})
-	for {
-		document, err := scroll.Next()
-		if err == io.EOF {
+	if err != nil {
+		ns.log.Error().Err(err).Str("Id", contract).Str("method", "getLastContractAbi").Msg("error while select")
+		return nil, err
+	} else if document == nil {
+		return nil, nil
+	}
+	return document.(*doc.EsContractAbi), nil
+}
+
+// getContractAbiAt returns the abi of contract in effect at the block, which is the last one stored before or at the block
+func (ns *Indexer) getContractAbiAt(contract string, blockNo uint64) (abiDoc *doc.EsContractAbi, err error) {
+	document, err := ns.db.SelectOne(db.QueryParams{
+		IndexName: ns.indexNamePrefix + "contract_abi",
+		StringMatch: &db.StringMatchQuery{
+			Field: "contract",
+			Value: contract,
+		},
+		IntegerRange: &db.IntegerRangeQuery{Field: "blockno", Min: 0, Max: blockNo},
+		SortField:    "blockno",
+		SortAsc:      false,
+	}, func() doc.DocType {
+		abi := new(doc.EsContractAbi)
+		abi.BaseEsType = new(doc.BaseEsType)
+		return abi
+	})
+	if err != nil {
+		ns.log.Error().Err(err).Str("Id", contract).Str("method", "getContractAbiAt").Msg("error while select")
+		return nil, err
+	} else if document == nil {
+		return nil, nil
+	}
+	return document.(*doc.EsContractAbi), nil
+}
+
+func (ns *Indexer) getLastContractVersion(contract string) (versionDoc *doc.EsContractVersion, err error) {
+	document, err := ns.db.SelectOne(db.QueryParams{
+		IndexName: ns.indexNamePrefix + "contract_version",
+		StringMatch: &db.StringMatchQuery{
+			Field: "contract",
+			Value: contract,
+		},
+		SortField: "blockno",
+		SortAsc:   false,
+	}, func() doc.DocType {
+		version := new(doc.EsContractVersion)
+		version.BaseEsType = new(doc.BaseEsType)
+		return version
+	})
+	if err != nil {
+		ns.log.Error().Err(err).Str("Id", contract).Str("method", "getLastContractVersion").Msg("error while select")
+		return nil, err
+	} else if document == nil {
+		return nil, nil
+	}
+	return document.(*doc.EsContractVersion), nil
+}
+
+func (ns *Indexer) cntTokenTransfer(id string) (ttCnt uint64, err error) {
+	cnt, err := ns.db.Count(db.QueryParams{
+		IndexName: ns.indexNamePrefix + "token_transfer",
+		StringMatch: &db.StringMatchQuery{
+			Field: "address",
+			Value: id,
+		},
+	})
+	if err != nil {
+		ns.log.Error().Err(err).Str("Id", id).Str("method", "countTokenTransfer").Msg("error while count")
+		return 0, err
+	}
+	return uint64(cnt), nil
+}
+
+func (ns *Indexer) cntTokenHolders(tokenAddr string) (holderCnt uint64, err error) {
+	cnt, err := ns.db.Count(db.QueryParams{
+		IndexName: ns.indexNamePrefix + "account_tokens",
+		StringMatch: &db.StringMatchQuery{
+			Field: "address",
+			Value: tokenAddr,
+		},
+		GreaterThan: &db.GreaterThanQuery{
+			Field: "balance_float",
+			Value: 0,
+		},
+	})
+	if err != nil {
+		ns.log.Error().Err(err).Str("Id", tokenAddr).Str("method", "countTokenHolders").Msg("error while count")
+		return 0, err
+	}
+	return uint64(cnt), nil
+}
+
+func (ns *Indexer) getTopHolders(tokenAddr string, size int) (holders []*doc.EsAccountTokens, err error) {
+	documents, err := ns.db.Search(db.QueryParams{
+		IndexName: ns.indexNamePrefix + "account_tokens",
+		StringMatch: &db.StringMatchQuery{
+			Field: "address",
+			Value: tokenAddr,
+		},
+		GreaterThan: &db.GreaterThanQuery{
+			Field: "balance_float",
+			Value: 0,
+		},
+		SortField: "balance_padded",
+		SortAsc:   false,
+		Size:      size,
+	}, func() doc.DocType {
+		accountTokens := new(doc.EsAccountTokens)
+		accountTokens.BaseEsType = new(doc.BaseEsType)
+		return accountTokens
+	})
+	if err != nil {
+		ns.log.Error().Err(err).Str("Id", tokenAddr).Str("method", "getTopHolders").Msg("error while search")
+		return nil, err
+	}
+	for _, document := range documents {
+		holders = append(holders, document.(*doc.EsAccountTokens))
+	}
+	return holders, nil
+}
+
+// cntContractsIn counts contracts created in the time range of [from, to)
+func (ns *Indexer) cntContractsIn(from time.Time, to time.Time) (contractCnt uint64, err error) {
+	cnt, err := ns.db.Count(db.QueryParams{
+		IndexName: ns.indexNamePrefix + "contract",
+		IntegerRange: &db.IntegerRangeQuery{
+			Field: "ts",
+			Min:   uint64(from.UnixMilli()),
+			Max:   uint64(to.UnixMilli() - 1),
+		},
+	})
+	if err != nil {
+		ns.log.Error().Err(err).Str("method", "countContractsIn").Msg("error while count")
+		return 0, err
+	}
+	return uint64(cnt), nil
+}
+
+// cntTokensIn counts tokens created in the block range of [from, to]
+func (ns *Indexer) cntTokensIn(from uint64, to uint64) (tokenCnt uint64, err error) {
+	cnt, err := ns.db.Count(db.QueryParams{
+		IndexName: ns.indexNamePrefix + "token",
+		IntegerRange: &db.IntegerRangeQuery{
+			Field: "blockno",
+			Min:   from,
+			Max:   to,
+		},
+	})
+	if err != nil {
+		ns.log.Error().Err(err).Str("method", "countTokensIn").Msg("error while count")
+		return 0, err
+	}
+	return uint64(cnt), nil
+}
+
+// getFirstTx returns the first tx which account sent or received
+func (ns *Indexer) getFirstTx(account string) (txDoc *doc.EsTx, err error) {
+	document, err := ns.db.SelectOne(db.QueryParams{
+		IndexName: ns.indexNamePrefix + "tx",
+		StringMatch: &db.StringMatchQuery{
+			Fields: []string{"from", "to"},
+			Value:  account,
+		},
+		SortField: "blockno",
+		SortAsc:   true,
+	}, func() doc.DocType {
+		tx := new(doc.EsTx)
+		tx.BaseEsType = new(doc.BaseEsType)
+		return tx
+	})
+	if err != nil {
+		ns.log.Error().Err(err).Str("account", account).Str("method", "getFirstTx").Msg("error while select")
+		return nil, err
+	}
+	if document == nil {
+		return nil, nil
+	}
+	return document.(*doc.EsTx), nil
+}
+
+func (ns *Indexer) ScrollChainParam(fn func(*doc.EsChainParam)) error {
+	scroll := ns.db.Scroll(db.QueryParams{
+		IndexName: ns.indexNamePrefix + "chain_param",
+		SortField: "blockno",
+		Size:      10000,
+		From:      0,
+		SortAsc:   true,
+	}, func() doc.DocType {
+		param := new(doc.EsChainParam)
+		param.BaseEsType = new(doc.BaseEsType)
+		return param
+	})
+	for {
+		document, err := scroll.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if param, ok := document.(*doc.EsChainParam); ok {
+			fn(param)
+		}
+	}
+	return nil
+}
+
+func (ns *Indexer) ScrollBpChange(fn func(*doc.EsBpChange)) error {
+	scroll := ns.db.Scroll(db.QueryParams{
+		IndexName: ns.indexNamePrefix + "bp_change",
+		SortField: "blockno",
+		Size:      10000,
+		From:      0,
+		SortAsc:   true,
+	}, func() doc.DocType {
+		bpChange := new(doc.EsBpChange)
+		bpChange.BaseEsType = new(doc.BaseEsType)
+		return bpChange
+	})
+	for {
+		document, err := scroll.Next()
+		if err == io.EOF {
 			break
 		}
+		if err != nil {
+			return err
+		}
+		if bpChange, ok := document.(*doc.EsBpChange); ok {
+			fn(bpChange)
+		}
+	}
+	return nil
+}
+
+func (ns *Indexer) ScrollToken(fn func(*doc.EsToken)) error {
+	scroll := ns.db.Scroll(db.QueryParams{
+		IndexName: ns.indexNamePrefix + "token",
+		SortField: "blockno",
+		Size:      10000,
+		From:      0,
+		SortAsc:   true,
+	}, func() doc.DocType {
+		token := new(doc.EsToken)
+		token.BaseEsType = new(doc.BaseEsType)
+		return token
+	})
+	for {
+		document, err := scroll.Next()
+		if err == io.EOF {
+			break
+		}
+		if token, ok := document.(*doc.EsToken); ok {
+			fn(token)
+		}
+	}
+	return nil
+}
+
+func (ns *Indexer) ScrollNameState(fn func(*doc.EsNameState)) error {
+	scroll := ns.db.Scroll(db.QueryParams{
+		IndexName: ns.indexNamePrefix + "name_state",
+		SortField: "blockno",
+		Size:      10000,
+		From:      0,
+		SortAsc:   true,
+	}, func() doc.DocType {
+		nameState := new(doc.EsNameState)
+		nameState.BaseEsType = new(doc.BaseEsType)
+		return nameState
+	})
+	for {
+		document, err := scroll.Next()
+		if err == io.EOF {
+			break
+		}
+		if nameState, ok := document.(*doc.EsNameState); ok {
+			fn(nameState)
+		}
+	}
+	return nil
+}
+
+func (ns *Indexer) ScrollEnterpriseConf(fn func(*doc.EsEnterpriseConf)) error {
+	scroll := ns.db.Scroll(db.QueryParams{
+		IndexName: ns.indexNamePrefix + "enterprise_conf",
+		SortField: "blockno",
+		Size:      10000,
+		From:      0,
+		SortAsc:   true,
+	}, func() doc.DocType {
+		conf := new(doc.EsEnterpriseConf)
+		conf.BaseEsType = new(doc.BaseEsType)
+		return conf
+	})
+	for {
+		document, err := scroll.Next()
+		if err == io.EOF {
+			break
+		}
+		if conf, ok := document.(*doc.EsEnterpriseConf); ok {
+			fn(conf)
+		}
+	}
+	return nil
+}
+
+// ScrollEnterpriseHistory scrolls changes of the key up to the given block in block order. changes of every key are scrolled if the key is empty, and every block if toBlockNo is zero
+func (ns *Indexer) ScrollEnterpriseHistory(key string, toBlockNo uint64, fn func(*doc.EsEnterpriseHistory)) error {
+	params := db.QueryParams{
+		IndexName: ns.indexNamePrefix + "enterprise_history",
+		SortField: "blockno",
+		Size:      10000,
+		To:        int(toBlockNo),
+		SortAsc:   true,
+	}
+	if key != "" {
+		params.StringMatch = &db.StringMatchQuery{Field: "key", Value: key}
+	}
+	scroll := ns.db.Scroll(params, func() doc.DocType {
+		history := new(doc.EsEnterpriseHistory)
+		history.BaseEsType = new(doc.BaseEsType)
+		return history
+	})
+	for {
+		document, err := scroll.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if history, ok := document.(*doc.EsEnterpriseHistory); ok {
+			fn(history)
+		}
+	}
+	return nil
+}
+
+func (ns *Indexer) ScrollContract(fn func(*doc.EsContract)) error {
+	scroll := ns.db.Scroll(db.QueryParams{
+		IndexName: ns.indexNamePrefix + "contract",
+		SortField: "blockno",
+		Size:      10000,
+		From:      0,
+		SortAsc:   true,
+	}, func() doc.DocType {
+		contract := new(doc.EsContract)
+		contract.BaseEsType = new(doc.BaseEsType)
+		return contract
+	})
+	for {
+		document, err := scroll.Next()
+		if err == io.EOF {
+			break
+		}
+		if contract, ok := document.(*doc.EsContract); ok {
+			fn(contract)
+		}
+	}
+	return nil
+}
+
+func (ns *Indexer) ScrollTokenTransfer(fn func(*doc.EsTokenTransfer)) error {
+	scroll := ns.db.Scroll(db.QueryParams{
+		IndexName: ns.indexNamePrefix + "token_transfer",
+		SortField: "blockno",
+		Size:      10000,
+		From:      0,
+		SortAsc:   true,
+	}, func() doc.DocType {
+		tokenTransfer := new(doc.EsTokenTransfer)
+		tokenTransfer.BaseEsType = new(doc.BaseEsType)
+		return tokenTransfer
+	})
+	for {
+		document, err := scroll.Next()
+		if err == io.EOF {
+			break
+		}
+		if tokenTransfer, ok := document.(*doc.EsTokenTransfer); ok {
+			fn(tokenTransfer)
+		}
+	}
+	return nil
+}
+
+// ScrollBlocksIn scrolls blocks in the time range of [from, to), with the fields of chain stats and bp stats only
+func (ns *Indexer) ScrollBlocksIn(from time.Time, to time.Time, fn func(*doc.EsBlock)) error {
+	return ns.scrollBlocks("ts", int(from.UnixMilli()), int(to.UnixMilli()-1), fn)
+}
+
+// ScrollBlocksInRange scrolls blocks in the block range of [from, to], with the fields of chain stats and bp stats only. every block is scrolled if both are zero
+func (ns *Indexer) ScrollBlocksInRange(from uint64, to uint64, fn func(*doc.EsBlock)) error {
+	return ns.scrollBlocks("no", int(from), int(to), fn)
+}
+
+func (ns *Indexer) scrollBlocks(field string, from int, to int, fn func(*doc.EsBlock)) error {
+	scroll := ns.db.Scroll(db.QueryParams{
+		IndexName:    ns.indexNamePrefix + "block",
+		SelectFields: []string{"no", "ts", "block_producer", "size", "txs", "reward_amount"},
+		SortField:    field,
+		Size:         10000,
+		From:         from,
+		To:           to,
+		SortAsc:      true,
+	}, func() doc.DocType {
+		block := new(doc.EsBlock)
+		block.BaseEsType = new(doc.BaseEsType)
+		return block
+	})
+	for {
+		document, err := scroll.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if block, ok := document.(*doc.EsBlock); ok {
+			fn(block)
+		}
+	}
+	return nil
+}
+
+// ScrollTxIn scrolls txs in the time range of [from, to), with the fields of chain stats only
+func (ns *Indexer) ScrollTxIn(from time.Time, to time.Time, fn func(*doc.EsTx)) error {
+	scroll := ns.db.Scroll(db.QueryParams{
+		IndexName:    ns.indexNamePrefix + "tx",
+		SelectFields: []string{"blockno", "from", "to", "category", "gas_used", "fee_used"},
+		SortField:    "ts",
+		Size:         10000,
+		From:         int(from.UnixMilli()),
+		To:           int(to.UnixMilli() - 1),
+		SortAsc:      true,
+	}, func() doc.DocType {
+		tx := new(doc.EsTx)
+		tx.BaseEsType = new(doc.BaseEsType)
+		return tx
+	})
+	for {
+		document, err := scroll.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tx, ok := document.(*doc.EsTx); ok {
+			fn(tx)
+		}
+	}
+	return nil
+}
+
+// ScrollFeeDelegatedTxBefore scrolls the fee delegated txs which fee the contract paid before the block, from the latest, with the fields of fee sponsor only. scroll stops if fn returns false
+func (ns *Indexer) ScrollFeeDelegatedTxBefore(contract string, blockNo uint64, fn func(*doc.EsTx) bool) error {
+	if blockNo <= 1 {
+		return nil
+	}
+	return ns.scrollFeeDelegatedTx(db.QueryParams{
+		StringMatch: &db.StringMatchQuery{
+			Field: "fee_payer",
+			Value: contract,
+		},
+		SortField: "blockno",
+		To:        int(blockNo - 1),
+		SortAsc:   false,
+	}, fn)
+}
+
+// ScrollFeeDelegatedTxInBlocks scrolls the fee delegated txs in the block range of [from, to], with the fields of fee sponsor only
+func (ns *Indexer) ScrollFeeDelegatedTxInBlocks(from uint64, to uint64, fn func(*doc.EsTx)) error {
+	return ns.scrollFeeDelegatedTx(db.QueryParams{
+		StringMatch: &db.StringMatchQuery{
+			Field: "fee_delegation",
+			Value: "true",
+		},
+		SortField: "blockno",
+		From:      int(from),
+		To:        int(to),
+		SortAsc:   true,
+	}, func(txDoc *doc.EsTx) bool {
+		fn(txDoc)
+		return true
+	})
+}
+
+func (ns *Indexer) scrollFeeDelegatedTx(params db.QueryParams, fn func(*doc.EsTx) bool) error {
+	params.IndexName = ns.indexNamePrefix + "tx"
+	params.SelectFields = []string{"blockno", "ts", "from", "method", "fee_delegation", "fee_payer", "gas_used", "fee_used"}
+	params.Size = 10000
+	scroll := ns.db.Scroll(params, func() doc.DocType {
+		tx := new(doc.EsTx)
+		tx.BaseEsType = new(doc.BaseEsType)
+		return tx
+	})
+	for {
+		document, err := scroll.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tx, ok := document.(*doc.EsTx); ok && tx.FeeDelegation == true {
+			if fn(tx) == false {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// ScrollTokenTransferOf scrolls transfers of a token in the time range of [from, to)
+func (ns *Indexer) ScrollTokenTransferOf(tokenAddr string, from time.Time, to time.Time, fn func(*doc.EsTokenTransfer)) error {
+	scroll := ns.db.Scroll(db.QueryParams{
+		IndexName: ns.indexNamePrefix + "token_transfer",
+		StringMatch: &db.StringMatchQuery{
+			Field: "address",
+			Value: tokenAddr,
+		},
+		SortField: "ts",
+		Size:      10000,
+		From:      int(from.UnixMilli()),
+		To:        int(to.UnixMilli() - 1),
+		SortAsc:   true,
+	}, func() doc.DocType {
+		tokenTransfer := new(doc.EsTokenTransfer)
+		tokenTransfer.BaseEsType = new(doc.BaseEsType)
+		return tokenTransfer
+	})
+	for {
+		document, err := scroll.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tokenTransfer, ok := document.(*doc.EsTokenTransfer); ok {
+			fn(tokenTransfer)
+		}
+	}
+	return nil
+}
+
+// ScrollTokenTransferInBlocks scrolls transfers in the block range of [from, to]
+func (ns *Indexer) ScrollTokenTransferInBlocks(from uint64, to uint64, fn func(*doc.EsTokenTransfer)) error {
+	scroll := ns.db.Scroll(db.QueryParams{
+		IndexName: ns.indexNamePrefix + "token_transfer",
+		SortField: "blockno",
+		Size:      10000,
+		From:      int(from),
+		To:        int(to),
+		SortAsc:   true,
+	}, func() doc.DocType {
+		tokenTransfer := new(doc.EsTokenTransfer)
+		tokenTransfer.BaseEsType = new(doc.BaseEsType)
+		return tokenTransfer
+	})
+	for {
+		document, err := scroll.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
 		if tokenTransfer, ok := document.(*doc.EsTokenTransfer); ok {
 			fn(tokenTransfer)
 		}
@@ -398,3 +1266,150 @@ func (ns *Indexer) ScrollWhitelist(fn func(*doc.EsWhitelist)) error {
 	}
 	return nil
 }
+
+// ScrollNFTMetadataDue scrolls nfts which metadata was fetched before the time or never fetched, oldest first. stops when fn returns false
+func (ns *Indexer) ScrollNFTMetadataDue(before time.Time, fn func(*doc.EsNFT) bool) error {
+	scroll := ns.db.Scroll(db.QueryParams{
+		IndexName:   ns.indexNamePrefix + "nft",
+		SortField:   "metadata_ts",
+		SortMissing: true,
+		Size:        100,
+		To:          int(before.UnixMilli()),
+		SortAsc:     true,
+	}, func() doc.DocType {
+		nft := new(doc.EsNFT)
+		nft.BaseEsType = new(doc.BaseEsType)
+		return nft
+	})
+	for {
+		document, err := scroll.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if nft, ok := document.(*doc.EsNFT); ok && !fn(nft) {
+			break
+		}
+	}
+	return nil
+}
+
+// ScrollContractVersionInRange scrolls contract versions in range of block numbers
+func (ns *Indexer) ScrollContractVersionInRange(fromBlockHeight uint64, toBlockHeight uint64, fn func(*doc.EsContractVersion)) error {
+	scroll := ns.db.Scroll(db.QueryParams{
+		IndexName: ns.indexNamePrefix + "contract_version",
+		SortField: "blockno",
+		Size:      1000,
+		From:      int(fromBlockHeight),
+		To:        int(toBlockHeight),
+		SortAsc:   true,
+	}, func() doc.DocType {
+		version := new(doc.EsContractVersion)
+		version.BaseEsType = new(doc.BaseEsType)
+		return version
+	})
+	for {
+		document, err := scroll.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if version, ok := document.(*doc.EsContractVersion); ok {
+			fn(version)
+		}
+	}
+	return nil
+}
+
+// ScrollNFTInRange scrolls nfts which were changed in range of block numbers
+func (ns *Indexer) ScrollNFTInRange(fromBlockHeight uint64, toBlockHeight uint64, fn func(*doc.EsNFT)) error {
+	scroll := ns.db.Scroll(db.QueryParams{
+		IndexName: ns.indexNamePrefix + "nft",
+		SortField: "blockno",
+		Size:      1000,
+		From:      int(fromBlockHeight),
+		To:        int(toBlockHeight),
+		SortAsc:   true,
+	}, func() doc.DocType {
+		nft := new(doc.EsNFT)
+		nft.BaseEsType = new(doc.BaseEsType)
+		return nft
+	})
+	for {
+		document, err := scroll.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if nft, ok := document.(*doc.EsNFT); ok {
+			fn(nft)
+		}
+	}
+	return nil
+}
+
+// ScrollNFTHistory scrolls nft histories in range of block numbers
+func (ns *Indexer) ScrollNFTHistory(fromBlockHeight uint64, toBlockHeight uint64, fn func(*doc.EsNFTHistory)) error {
+	scroll := ns.db.Scroll(db.QueryParams{
+		IndexName: ns.indexNamePrefix + "nft_history",
+		SortField: "blockno",
+		Size:      1000,
+		From:      int(fromBlockHeight),
+		To:        int(toBlockHeight),
+		SortAsc:   true,
+	}, func() doc.DocType {
+		history := new(doc.EsNFTHistory)
+		history.BaseEsType = new(doc.BaseEsType)
+		return history
+	})
+	for {
+		document, err := scroll.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if history, ok := document.(*doc.EsNFTHistory); ok {
+			fn(history)
+		}
+	}
+	return nil
+}
+
+// ScrollNFTHistoryOf scrolls histories of a nft in order
+func (ns *Indexer) ScrollNFTHistoryOf(nftId string, fn func(*doc.EsNFTHistory)) error {
+	scroll := ns.db.Scroll(db.QueryParams{
+		IndexName: ns.indexNamePrefix + "nft_history",
+		StringMatch: &db.StringMatchQuery{
+			Field: "nft_id",
+			Value: nftId,
+		},
+		SortField: "order",
+		Size:      1000,
+		SortAsc:   true,
+	}, func() doc.DocType {
+		history := new(doc.EsNFTHistory)
+		history.BaseEsType = new(doc.BaseEsType)
+		return history
+	})
+	for {
+		document, err := scroll.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if history, ok := document.(*doc.EsNFTHistory); ok {
+			fn(history)
+		}
+	}
+	return nil
+}
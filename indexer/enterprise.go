@@ -0,0 +1,104 @@
+package indexer
+
+import (
+	"github.com/aergoio/aergo-indexer-2.0/indexer/client"
+	doc "github.com/aergoio/aergo-indexer-2.0/indexer/documents"
+	"github.com/aergoio/aergo-indexer-2.0/indexer/transaction"
+	"github.com/aergoio/aergo-indexer-2.0/types"
+)
+
+// MinerEnterprise records a change of enterprise config or cluster and applies it to the config state. in bulk mode, config states of the changed keys are rebuilt after bulk sync
+func (ns *Indexer) MinerEnterprise(blockType BlockType, txDoc *doc.EsTx, tx *types.Tx, MinerGRPC *client.AergoClientController) {
+	payload, err := transaction.UnmarshalEnterprisePayload(tx.GetBody().GetPayload())
+	if err != nil {
+		ns.log.Warn().Err(err).Str("tx", txDoc.Id).Msg("Failed to decode enterprise payload")
+		return
+	}
+
+	var progress *types.ConfChangeProgress
+	if payload.Name == transaction.EnterpriseChangeCluster {
+		progress, err = MinerGRPC.GetConfChangeProgress(tx.GetHash())
+		if err != nil {
+			ns.log.Warn().Err(err).Str("tx", txDoc.Id).Msg("Failed to get conf change progress")
+		}
+	}
+	historyDoc := doc.ConvEnterpriseHistory(txDoc, payload, progress)
+	ns.addEnterpriseHistory(historyDoc)
+
+	if payload.Key == "" || txDoc.Status != "SUCCESS" {
+		return
+	}
+	if blockType == BlockType_Bulk {
+		ns.cache.markEnterpriseConf(payload.Key)
+		return
+	}
+	ns.applyEnterpriseConf(historyDoc)
+}
+
+// applyEnterpriseConf applies a change to the stored config state. the state is rebuilt from history if a later change is already applied
+func (ns *Indexer) applyEnterpriseConf(historyDoc *doc.EsEnterpriseHistory) {
+	confDoc, err := ns.getEnterpriseConf(historyDoc.Key)
+	if err != nil {
+		return
+	}
+	if confDoc == nil {
+		confDoc = doc.NewEnterpriseConf(historyDoc.Key)
+	} else if confDoc.BlockNo > historyDoc.BlockNo {
+		if err := ns.db.Refresh(ns.indexNamePrefix + "enterprise_history"); err != nil {
+			ns.log.Warn().Err(err).Msg("Failed to refresh indices")
+		}
+		ns.rebuildEnterpriseConf(historyDoc.Key, 0)
+		return
+	}
+	if confDoc.ApplyHistory(historyDoc) {
+		ns.addEnterpriseConf(confDoc)
+	}
+}
+
+// rebuildEnterpriseConf replays changes of the key before the given block (every block if zero). the config state is deleted if no change is left
+func (ns *Indexer) rebuildEnterpriseConf(key string, beforeBlockNo uint64) {
+	var toBlockNo uint64
+	if beforeBlockNo > 1 {
+		toBlockNo = beforeBlockNo - 1
+	}
+
+	confDoc := doc.NewEnterpriseConf(key)
+	var applied bool
+	if err := ns.ScrollEnterpriseHistory(key, toBlockNo, func(historyDoc *doc.EsEnterpriseHistory) {
+		if confDoc.ApplyHistory(historyDoc) {
+			applied = true
+		}
+	}); err != nil {
+		ns.log.Error().Err(err).Str("key", key).Str("func", "rebuildEnterpriseConf").Msg("error while scroll enterprise history")
+		return
+	}
+	if applied == false {
+		ns.deleteEnterpriseConf(key)
+		return
+	}
+	ns.addEnterpriseConf(confDoc)
+}
+
+// rebuildMarkedEnterpriseConfs rebuilds the config states of keys changed in bulk from history, after bulk sync
+func (ns *Indexer) rebuildMarkedEnterpriseConfs() {
+	for _, key := range ns.cache.takeMarkedEnterpriseConfs() {
+		ns.rebuildEnterpriseConf(key, 0)
+	}
+}
+
+// rollbackEnterpriseConf rebuilds config states changed from the given block with the remaining history
+func (ns *Indexer) rollbackEnterpriseConf(fromBlockNo uint64) {
+	if err := ns.db.Refresh(ns.indexNamePrefix+"enterprise_history", ns.indexNamePrefix+"enterprise_conf"); err != nil {
+		ns.log.Warn().Err(err).Msg("Failed to refresh indices")
+	}
+
+	var keys []string
+	ns.ScrollEnterpriseConf(func(confDoc *doc.EsEnterpriseConf) {
+		if confDoc.BlockNo >= fromBlockNo {
+			keys = append(keys, confDoc.Key)
+		}
+	})
+	for _, key := range keys {
+		ns.rebuildEnterpriseConf(key, fromBlockNo)
+	}
+}
@@ -0,0 +1,41 @@
+package indexer
+
+import (
+	"github.com/aergoio/aergo-indexer-2.0/indexer/db"
+	doc "github.com/aergoio/aergo-indexer-2.0/indexer/documents"
+)
+
+// initEventIndices creates custom event indices declared by config
+func (ns *Indexer) initEventIndices() error {
+	for _, spec := range ns.eventIndices {
+		if err := doc.RegisterEventIndex(spec); err != nil {
+			return err
+		}
+		if err := ns.CreateIndexIfNotExists(spec.Index); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MinerEventIndex adds the event to custom event indices which match it
+func (ns *Indexer) MinerEventIndex(blockType BlockType, blockDoc *doc.EsBlock, eventDoc *doc.EsEvent) {
+	for _, spec := range ns.eventIndices {
+		if !spec.Matches(eventDoc) {
+			continue
+		}
+		eventIndexDoc, err := doc.ConvEventIndex(spec, eventDoc, blockDoc.Timestamp)
+		if err != nil {
+			ns.log.Warn().Err(err).Str("tx", eventDoc.TxId).Uint64("event_idx", eventDoc.EventIdx).Msg("Failed to convert event index")
+			continue
+		}
+		ns.addEventIndex(blockType, spec.Index, eventIndexDoc)
+	}
+}
+
+// rollbackEventIndices deletes events in the block range from custom event indices
+func (ns *Indexer) rollbackEventIndices(fromBlockHeight uint64, toBlockHeight uint64) {
+	for _, spec := range ns.eventIndices {
+		ns.deleteTypeByQuery(spec.Index, db.IntegerRangeQuery{Field: "blockno", Min: fromBlockHeight, Max: toBlockHeight})
+	}
+}
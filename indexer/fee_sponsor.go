@@ -0,0 +1,94 @@
+package indexer
+
+import (
+	doc "github.com/aergoio/aergo-indexer-2.0/indexer/documents"
+)
+
+// MinerFeeSponsor adds the fee of a fee delegated tx to the fees sponsored by the contract ( sync only )
+func (ns *Indexer) MinerFeeSponsor(txDoc *doc.EsTx) {
+	changed := make(map[string]*doc.EsFeeSponsor)
+	ns.applyFeeSponsor(changed, txDoc, false)
+	ns.saveFeeSponsors(changed)
+}
+
+// applyFeeSponsor applies a fee delegated tx to the fees sponsored, which are loaded from the docs when first changed. the fee is removed if revert is true
+func (ns *Indexer) applyFeeSponsor(changed map[string]*doc.EsFeeSponsor, txDoc *doc.EsTx, revert bool) {
+	for _, sponsor := range doc.FeeSponsorsOf(txDoc) {
+		if loaded, exist := changed[sponsor.Id]; exist == true {
+			sponsor = loaded
+		} else {
+			stored, err := ns.getFeeSponsor(sponsor.Id)
+			if err != nil {
+				continue
+			}
+			if stored != nil {
+				sponsor = stored
+			}
+			changed[sponsor.Id] = sponsor
+		}
+		if revert == true {
+			sponsor.Revert(txDoc)
+		} else {
+			sponsor.Apply(txDoc)
+		}
+	}
+}
+
+// saveFeeSponsors stores the fees sponsored, or deletes them if no tx is left
+func (ns *Indexer) saveFeeSponsors(changed map[string]*doc.EsFeeSponsor) {
+	for id, sponsor := range changed {
+		if sponsor.TxCount == 0 {
+			ns.deleteFeeSponsor(id)
+		} else {
+			ns.addFeeSponsor(sponsor)
+		}
+	}
+}
+
+// applyFeeSponsorsInRange applies the fee delegated txs in the block range to the fees sponsored. they are reverted if revert is true
+// txs indexed in bulk are applied after bulk sync, and txs going to be indexed again are reverted before
+func (ns *Indexer) applyFeeSponsorsInRange(fromBlockHeight uint64, toBlockHeight uint64, revert bool) map[string]*doc.EsFeeSponsor {
+	changed := make(map[string]*doc.EsFeeSponsor)
+	if err := ns.ScrollFeeDelegatedTxInBlocks(fromBlockHeight, toBlockHeight, func(txDoc *doc.EsTx) {
+		ns.applyFeeSponsor(changed, txDoc, revert)
+	}); err != nil {
+		ns.log.Error().Err(err).Str("func", "applyFeeSponsorsInRange").Msg("error while scroll tx")
+	}
+	ns.saveFeeSponsors(changed)
+	return changed
+}
+
+// rollbackFeeSponsors removes the fees of fee delegated txs in the block range, which are going to be deleted
+func (ns *Indexer) rollbackFeeSponsors(fromBlockHeight uint64, toBlockHeight uint64) {
+	changed := make(map[string]*doc.EsFeeSponsor)
+	if err := ns.ScrollFeeDelegatedTxInBlocks(fromBlockHeight, toBlockHeight, func(txDoc *doc.EsTx) {
+		ns.applyFeeSponsor(changed, txDoc, true)
+	}); err != nil {
+		ns.log.Error().Err(err).Str("func", "rollbackFeeSponsors").Msg("error while scroll tx")
+	}
+
+	// the last sponsored block is restored from the latest tx left
+	pending := make(map[string]map[string]*doc.EsFeeSponsor)
+	for id, sponsor := range changed {
+		if sponsor.TxCount > 0 && sponsor.LastBlock >= fromBlockHeight {
+			if pending[sponsor.Contract] == nil {
+				pending[sponsor.Contract] = make(map[string]*doc.EsFeeSponsor)
+			}
+			pending[sponsor.Contract][id] = sponsor
+		}
+	}
+	for contract, sponsors := range pending {
+		if err := ns.ScrollFeeDelegatedTxBefore(contract, fromBlockHeight, func(txDoc *doc.EsTx) bool {
+			for _, sponsor := range doc.FeeSponsorsOf(txDoc) {
+				if restored, exist := sponsors[sponsor.Id]; exist == true {
+					restored.LastBlock, restored.LastTs = txDoc.BlockNo, txDoc.Timestamp
+					delete(sponsors, sponsor.Id)
+				}
+			}
+			return len(sponsors) > 0
+		}); err != nil {
+			ns.log.Error().Err(err).Str("contract", contract).Str("func", "rollbackFeeSponsors").Msg("error while scroll tx")
+		}
+	}
+	ns.saveFeeSponsors(changed)
+}
@@ -0,0 +1,131 @@
+package indexer
+
+import (
+	doc "github.com/aergoio/aergo-indexer-2.0/indexer/documents"
+)
+
+// MinerTokenHolders applies the balance change of account tokens to the holder values of token ( sync only )
+func (ns *Indexer) MinerTokenHolders(accountTokensDoc *doc.EsAccountTokens) {
+	holders := ns.loadTokenHolders(accountTokensDoc.TokenAddress)
+	if holders == nil {
+		return
+	}
+	prev, err := ns.getAccountTokens(accountTokensDoc.Id)
+
+	holders.Lock()
+	defer holders.Unlock()
+	if err != nil {
+		holders.Stale = true
+		return
+	}
+	holders.Apply(prev, accountTokensDoc)
+	ns.updateTokenHolders(doc.ConvTokenUpHolders(accountTokensDoc.TokenAddress, holders.TokenHolders, holders.supply))
+}
+
+// loadTokenHolders returns the holder tracker of token, which starts from the token doc. returns nil if the token is not indexed
+func (ns *Indexer) loadTokenHolders(tokenAddr string) *tokenHolders {
+	if holders, exist := ns.cache.getTokenHolders(tokenAddr); exist == true {
+		return holders
+	}
+	tokenDoc, err := ns.getToken(tokenAddr)
+	if err != nil || tokenDoc == nil {
+		return nil
+	}
+	holders := &tokenHolders{
+		TokenHolders: doc.NewTokenHolders(ns.topHolders, tokenDoc.HolderCount, tokenDoc.TopHolders),
+		supply:       tokenDoc.Supply,
+	}
+	// holders are not counted yet
+	if tokenDoc.HolderCount == 0 {
+		holders.Stale = true
+	}
+	ns.cache.storeTokenHolders(tokenAddr, holders)
+	return holders
+}
+
+// recountTokenHolders replaces the holder values of token with a full count of account tokens
+func (ns *Indexer) recountTokenHolders(tokenAddr string, supply string) {
+	count, err := ns.cntTokenHolders(tokenAddr)
+	if err != nil {
+		return
+	}
+	top, err := ns.getTopHolders(tokenAddr, ns.topHolders)
+	if err != nil {
+		return
+	}
+
+	holders := ns.cache.loadOrStoreTokenHolders(tokenAddr, &tokenHolders{TokenHolders: doc.NewTokenHolders(ns.topHolders, 0, nil)})
+	holders.Lock()
+	defer holders.Unlock()
+	if supply != "" {
+		holders.supply = supply
+	}
+	holders.Reset(count, top)
+	ns.updateTokenHolders(doc.ConvTokenUpHolders(tokenAddr, holders.TokenHolders, holders.supply))
+}
+
+// RecountTokenHolders recounts the holders of all tokens
+func (ns *Indexer) RecountTokenHolders() {
+	tokens := 0
+	if err := ns.ScrollToken(func(tokenDoc *doc.EsToken) {
+		ns.recountTokenHolders(tokenDoc.Id, tokenDoc.Supply)
+		tokens++
+	}); err != nil {
+		ns.log.Error().Err(err).Str("func", "RecountTokenHolders").Msg("error while scroll token")
+	}
+	ns.log.Info().Int("tokens", tokens).Msg("recount token holders")
+}
+
+// recountMarkedTokenHolders recounts the holders of tokens which balances are indexed in bulk
+func (ns *Indexer) recountMarkedTokenHolders() {
+	tokens := ns.cache.takeMarkedHolders()
+	for _, tokenAddr := range tokens {
+		tokenDoc, err := ns.getToken(tokenAddr)
+		if err != nil || tokenDoc == nil {
+			continue
+		}
+		ns.recountTokenHolders(tokenAddr, tokenDoc.Supply)
+	}
+	ns.log.Info().Int("tokens", len(tokens)).Msg("recount token holders indexed in bulk")
+}
+
+// recountStaleTokenHolders recounts the holders of tokens which top holders may miss an account
+func (ns *Indexer) recountStaleTokenHolders() {
+	var stale []string
+	ns.cache.rangeTokenHolders(func(tokenAddr string, holders *tokenHolders) {
+		holders.Lock()
+		if holders.Stale == true {
+			stale = append(stale, tokenAddr)
+		}
+		holders.Unlock()
+	})
+	for _, tokenAddr := range stale {
+		ns.recountTokenHolders(tokenAddr, "")
+	}
+}
+
+// recountInBackground runs a recount of token holders off the sync miner. it is skipped if another recount is running
+func (ns *Indexer) recountInBackground(recount func()) {
+	if ns.holdersRecount.TryLock() != true {
+		ns.log.Debug().Msg("recount token holders is running, skip")
+		return
+	}
+	go func() {
+		defer ns.holdersRecount.Unlock()
+		recount()
+	}()
+}
+
+// rollbackTokenHolders drops the tracked holders, which may include rolled back balance changes, and recounts them
+func (ns *Indexer) rollbackTokenHolders() {
+	var tokens []string
+	ns.cache.rangeTokenHolders(func(tokenAddr string, holders *tokenHolders) {
+		tokens = append(tokens, tokenAddr)
+	})
+	ns.cache.resetTokenHolders()
+	ns.recountInBackground(func() {
+		for _, tokenAddr := range tokens {
+			ns.recountTokenHolders(tokenAddr, "")
+		}
+	})
+}
@@ -4,11 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/aergoio/aergo-indexer-2.0/indexer/client"
 	"github.com/aergoio/aergo-indexer-2.0/indexer/db"
 	doc "github.com/aergoio/aergo-indexer-2.0/indexer/documents"
+	"github.com/aergoio/aergo-indexer-2.0/indexer/metadata"
+	"github.com/aergoio/aergo-indexer-2.0/indexer/transaction"
+	"github.com/aergoio/aergo-indexer-2.0/lua_compiler"
 	"github.com/aergoio/aergo-indexer-2.0/types"
 	"github.com/aergoio/aergo-lib/log"
 )
@@ -22,11 +27,11 @@ type Indexer struct {
 	prefix                  string
 	runMode                 string
 	fix                     bool
-	networkTypeForCccv      string
 	indexNamePrefix         string
 	aliasNamePrefix         string
-	lastHeight              uint64
-	cccvNftAddress          []byte
+	lastHeight              uint64 // written by the stream and read by the miners, accessed atomically
+	consensus               string
+	contractProfiles        map[string]*client.ContractProfile
 	bulkSize                int32
 	batchTime               time.Duration
 	minerNum                int
@@ -36,6 +41,18 @@ type Indexer struct {
 	balanceWhitelist        []string
 	tokenVerifyWhitelist    []string
 	contractVerifyWhitelist []string
+	bpVotesInterval         uint64
+	bpVotesCount            uint32
+	holdersRecountInterval  uint64
+	topHolders              int
+	holdersRecount          sync.Mutex
+	blockInterval           time.Duration
+	observeReward           bool
+	chainConfig             *transaction.ChainConfig
+	compilers               *lua_compiler.Compilers
+	nftMetadata             *metadata.Resolver
+	nftMetadataQueue        chan nftMetadataJob
+	eventIndices            []*doc.EventIndexSpec
 
 	db         db.DbController
 	grpcClient *client.AergoClientController
@@ -56,6 +73,19 @@ func NewIndexer(options ...IndexerOptionFunc) (*Indexer, error) {
 		batchTime: 60 * time.Second,
 		minerNum:  32,
 		grpcNum:   16,
+
+		bpVotesInterval: 3600,
+		bpVotesCount:    100,
+
+		holdersRecountInterval: 86400,
+		topHolders:             doc.DefaultTopHolders,
+
+		blockInterval: time.Second,
+
+		chainConfig: transaction.NewChainConfig(),
+		compilers:   lua_compiler.NewCompilers(),
+
+		contractProfiles: make(map[string]*client.ContractProfile),
 	}
 
 	// overwrite options on it
@@ -85,6 +115,15 @@ func NewIndexer(options ...IndexerOptionFunc) (*Indexer, error) {
 	return svc, nil
 }
 
+// getLastHeight returns the height of the chain tip known by the stream
+func (ns *Indexer) getLastHeight() uint64 {
+	return atomic.LoadUint64(&ns.lastHeight)
+}
+
+func (ns *Indexer) setLastHeight(height uint64) {
+	atomic.StoreUint64(&ns.lastHeight, height)
+}
+
 // Start setups the indexer
 func (ns *Indexer) Start(startFrom uint64, stopAt uint64) (exitOnComplete bool) {
 	ns.log.Info().Msg("Start Indexer")
@@ -94,8 +133,15 @@ func (ns *Indexer) Start(startFrom uint64, stopAt uint64) (exitOnComplete bool)
 		return true
 	}
 
-	ns.initCccvNft()
-	ns.lastHeight = uint64(ns.GetBestBlock()) - 1
+	ns.initContractProfiles()
+	ns.startNFTMetadata()
+	ns.initNameState()
+	ns.setLastHeight(uint64(ns.GetBestBlock()) - 1)
+	ns.initChainParams(ns.getLastHeight())
+	if _, ok := ns.chainConfig.HardforkAt(ns.getLastHeight()); ok != true {
+		ns.log.Warn().Msg("Heights of hardforks are not set. voting rewards of blocks are not computed")
+	}
+	ns.initBpSchedules()
 
 	switch ns.runMode {
 	case "all":
@@ -184,10 +230,31 @@ func (ns *Indexer) InitIndex() error {
 	ns.CreateIndexIfNotExists("token")
 	ns.CreateIndexIfNotExists("contract")
 	ns.CreateIndexIfNotExists("token_transfer")
+	ns.CreateIndexIfNotExists("token_stats")
+	ns.CreateIndexIfNotExists("chain_stats")
+	ns.CreateIndexIfNotExists("chain_param")
+	ns.CreateIndexIfNotExists("bp_stats")
+	ns.CreateIndexIfNotExists("fee_sponsor")
 	ns.CreateIndexIfNotExists("account_tokens")
 	ns.CreateIndexIfNotExists("nft")
+	ns.CreateIndexIfNotExists("nft_history")
 	ns.CreateIndexIfNotExists("account_balance")
 	ns.CreateIndexIfNotExists("whitelist")
+	ns.CreateIndexIfNotExists("bp_votes")
+	ns.CreateIndexIfNotExists("bp_change")
+	ns.CreateIndexIfNotExists("account_votes")
+	ns.CreateIndexIfNotExists("name_state")
+	ns.CreateIndexIfNotExists("enterprise_conf")
+	ns.CreateIndexIfNotExists("enterprise_history")
+	ns.CreateIndexIfNotExists("raft_member")
+	ns.CreateIndexIfNotExists("contract_abi")
+	ns.CreateIndexIfNotExists("contract_version")
+
+	// create custom event indexes
+	if err := ns.initEventIndices(); err != nil {
+		ns.log.Error().Err(err).Msg("Failed to create event indices")
+		return err
+	}
 
 	return nil
 }
@@ -208,6 +275,7 @@ func (ns *Indexer) ValidChainInfo() error {
 	if err != nil {
 		return err
 	}
+	ns.consensus = chainInfoFromNode.Id.Consensus
 
 	document, err := ns.db.SelectOne(db.QueryParams{ // get chain info from db
 		IndexName: ns.indexNamePrefix + "chain_info",
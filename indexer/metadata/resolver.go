@@ -0,0 +1,242 @@
+package metadata
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultIpfsGateway = "https://ipfs.io/ipfs/"
+	DefaultMaxSize     = 1024 * 1024
+	DefaultTimeout     = 10 * time.Second
+	DefaultRefresh     = 24 * time.Hour
+
+	maxCacheEntries = 10000
+)
+
+// Config is the configuration of metadata resolver
+type Config struct {
+	IpfsGateway string        // gateway url which ipfs path is appended to
+	MaxSize     int64         // max size of metadata in bytes
+	Timeout     time.Duration // timeout of a fetch
+	Refresh     time.Duration // interval to re-fetch metadata
+}
+
+// Attribute is an attribute of nft metadata
+type Attribute struct {
+	TraitType string `json:"trait_type"`
+	Value     string `json:"value"`
+}
+
+// Metadata is the off-chain metadata of a token, fetched from token uri
+type Metadata struct {
+	Name        string
+	Description string
+	Image       string
+	Attributes  []Attribute
+	Hash        string // sha256 hash of content
+	FetchedAt   time.Time
+}
+
+// Resolver fetches and parses metadata of token uri, cached by uri and content hash
+type Resolver struct {
+	config Config
+	client *http.Client
+
+	mu     sync.Mutex
+	byUri  map[string]*Metadata
+	byHash map[string]*Metadata
+}
+
+// NewResolver returns the resolver. zero values of config are set to defaults
+func NewResolver(config Config) *Resolver {
+	if config.IpfsGateway == "" {
+		config.IpfsGateway = DefaultIpfsGateway
+	}
+	if !strings.HasSuffix(config.IpfsGateway, "/") {
+		config.IpfsGateway += "/"
+	}
+	if config.MaxSize <= 0 {
+		config.MaxSize = DefaultMaxSize
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+	if config.Refresh <= 0 {
+		config.Refresh = DefaultRefresh
+	}
+	return &Resolver{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+		byUri:  make(map[string]*Metadata),
+		byHash: make(map[string]*Metadata),
+	}
+}
+
+// Refresh returns the interval to re-fetch metadata
+func (r *Resolver) Refresh() time.Duration {
+	return r.config.Refresh
+}
+
+// Due checks if metadata fetched at the time should be re-fetched
+func (r *Resolver) Due(fetchedAt time.Time) bool {
+	return time.Since(fetchedAt) >= r.config.Refresh
+}
+
+// Resolve returns the metadata of uri. it is fetched again only if the cached one is due, and parsed again only if the content is changed
+func (r *Resolver) Resolve(uri string) (*Metadata, error) {
+	uri = strings.TrimSpace(uri)
+	if uri == "" {
+		return nil, errors.New("empty uri")
+	}
+	r.mu.Lock()
+	cached, exist := r.byUri[uri]
+	r.mu.Unlock()
+	if exist && !r.Due(cached.FetchedAt) {
+		metadata := *cached
+		return &metadata, nil
+	}
+
+	content, err := r.Fetch(uri)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(content)
+	contentHash := hex.EncodeToString(hash[:])
+
+	r.mu.Lock()
+	parsed, exist := r.byHash[contentHash]
+	r.mu.Unlock()
+	if !exist {
+		if parsed, err = Parse(content); err != nil {
+			return nil, err
+		}
+		parsed.Hash = contentHash
+	}
+	metadata := *parsed
+	metadata.FetchedAt = time.Now()
+
+	r.mu.Lock()
+	if len(r.byUri) >= maxCacheEntries {
+		r.byUri = make(map[string]*Metadata)
+		r.byHash = make(map[string]*Metadata)
+	}
+	r.byUri[uri] = &metadata
+	r.byHash[contentHash] = parsed
+	r.mu.Unlock()
+	result := metadata
+	return &result, nil
+}
+
+// Fetch returns the content of uri. supports http(s), ipfs and data uri
+func (r *Resolver) Fetch(uri string) ([]byte, error) {
+	switch {
+	case strings.HasPrefix(uri, "data:"):
+		content, err := decodeDataUri(uri)
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(content)) > r.config.MaxSize {
+			return nil, fmt.Errorf("metadata exceeds max size %d", r.config.MaxSize)
+		}
+		return content, nil
+	case strings.HasPrefix(uri, "ipfs://"):
+		path := strings.TrimPrefix(strings.TrimPrefix(uri, "ipfs://"), "ipfs/")
+		return r.get(r.config.IpfsGateway + path)
+	case strings.HasPrefix(uri, "http://"), strings.HasPrefix(uri, "https://"):
+		return r.get(uri)
+	default:
+		return nil, fmt.Errorf("unsupported uri: %s", uri)
+	}
+}
+
+func (r *Resolver) get(uri string) ([]byte, error) {
+	resp, err := r.client.Get(uri)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, uri)
+	}
+	if resp.ContentLength > r.config.MaxSize {
+		return nil, fmt.Errorf("metadata exceeds max size %d", r.config.MaxSize)
+	}
+	content, err := io.ReadAll(io.LimitReader(resp.Body, r.config.MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) > r.config.MaxSize {
+		return nil, fmt.Errorf("metadata exceeds max size %d", r.config.MaxSize)
+	}
+	return content, nil
+}
+
+// decodeDataUri decodes data uri in the form of data:[<mediatype>][;base64],<data>
+func decodeDataUri(uri string) ([]byte, error) {
+	comma := strings.Index(uri, ",")
+	if comma < 0 {
+		return nil, errors.New("invalid data uri")
+	}
+	header, data := uri[len("data:"):comma], uri[comma+1:]
+	if strings.HasSuffix(header, ";base64") {
+		return base64.StdEncoding.DecodeString(data)
+	}
+	decoded, err := url.PathUnescape(data)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(decoded), nil
+}
+
+// Parse parses metadata json. values of attributes are converted to string
+func Parse(content []byte) (*Metadata, error) {
+	var raw struct {
+		Name        interface{} `json:"name"`
+		Description interface{} `json:"description"`
+		Image       interface{} `json:"image"`
+		Attributes  []struct {
+			TraitType interface{} `json:"trait_type"`
+			Value     interface{} `json:"value"`
+		} `json:"attributes"`
+	}
+	if err := json.Unmarshal(content, &raw); err != nil {
+		return nil, err
+	}
+	metadata := &Metadata{
+		Name:        toString(raw.Name),
+		Description: toString(raw.Description),
+		Image:       toString(raw.Image),
+	}
+	for _, attr := range raw.Attributes {
+		metadata.Attributes = append(metadata.Attributes, Attribute{
+			TraitType: toString(attr.TraitType),
+			Value:     toString(attr.Value),
+		})
+	}
+	return metadata, nil
+}
+
+func toString(v interface{}) string {
+	switch c := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return c
+	case float64, bool:
+		return fmt.Sprint(c)
+	default:
+		b, _ := json.Marshal(c)
+		return string(b)
+	}
+}
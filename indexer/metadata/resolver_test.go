@@ -0,0 +1,93 @@
+package metadata
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testMetadata = `{"name":"Aergo #1","description":"first","image":"ipfs://QmImage","attributes":[{"trait_type":"level","value":3},{"trait_type":"color","value":"red"}]}`
+
+func TestResolve(t *testing.T) {
+	fetched := 0
+	content := testMetadata
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetched++
+		switch r.URL.Path {
+		case "/1.json", "/ipfs/QmMeta/1.json":
+			w.Write([]byte(content))
+		case "/large.json":
+			w.Write([]byte(strings.Repeat(" ", 200) + testMetadata))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	resolver := NewResolver(Config{IpfsGateway: server.URL + "/ipfs", MaxSize: 256, Refresh: time.Hour})
+	expect := &Metadata{
+		Name:        "Aergo #1",
+		Description: "first",
+		Image:       "ipfs://QmImage",
+		Attributes:  []Attribute{{TraitType: "level", Value: "3"}, {TraitType: "color", Value: "red"}},
+	}
+	fn_test := func(uri string, expect *Metadata) {
+		metadata, err := resolver.Resolve(uri)
+		require.NoError(t, err)
+		require.NotEmpty(t, metadata.Hash)
+		require.False(t, metadata.FetchedAt.IsZero())
+		metadata.Hash, metadata.FetchedAt = "", time.Time{}
+		require.Equal(t, expect, metadata)
+	}
+
+	// http, ipfs, data
+	fn_test(server.URL+"/1.json", expect)
+	fn_test("ipfs://QmMeta/1.json", expect)
+	fn_test("ipfs://ipfs/QmMeta/1.json", expect)
+	fn_test("data:application/json;base64,"+base64.StdEncoding.EncodeToString([]byte(testMetadata)), expect)
+	fn_test(`data:application/json,{"name":"Aergo%20%232"}`, &Metadata{Name: "Aergo #2"})
+	require.Equal(t, 3, fetched)
+
+	// cached until due
+	content = `{"name":"changed"}`
+	fn_test(server.URL+"/1.json", expect)
+	require.Equal(t, 3, fetched)
+
+	// limits and errors
+	_, err := resolver.Resolve(server.URL + "/large.json")
+	require.Error(t, err)
+	_, err = resolver.Resolve(server.URL + "/none.json")
+	require.Error(t, err)
+	_, err = resolver.Resolve("ftp://example.com/1.json")
+	require.Error(t, err)
+}
+
+func TestResolveRefresh(t *testing.T) {
+	content := testMetadata
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	resolver := NewResolver(Config{Refresh: time.Nanosecond})
+	first, err := resolver.Resolve(server.URL)
+	require.NoError(t, err)
+	require.True(t, resolver.Due(first.FetchedAt))
+
+	// same content keeps hash
+	second, err := resolver.Resolve(server.URL)
+	require.NoError(t, err)
+	require.Equal(t, first.Hash, second.Hash)
+
+	// changed content is parsed again
+	content = `{"name":"changed"}`
+	third, err := resolver.Resolve(server.URL)
+	require.NoError(t, err)
+	require.NotEqual(t, first.Hash, third.Hash)
+	require.Equal(t, "changed", third.Name)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/aergoio/aergo-indexer-2.0/indexer/transaction"
 	"github.com/aergoio/aergo-indexer-2.0/lua_compiler"
 	"github.com/aergoio/aergo-indexer-2.0/types"
+	"github.com/mr-tron/base58"
 )
 
 // IndexTxs indexes a list of transactions in bulk
@@ -38,14 +39,45 @@ func (ns *Indexer) Miner(RChannel chan BlockInfo, MinerGRPC *client.AergoClientC
 			}
 		}
 		// Get Block doc
-		blockDoc := doc.ConvBlock(block, ns.cache.getPeerId(block.Header.PubKey))
+		blockDoc := doc.ConvBlock(block, ns.cache.getPeerId(block.Header.PubKey), ns.votingRewardAt(block.Header.BlockNo), ns.resolveName)
+
+		receipts := ns.getReceipts(block, MinerGRPC)
+
+		// update chain params before txs use them, and observe the block reward ( sync only )
+		if info.Type == BlockType_Sync {
+			ns.MinerChainParams(blockDoc, MinerGRPC)
+			ns.MinerReward(block, blockDoc, MinerGRPC)
+		}
+		txDocs := make([]*doc.EsTx, 0, len(block.Body.Txs))
 		for i, tx := range block.Body.Txs {
 			txIdx := uint64(i)
-			ns.MinerTx(txIdx, info, blockDoc, tx, MinerGRPC)
+			txDocs = append(txDocs, ns.MinerTx(txIdx, info, blockDoc, tx, receipts[i], MinerGRPC))
 		}
 
 		// Add block doc
 		ns.addBlock(info.Type, blockDoc)
+		if info.Type == BlockType_Bulk {
+			ns.markStats("chain_stats", "chain", blockDoc.Timestamp, blockDoc.BlockNo)
+		}
+
+		// update bp set, raft members, bp stats, bp votes, token holders and rollups ( sync only )
+		if info.Type == BlockType_Sync {
+			consensusInfo := ns.getConsensusInfo(blockDoc, MinerGRPC)
+			ns.MinerBpChange(blockDoc, consensusInfo)
+			if ns.consensus == transaction.ConsensusRaft {
+				ns.MinerRaftMember(blockDoc, consensusInfo)
+			}
+			ns.MinerBpStats(blockDoc)
+			if ns.bpVotesInterval > 0 && blockHeight%ns.bpVotesInterval == 0 {
+				ns.MinerBpVotes(blockDoc, MinerGRPC)
+			}
+			if ns.holdersRecountInterval > 0 && blockHeight%ns.holdersRecountInterval == 0 {
+				ns.recountInBackground(ns.RecountTokenHolders)
+			}
+			ns.accumulateChainStats(blockDoc, txDocs)
+			ns.flushStatsAcc(blockDoc)
+			ns.flushStats(blockHeight, false)
+		}
 
 		// update variables per 300 blocks
 		if info.Type == BlockType_Sync && blockHeight%300 == 0 {
@@ -54,29 +86,85 @@ func (ns *Indexer) Miner(RChannel chan BlockInfo, MinerGRPC *client.AergoClientC
 	}
 }
 
-func (ns *Indexer) MinerTx(txIdx uint64, info BlockInfo, blockDoc *doc.EsBlock, tx *types.Tx, MinerGRPC *client.AergoClientController) {
-	// get receipt
-	receipt, err := MinerGRPC.GetReceipt(tx.GetHash())
+// getConsensusInfo returns the consensus info of the tip, queried once per block for the bp set and raft members. returns nil if not needed while catching up or failed to get
+func (ns *Indexer) getConsensusInfo(blockDoc *doc.EsBlock, MinerGRPC *client.AergoClientController) *types.ConsensusInfo {
+	if blockDoc.BlockNo < ns.getLastHeight() && ns.consensus != transaction.ConsensusRaft {
+		return nil
+	}
+	consensusInfo, err := MinerGRPC.GetConsensusInfo()
 	if err != nil {
-		receipt = nil
+		ns.log.Warn().Err(err).Uint64("blockNo", blockDoc.BlockNo).Msg("Failed to get consensus info")
+		return nil
 	}
+	return consensusInfo
+}
 
+// getReceipts returns the receipts of txs in the block. the receipt is nil if failed to get
+func (ns *Indexer) getReceipts(block *types.Block, MinerGRPC *client.AergoClientController) []*types.Receipt {
+	receipts := make([]*types.Receipt, len(block.Body.Txs))
+	for i, tx := range block.Body.Txs {
+		receipt, err := MinerGRPC.GetReceipt(tx.GetHash())
+		if err == nil {
+			receipts[i] = receipt
+		}
+	}
+	return receipts
+}
+
+// MinerTx indexes the tx and the documents derived from it, and returns the tx doc
+func (ns *Indexer) MinerTx(txIdx uint64, info BlockInfo, blockDoc *doc.EsBlock, tx *types.Tx, receipt *types.Receipt, MinerGRPC *client.AergoClientController) (txDoc *doc.EsTx) {
 	// get Tx doc
-	txDoc := doc.ConvTx(txIdx, tx, receipt, blockDoc)
+	txDoc = doc.ConvTx(txIdx, tx, receipt, blockDoc, ns.gasPriceAt(blockDoc.BlockNo), ns.resolveName)
+
+	// decode call arguments
+	if txDoc.Method != "" {
+		txDoc.Args = ns.MinerTxArgs(txDoc, tx, MinerGRPC)
+	}
 
 	// add tx doc ( defer )
 	defer ns.addTx(info.Type, txDoc)
 
+	// fees sponsored by the contract ( sync only, bulk applies them after bulk sync )
+	if txDoc.FeeDelegation == true && info.Type == BlockType_Sync {
+		ns.MinerFeeSponsor(txDoc)
+	}
+
 	// Process governance and name transactions
 	if tx.GetBody().GetType() == types.TxType_GOVERNANCE && string(tx.GetBody().GetRecipient()) == "aergo.name" {
-		nameDoc := doc.ConvName(tx, txDoc.BlockNo)
+		nameDoc := doc.ConvName(tx, txDoc.BlockNo, ns.resolveName)
 		ns.addName(nameDoc)
+		ns.MinerNameState(txDoc, nameDoc, MinerGRPC)
 		return
 	}
 
+	// Process bp voting ( sync only )
+	if txDoc.Category == transaction.TxVoting && info.Type == BlockType_Sync {
+		ns.MinerAccountVotes(txDoc, tx, MinerGRPC)
+	}
+
+	// Process enterprise config and cluster changes
+	if txDoc.Category == transaction.TxEnterprise || txDoc.Category == transaction.TxConf || txDoc.Category == transaction.TxCluster {
+		ns.MinerEnterprise(info.Type, txDoc, tx, MinerGRPC)
+	}
+
 	// Balance from, to
-	ns.cache.storeBalance(transaction.EncodeAndResolveAccount(tx.Body.Account, txDoc.BlockNo))
-	ns.cache.storeBalance(transaction.EncodeAndResolveAccount(tx.Body.Recipient, txDoc.BlockNo))
+	ns.cache.storeBalance(transaction.EncodeAndResolveAccount(tx.Body.Account, txDoc.BlockNo, ns.resolveName))
+	ns.cache.storeBalance(transaction.EncodeAndResolveAccount(tx.Body.Recipient, txDoc.BlockNo, ns.resolveName))
+	for _, op := range txDoc.Ops {
+		if op.Command == transaction.MulticallSend && transaction.IsAddress(op.Contract) {
+			ns.cache.storeBalance(op.Contract)
+		}
+	}
+
+	// Process contract abi and version ( deploy, redeploy )
+	var detection *transaction.TokenDetection
+	if (txDoc.Category == transaction.TxDeploy || txDoc.Category == transaction.TxRedeploy) && receipt != nil && txDoc.Status != "ERROR" {
+		abiDoc := ns.MinerContractAbi(txDoc, receipt.ContractAddress, MinerGRPC)
+		ns.MinerContractVersion(txDoc, receipt.ContractAddress, abiDoc)
+		if txDoc.Category == transaction.TxDeploy {
+			detection = ns.MinerTokenStandard(abiDoc, receipt.ContractAddress, MinerGRPC)
+		}
+	}
 
 	// Process Token and TokenTransfer
 	switch txDoc.Category {
@@ -91,6 +179,10 @@ func (ns *Indexer) MinerTx(txIdx uint64, info BlockInfo, blockDoc *doc.EsBlock,
 	// Process Contract Deploy
 	if txDoc.Category == transaction.TxDeploy {
 		contractDoc := doc.ConvContract(txDoc, receipt.ContractAddress)
+		contractDoc.SetTokenDetection(detection)
+		if info.Type == BlockType_Bulk {
+			ns.applyLatestVersion(contractDoc)
+		}
 		ns.addContract(info.Type, contractDoc)
 	}
 
@@ -101,21 +193,24 @@ func (ns *Indexer) MinerTx(txIdx uint64, info BlockInfo, blockDoc *doc.EsBlock,
 	}
 
 	// Process POLICY 2 Token
-	tType := transaction.MaybeTokenCreation(tx)
-	switch tType {
-	case transaction.TokenARC1, transaction.TokenARC2:
+	if detection.IsToken() {
+		tType := detection.Type
 		name, symbol, decimals := MinerGRPC.QueryTokenInfo(receipt.ContractAddress)
 		if name == "" {
 			return
 		}
 
 		// Add Token doc
-		supply, supplyFloat := MinerGRPC.QueryTotalSupply(receipt.ContractAddress, ns.isCccvNft(receipt.ContractAddress))
+		supply, supplyFloat := MinerGRPC.QueryTotalSupply(receipt.ContractAddress, ns.contractProfile(receipt.ContractAddress))
 		tokenDoc := doc.ConvToken(txDoc, receipt.ContractAddress, tType, name, symbol, decimals, supply, supplyFloat)
 		ns.addToken(tokenDoc)
 
 		// Add Contract Doc
 		contractDoc := doc.ConvContract(txDoc, receipt.ContractAddress)
+		contractDoc.SetTokenDetection(detection)
+		if info.Type == BlockType_Bulk {
+			ns.applyLatestVersion(contractDoc)
+		}
 		ns.addContract(info.Type, contractDoc)
 
 		ns.log.Info().Str("contract", transaction.EncodeAccount(receipt.ContractAddress)).Msg("Token created ( Policy 2 )")
@@ -129,6 +224,9 @@ func (ns *Indexer) MinerEvent(info BlockInfo, blockDoc *doc.EsBlock, txDoc *doc.
 	eventDoc := doc.ConvEvent(event, blockDoc, txDoc, txIdx)
 	ns.addEvent(info.Type, eventDoc)
 
+	// add event to custom event indices
+	ns.MinerEventIndex(info.Type, blockDoc, eventDoc)
+
 	// parse event by contract address
 	ns.MinerEventByAddr(blockDoc, txDoc, event, MinerGRPC)
 
@@ -169,17 +267,18 @@ func (ns *Indexer) MinerEventByName(info BlockInfo, blockDoc *doc.EsBlock, txDoc
 		if name == "" {
 			return
 		}
-		supply, supplyFloat := MinerGRPC.QueryTotalSupply(contractAddress, ns.isCccvNft(contractAddress))
+		supply, supplyFloat := MinerGRPC.QueryTotalSupply(contractAddress, ns.contractProfile(contractAddress))
 		tokenDoc := doc.ConvToken(txDoc, contractAddress, tokenType, name, symbol, decimals, supply, supplyFloat)
 		ns.addToken(tokenDoc)
 
 		// Add AccountTokens Doc
-		balance, balanceFloat := MinerGRPC.QueryBalanceOf(contractAddress, txDoc.Account, ns.isCccvNft(contractAddress))
-		accountTokensDoc := doc.ConvAccountTokens(tokenType, transaction.EncodeAndResolveAccount(contractAddress, txDoc.BlockNo), txDoc.Timestamp, txDoc.Account, balance, balanceFloat)
+		balance, balanceFloat := MinerGRPC.QueryBalanceOf(contractAddress, txDoc.Account, ns.contractProfile(contractAddress))
+		accountTokensDoc := doc.ConvAccountTokens(tokenType, transaction.EncodeAccount(contractAddress), txDoc.Timestamp, txDoc.Account, balance, balanceFloat, ns.tokenDecimals(contractAddress, MinerGRPC))
 		ns.addAccountTokens(info.Type, accountTokensDoc)
 
 		// Add Contract Doc
 		contractDoc := doc.ConvContract(txDoc, contractAddress)
+		contractDoc.SetTokenDetection(ns.MinerTokenStandard(ns.getContractAbi(contractDoc.Id, txDoc.BlockNo, MinerGRPC), contractAddress, MinerGRPC))
 		ns.addContract(info.Type, contractDoc)
 
 		ns.log.Info().Str("contract", transaction.EncodeAccount(contractAddress)).Msg("Token created ( Policy 1 )")
@@ -191,25 +290,25 @@ func (ns *Indexer) MinerEventByName(info BlockInfo, blockDoc *doc.EsBlock, txDoc
 		}
 
 		// Add TokenTransfer Doc
-		tokenType, tokenId, amount, amountFloat := MinerGRPC.QueryOwnerOf(contractAddress, amountOrId, ns.isCccvNft(event.ContractAddress))
-		tokenTransferDoc := doc.ConvTokenTransfer(contractAddress, txDoc, int(event.EventIdx), accountFrom, accountTo, tokenId, amount, amountFloat)
+		decimals := ns.tokenDecimals(contractAddress, MinerGRPC)
+		tokenType, tokenId, amount, amountFloat := MinerGRPC.QueryOwnerOf(contractAddress, amountOrId, ns.contractProfile(contractAddress))
+		tokenTransferDoc := doc.ConvTokenTransfer(contractAddress, txDoc, int(event.EventIdx), accountFrom, accountTo, tokenId, amount, amountFloat, decimals)
 		ns.addTokenTransfer(info.Type, tokenTransferDoc)
 
 		// Update Token Doc
-		supply, supplyFloat := MinerGRPC.QueryTotalSupply(contractAddress, ns.isCccvNft(contractAddress))
-		tokenUpDoc := doc.ConvTokenUp(txDoc, contractAddress, supply, supplyFloat)
+		supply, supplyFloat := MinerGRPC.QueryTotalSupply(contractAddress, ns.contractProfile(contractAddress))
+		tokenUpDoc := doc.ConvTokenUp(txDoc, contractAddress, tokenType, supply, supplyFloat, decimals)
 		ns.updateToken(tokenUpDoc)
 
 		// Add AccountTokens Doc ( update TO-Account )
-		balance, balanceFloat := MinerGRPC.QueryBalanceOf(contractAddress, tokenTransferDoc.To, ns.isCccvNft(contractAddress))
-		accountTokensDoc := doc.ConvAccountTokens(tokenType, tokenTransferDoc.TokenAddress, tokenTransferDoc.Timestamp, tokenTransferDoc.To, balance, balanceFloat)
+		balance, balanceFloat := MinerGRPC.QueryBalanceOf(contractAddress, tokenTransferDoc.To, ns.contractProfile(contractAddress))
+		accountTokensDoc := doc.ConvAccountTokens(tokenType, tokenTransferDoc.TokenAddress, tokenTransferDoc.Timestamp, tokenTransferDoc.To, balance, balanceFloat, decimals)
 		ns.addAccountTokens(info.Type, accountTokensDoc)
 
 		// Add NFT Doc
 		if tokenType == transaction.TokenARC2 {
-			tokenUri, imageUrl := MinerGRPC.QueryNFTMetadata(contractAddress, tokenTransferDoc.TokenId)
-			nftDoc := doc.ConvNFT(tokenTransferDoc, tokenUri, imageUrl)
-			ns.addNFT(nftDoc)
+			ns.MinerNFT(tokenTransferDoc, contractAddress, tokenTransferDoc.TokenId, MinerGRPC)
+			ns.addNFTHistory(info.Type, doc.ConvNFTHistory(txDoc, tokenTransferDoc, int(event.EventIdx), transaction.EventMint))
 		}
 		ns.log.Debug().Str("contract", transaction.EncodeAccount(contractAddress)).Str("type", string(tokenType)).Msg("Event mint")
 	case transaction.EventTransfer:
@@ -220,28 +319,28 @@ func (ns *Indexer) MinerEventByName(info BlockInfo, blockDoc *doc.EsBlock, txDoc
 		}
 
 		// Add TokenTransfer Doc
-		tokenType, tokenId, amount, amountFloat := MinerGRPC.QueryOwnerOf(contractAddress, amountOrId, ns.isCccvNft(contractAddress))
-		tokenTransferDoc := doc.ConvTokenTransfer(contractAddress, txDoc, int(event.EventIdx), accountFrom, accountTo, tokenId, amount, amountFloat)
+		decimals := ns.tokenDecimals(contractAddress, MinerGRPC)
+		tokenType, tokenId, amount, amountFloat := MinerGRPC.QueryOwnerOf(contractAddress, amountOrId, ns.contractProfile(contractAddress))
+		tokenTransferDoc := doc.ConvTokenTransfer(contractAddress, txDoc, int(event.EventIdx), accountFrom, accountTo, tokenId, amount, amountFloat, decimals)
 		if tokenTransferDoc.Amount == "" {
 			return
 		}
 		ns.addTokenTransfer(info.Type, tokenTransferDoc)
 
 		// Add AccountTokens Doc ( update TO-Account )
-		balance, balanceFloat := MinerGRPC.QueryBalanceOf(contractAddress, tokenTransferDoc.To, ns.isCccvNft(contractAddress))
-		accountTokensDoc := doc.ConvAccountTokens(tokenType, tokenTransferDoc.TokenAddress, tokenTransferDoc.Timestamp, tokenTransferDoc.To, balance, balanceFloat)
+		balance, balanceFloat := MinerGRPC.QueryBalanceOf(contractAddress, tokenTransferDoc.To, ns.contractProfile(contractAddress))
+		accountTokensDoc := doc.ConvAccountTokens(tokenType, tokenTransferDoc.TokenAddress, tokenTransferDoc.Timestamp, tokenTransferDoc.To, balance, balanceFloat, decimals)
 		ns.addAccountTokens(info.Type, accountTokensDoc)
 
 		// Add AccountTokens Doc ( update FROM-Account )
-		balance, balanceFloat = MinerGRPC.QueryBalanceOf(contractAddress, tokenTransferDoc.From, ns.isCccvNft(contractAddress))
-		accountTokensDoc = doc.ConvAccountTokens(tokenType, tokenTransferDoc.TokenAddress, tokenTransferDoc.Timestamp, tokenTransferDoc.From, balance, balanceFloat)
+		balance, balanceFloat = MinerGRPC.QueryBalanceOf(contractAddress, tokenTransferDoc.From, ns.contractProfile(contractAddress))
+		accountTokensDoc = doc.ConvAccountTokens(tokenType, tokenTransferDoc.TokenAddress, tokenTransferDoc.Timestamp, tokenTransferDoc.From, balance, balanceFloat, decimals)
 		ns.addAccountTokens(info.Type, accountTokensDoc)
 
 		// Add NFT Doc ( update NFT )
 		if tokenType == transaction.TokenARC2 {
-			tokenUri, imageUrl := MinerGRPC.QueryNFTMetadata(contractAddress, tokenId)
-			nftDoc := doc.ConvNFT(tokenTransferDoc, tokenUri, imageUrl)
-			ns.addNFT(nftDoc)
+			ns.MinerNFT(tokenTransferDoc, contractAddress, tokenId, MinerGRPC)
+			ns.addNFTHistory(info.Type, doc.ConvNFTHistory(txDoc, tokenTransferDoc, int(event.EventIdx), transaction.EventTransfer))
 		}
 		ns.log.Debug().Str("contract", transaction.EncodeAccount(contractAddress)).Str("type", string(tokenType)).Msg("Event transfer")
 	case transaction.EventBurn:
@@ -252,28 +351,28 @@ func (ns *Indexer) MinerEventByName(info BlockInfo, blockDoc *doc.EsBlock, txDoc
 		}
 
 		// Add TokenTransfer Doc
-		tokenType, tokenId, amount, amountFloat := MinerGRPC.QueryOwnerOf(contractAddress, amountOrId, ns.isCccvNft(contractAddress))
-		tokenTransferDoc := doc.ConvTokenTransfer(contractAddress, txDoc, int(event.EventIdx), accountFrom, accountTo, tokenId, amount, amountFloat)
+		decimals := ns.tokenDecimals(contractAddress, MinerGRPC)
+		tokenType, tokenId, amount, amountFloat := MinerGRPC.QueryOwnerOf(contractAddress, amountOrId, ns.contractProfile(contractAddress))
+		tokenTransferDoc := doc.ConvTokenTransfer(contractAddress, txDoc, int(event.EventIdx), accountFrom, accountTo, tokenId, amount, amountFloat, decimals)
 		if tokenTransferDoc.Amount == "" {
 			return
 		}
 		ns.addTokenTransfer(info.Type, tokenTransferDoc)
 
 		// Update TokenUp Doc
-		supply, supplyFloat := MinerGRPC.QueryTotalSupply(contractAddress, ns.isCccvNft(contractAddress))
-		tokenUpDoc := doc.ConvTokenUp(txDoc, contractAddress, supply, supplyFloat)
+		supply, supplyFloat := MinerGRPC.QueryTotalSupply(contractAddress, ns.contractProfile(contractAddress))
+		tokenUpDoc := doc.ConvTokenUp(txDoc, contractAddress, tokenType, supply, supplyFloat, decimals)
 		ns.updateToken(tokenUpDoc)
 
 		// Add AccountTokens Doc ( update FROM-Account )
-		balance, balanceFloat := MinerGRPC.QueryBalanceOf(contractAddress, tokenTransferDoc.From, ns.isCccvNft(contractAddress))
-		accountTokensDoc := doc.ConvAccountTokens(tokenType, tokenTransferDoc.TokenAddress, tokenTransferDoc.Timestamp, tokenTransferDoc.From, balance, balanceFloat)
+		balance, balanceFloat := MinerGRPC.QueryBalanceOf(contractAddress, tokenTransferDoc.From, ns.contractProfile(contractAddress))
+		accountTokensDoc := doc.ConvAccountTokens(tokenType, tokenTransferDoc.TokenAddress, tokenTransferDoc.Timestamp, tokenTransferDoc.From, balance, balanceFloat, decimals)
 		ns.addAccountTokens(info.Type, accountTokensDoc)
 
 		// Add NFT Doc
 		if tokenType == transaction.TokenARC2 {
-			tokenUri, imageUrl := MinerGRPC.QueryNFTMetadata(contractAddress, tokenId)
-			nftDoc := doc.ConvNFT(tokenTransferDoc, tokenUri, imageUrl)
-			ns.addNFT(nftDoc)
+			ns.MinerNFT(tokenTransferDoc, contractAddress, tokenId, MinerGRPC)
+			ns.addNFTHistory(info.Type, doc.ConvNFTHistory(txDoc, tokenTransferDoc, int(event.EventIdx), transaction.EventBurn))
 		}
 		ns.log.Debug().Str("contract", transaction.EncodeAccount(contractAddress)).Str("type", string(tokenType)).Msg("Event burn")
 	default:
@@ -347,7 +446,7 @@ func (ns *Indexer) MinerContractVerified(tokenAddr, contractAddr, metadata strin
 			ns.log.Error().Err(err).Str("addr", contractAddr).Msg("contractDoc is not exist. wait until contractDoc added")
 			return contractAddr
 		}
-		contractUpDoc := doc.ConvContractUp(contractDoc.Id, string(NotVerified), "", "", "")
+		contractUpDoc := doc.ConvContractUp(contractDoc.Id, string(NotVerified), "", "", "", "", "")
 		ns.updateContract(contractUpDoc)
 		ns.log.Info().Str("contract", contractAddr).Str("token", tokenAddr).Msg("verified contract removed")
 	}
@@ -367,38 +466,48 @@ func (ns *Indexer) MinerContractVerified(tokenAddr, contractAddr, metadata strin
 			ns.log.Debug().Str("method", "verifyContract").Str("tokenAddr", tokenAddr).Msg("codeUrl is not changed, skip")
 			return updateContractAddr
 		}
-		code, err = lua_compiler.GetCode(codeUrl)
+		var compiler, reason string
+		code, err = ns.compilers.GetCode(codeUrl)
 		if err != nil {
 			ns.log.Error().Err(err).Str("method", "verifyContract").Msg("Failed to get code")
-		} else if len(code) > 0 {
-			status = string(Verified)
-		}
-
-		// TODO : valid bytecode
-		/*
-			bytecode, err := lua_compiler.CompileCode(code)
-			if err != nil {
-				ns.log.Error().Err(err).Str("method", "verifyContract").Msg("Failed to compile code")
+			reason = "failed to get code"
+		} else if len(code) == 0 {
+			reason = "empty code"
+		} else {
+			blockNo := contractDoc.VersionBlockNo
+			if blockNo == 0 {
+				blockNo = contractDoc.BlockNo
 			}
-
-			// compare bytecode and payload
-			var status string
-			if bytes.Contains([]byte(contractDoc.Payload), bytecode) == true {
+			version, _ := ns.chainConfig.HardforkAt(blockNo)
+			verification := lua_compiler.Verify(ns.compilers.CompilerOf(version), code, ns.deployedPayload(contractDoc, MinerGRPC))
+			if verification.Verified {
 				status = string(Verified)
 			} else {
-				ns.log.Error().Str("method", "verifyContract").Str("tokenAddr", tokenAddr).Msg("Failed to verify contract")
-				fmt.Println([]byte(contractDoc.Payload))
-				var i interface{}
-				json.Unmarshal([]byte(contractDoc.Payload), i)
-				fmt.Println(i)
-				fmt.Println(bytecode)
-				status = string(NotVerified)
+				ns.log.Warn().Str("method", "verifyContract").Str("contract", updateContractAddr).Str("reason", verification.Reason).Msg("Failed to verify contract")
 			}
-		*/
+			compiler, reason = verification.CompilerVersion, verification.Reason
+		}
 
-		contractUpDoc := doc.ConvContractUp(updateContractAddr, status, tokenAddr, codeUrl, code)
+		contractUpDoc := doc.ConvContractUp(updateContractAddr, status, tokenAddr, codeUrl, code, compiler, reason)
 		ns.updateContract(contractUpDoc)
 		ns.log.Info().Str("contract", updateContractAddr).Str("token", tokenAddr).Msg("verified contract updated")
 	}
 	return updateContractAddr
 }
+
+// deployedPayload returns the payload of current version from node, since stored payload is not binary safe
+func (ns *Indexer) deployedPayload(contractDoc *doc.EsContract, MinerGRPC *client.AergoClientController) []byte {
+	txId := contractDoc.VersionTx
+	if txId == "" {
+		txId = contractDoc.TxId
+	}
+	txHash, err := base58.Decode(txId)
+	if err == nil {
+		var tx *types.Tx
+		if tx, err = MinerGRPC.GetTx(txHash); err == nil {
+			return tx.GetBody().GetPayload()
+		}
+	}
+	ns.log.Warn().Err(err).Str("tx", txId).Msg("Failed to get deploy tx, use stored payload")
+	return []byte(contractDoc.Payload)
+}
@@ -0,0 +1,78 @@
+package indexer
+
+import (
+	"github.com/aergoio/aergo-indexer-2.0/indexer/client"
+	doc "github.com/aergoio/aergo-indexer-2.0/indexer/documents"
+	"github.com/aergoio/aergo-indexer-2.0/indexer/transaction"
+)
+
+// initNameState loads name states into cache
+func (ns *Indexer) initNameState() {
+	if err := ns.ScrollNameState(func(nameStateDoc *doc.EsNameState) {
+		ns.cache.storeNameState(nameStateDoc)
+	}); err != nil {
+		ns.log.Error().Err(err).Str("func", "initNameState").Msg("error while scroll name state")
+	}
+}
+
+// resolveName returns the destination of a name as of the given block from the name states indexed. a name not indexed yet is left unresolved, so that conversions do not wait for the node
+func (ns *Indexer) resolveName(name string, blockNo uint64) (string, bool) {
+	ns.cache.nameLock.Lock()
+	defer ns.cache.nameLock.Unlock()
+
+	nameState, exist := ns.cache.getNameState(name)
+	if exist != true {
+		return "", false
+	}
+	return nameState.DestinationAt(blockNo)
+}
+
+// MinerNameState applies a name transaction to the name state, reconciled with the node
+func (ns *Indexer) MinerNameState(txDoc *doc.EsTx, nameDoc *doc.EsName, MinerGRPC *client.AergoClientController) {
+	if nameDoc.Name == "error" || txDoc.Status == "ERROR" {
+		return
+	}
+
+	owner, destination := txDoc.Account, nameDoc.Address
+	nameInfo, err := MinerGRPC.GetNameInfo(nameDoc.Name, txDoc.BlockNo)
+	if err != nil {
+		ns.log.Warn().Err(err).Str("name", nameDoc.Name).Uint64("blockNo", txDoc.BlockNo).Msg("Failed to get name info")
+	} else {
+		owner = transaction.EncodeAccount(nameInfo.GetOwner())
+		destination = transaction.EncodeAccount(nameInfo.GetDestination())
+	}
+
+	ns.cache.nameLock.Lock()
+	defer ns.cache.nameLock.Unlock()
+
+	nameState, exist := ns.cache.getNameState(nameDoc.Name)
+	if exist != true {
+		nameState = doc.NewNameState(nameDoc.Name)
+	}
+	nameState.ApplyHistory(doc.ConvNameHistory(txDoc, owner, destination))
+	ns.cache.storeNameState(nameState)
+	ns.addNameState(nameState)
+}
+
+// rollbackNameState removes name history from the given block
+func (ns *Indexer) rollbackNameState(fromBlockNo uint64) {
+	ns.cache.nameLock.Lock()
+	defer ns.cache.nameLock.Unlock()
+
+	var rollbacks []*doc.EsNameState
+	ns.cache.nameState.Range(func(k, v interface{}) bool {
+		if nameState, ok := v.(*doc.EsNameState); ok && nameState.BlockNo >= fromBlockNo {
+			rollbacks = append(rollbacks, nameState)
+		}
+		return true
+	})
+	for _, nameState := range rollbacks {
+		nameState.RollbackHistory(fromBlockNo)
+		if len(nameState.History) == 0 {
+			ns.cache.deleteNameState(nameState.Name)
+			ns.deleteNameState(nameState.Name)
+		} else {
+			ns.addNameState(nameState)
+		}
+	}
+}
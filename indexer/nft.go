@@ -0,0 +1,140 @@
+package indexer
+
+import (
+	"time"
+
+	"github.com/aergoio/aergo-indexer-2.0/indexer/client"
+	"github.com/aergoio/aergo-indexer-2.0/indexer/db"
+	doc "github.com/aergoio/aergo-indexer-2.0/indexer/documents"
+	"github.com/aergoio/aergo-indexer-2.0/indexer/transaction"
+)
+
+const (
+	nftMetadataRefreshBatch  = 100             // max number of nfts which metadata is re-fetched at once
+	nftMetadataRefreshPeriod = 5 * time.Minute // interval to look for nfts which metadata is due
+	nftMetadataQueueSize     = 1000            // max number of pending fetches, more are dropped and picked up by refresh
+	nftMetadataWorkers       = 4
+)
+
+// nftMetadataJob is a pending fetch of metadata of a nft
+type nftMetadataJob struct {
+	nftId    string
+	tokenUri string
+}
+
+// startNFTMetadata starts the workers which fetch nft metadata, and the refresh of metadata which is due
+func (ns *Indexer) startNFTMetadata() {
+	if ns.nftMetadata == nil {
+		return
+	}
+	ns.nftMetadataQueue = make(chan nftMetadataJob, nftMetadataQueueSize)
+	for i := 0; i < nftMetadataWorkers; i++ {
+		go ns.nftMetadataWorker()
+	}
+	go func() {
+		for {
+			ns.refreshNFTMetadata()
+			time.Sleep(nftMetadataRefreshPeriod)
+		}
+	}()
+}
+
+func (ns *Indexer) nftMetadataWorker() {
+	for job := range ns.nftMetadataQueue {
+		md, err := ns.nftMetadata.Resolve(job.tokenUri)
+		if err != nil {
+			ns.log.Debug().Err(err).Str("nft", job.nftId).Str("tokenUri", job.tokenUri).Msg("Failed to resolve nft metadata")
+		}
+		ns.updateNFTMetadata(doc.ConvNFTMetadata(job.nftId, md, err))
+	}
+}
+
+// queueNFTMetadata adds a fetch of nft metadata without blocking. returns false if the queue is full
+func (ns *Indexer) queueNFTMetadata(nftId string, tokenUri string) bool {
+	select {
+	case ns.nftMetadataQueue <- nftMetadataJob{nftId: nftId, tokenUri: tokenUri}:
+		return true
+	default:
+		return false
+	}
+}
+
+// MinerNFT adds the nft doc of a token transfer. off-chain metadata of its token uri is fetched in background if enabled
+func (ns *Indexer) MinerNFT(tokenTransferDoc *doc.EsTokenTransfer, contractAddress []byte, tokenId string, MinerGRPC *client.AergoClientController) {
+	tokenUri, imageUrl := MinerGRPC.QueryNFTMetadata(contractAddress, tokenId)
+	nftDoc := doc.ConvNFT(tokenTransferDoc, tokenUri, imageUrl)
+	ns.addNFT(nftDoc)
+	if ns.nftMetadata != nil && tokenUri != "" && !ns.queueNFTMetadata(nftDoc.Id, tokenUri) {
+		ns.log.Debug().Str("nft", nftDoc.Id).Msg("nft metadata queue is full, wait for refresh")
+	}
+}
+
+// refreshNFTMetadata queues nfts which metadata is due or never fetched, oldest first
+func (ns *Indexer) refreshNFTMetadata() {
+	queued := 0
+	err := ns.ScrollNFTMetadataDue(time.Now().Add(-ns.nftMetadata.Refresh()), func(nftDoc *doc.EsNFT) bool {
+		if nftDoc.TokenUri == "" {
+			return true
+		}
+		ns.nftMetadataQueue <- nftMetadataJob{nftId: nftDoc.Id, tokenUri: nftDoc.TokenUri}
+		queued++
+		return queued < nftMetadataRefreshBatch
+	})
+	if err != nil {
+		ns.log.Warn().Err(err).Msg("Failed to scroll nft metadata")
+	}
+	ns.log.Info().Int("count", queued).Msg("nft metadata refresh queued")
+}
+
+// rollbackNFT deletes nft histories in range, and restores nfts changed in range to their last history before it.
+// nfts minted in range are deleted, and nfts minted before their histories were indexed get the owner at the current state
+func (ns *Indexer) rollbackNFT(fromBlockHeight uint64, toBlockHeight uint64) {
+	nfts := make(map[string]*doc.EsNFT)
+	minted := make(map[string]bool)
+	if err := ns.ScrollNFTHistory(fromBlockHeight, toBlockHeight, func(historyDoc *doc.EsNFTHistory) {
+		nfts[historyDoc.NftId] = &doc.EsNFT{TokenAddress: historyDoc.TokenAddress, TokenId: historyDoc.TokenId}
+		if historyDoc.Event == string(transaction.EventMint) {
+			minted[historyDoc.NftId] = true
+		}
+	}); err != nil {
+		ns.log.Warn().Err(err).Msg("Failed to scroll nft history")
+	}
+	if err := ns.ScrollNFTInRange(fromBlockHeight, toBlockHeight, func(nftDoc *doc.EsNFT) {
+		nfts[nftDoc.Id] = nftDoc
+	}); err != nil {
+		ns.log.Warn().Err(err).Msg("Failed to scroll nft")
+	}
+	ns.deleteTypeByQuery("nft_history", db.IntegerRangeQuery{Field: "blockno", Min: fromBlockHeight, Max: toBlockHeight})
+
+	for nftId, nftDoc := range nfts {
+		// deleted histories may be still visible until refresh, so filter by block number
+		var lastDoc *doc.EsNFTHistory
+		if err := ns.ScrollNFTHistoryOf(nftId, func(historyDoc *doc.EsNFTHistory) {
+			if historyDoc.BlockNo < fromBlockHeight {
+				lastDoc = historyDoc
+			}
+		}); err != nil {
+			ns.log.Warn().Err(err).Str("nft", nftId).Msg("Failed to scroll nft history, not restored")
+			continue
+		}
+
+		contractAddress := transaction.DecodeAccount(nftDoc.TokenAddress)
+		switch {
+		case lastDoc != nil:
+			tokenUri, imageUrl := ns.grpcClient.QueryNFTMetadata(contractAddress, lastDoc.TokenId)
+			ns.restoreNFT(doc.ConvNFTFromHistory(lastDoc, tokenUri, imageUrl))
+			ns.log.Info().Str("nft", nftId).Uint64("blockno", lastDoc.BlockNo).Msg("nft restored")
+		case minted[nftId]:
+			ns.deleteNFT(nftId)
+			ns.log.Info().Str("nft", nftId).Msg("nft deleted")
+		default:
+			tokenType, _, owner, _ := ns.grpcClient.QueryOwnerOf(contractAddress, nftDoc.TokenId, ns.contractProfile(contractAddress))
+			if tokenType != transaction.TokenARC2 {
+				ns.log.Warn().Str("nft", nftId).Msg("Failed to query owner of nft, not restored")
+				continue
+			}
+			ns.restoreNFT(&doc.EsNFTOwnerUp{BaseEsType: &doc.BaseEsType{Id: nftId}, Account: owner})
+			ns.log.Info().Str("nft", nftId).Str("owner", owner).Msg("nft owner restored")
+		}
+	}
+}
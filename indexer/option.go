@@ -1,6 +1,14 @@
 package indexer
 
 import (
+	"path/filepath"
+	"time"
+
+	"github.com/aergoio/aergo-indexer-2.0/indexer/client"
+	doc "github.com/aergoio/aergo-indexer-2.0/indexer/documents"
+	"github.com/aergoio/aergo-indexer-2.0/indexer/metadata"
+	"github.com/aergoio/aergo-indexer-2.0/indexer/transaction"
+	"github.com/aergoio/aergo-indexer-2.0/lua_compiler"
 	"github.com/aergoio/aergo-indexer-2.0/types"
 	"github.com/aergoio/aergo-lib/log"
 )
@@ -35,9 +43,30 @@ func SetPrefix(prefix string) IndexerOptionFunc {
 	}
 }
 
-func SetNetworkTypeForCccv(initCccvNft string) IndexerOptionFunc {
+// SetNetworkTypeForCccv loads the contract profiles shipped in profiles/<network>.json
+//
+// Deprecated: use SetContractProfiles
+func SetNetworkTypeForCccv(networkType string) IndexerOptionFunc {
+	if networkType == "" {
+		return SetContractProfiles("")
+	}
+	return SetContractProfiles(filepath.Join("profiles", networkType+".json"))
+}
+
+func SetContractProfiles(path string) IndexerOptionFunc {
 	return func(indexer *Indexer) error {
-		indexer.networkTypeForCccv = initCccvNft
+		if path == "" {
+			return nil
+		}
+		profiles, err := client.LoadContractProfiles(path)
+		if err != nil {
+			return err
+		}
+		for _, profile := range profiles.Profiles {
+			if err := indexer.addContractProfile(profile); err != nil {
+				return err
+			}
+		}
 		return nil
 	}
 }
@@ -106,3 +135,101 @@ func SetContractVerifyWhitelist(verifyContractWhitelist []string) IndexerOptionF
 		return nil
 	}
 }
+
+func SetBpVotesInterval(bpVotesInterval uint64) IndexerOptionFunc {
+	return func(indexer *Indexer) error {
+		indexer.bpVotesInterval = bpVotesInterval
+		return nil
+	}
+}
+
+func SetBpVotesCount(bpVotesCount uint32) IndexerOptionFunc {
+	return func(indexer *Indexer) error {
+		indexer.bpVotesCount = bpVotesCount
+		return nil
+	}
+}
+
+func SetHoldersRecountInterval(holdersRecountInterval uint64) IndexerOptionFunc {
+	return func(indexer *Indexer) error {
+		indexer.holdersRecountInterval = holdersRecountInterval
+		return nil
+	}
+}
+
+func SetTopHolders(topHolders int) IndexerOptionFunc {
+	return func(indexer *Indexer) error {
+		indexer.topHolders = topHolders
+		return nil
+	}
+}
+
+func SetBlockInterval(blockInterval time.Duration) IndexerOptionFunc {
+	return func(indexer *Indexer) error {
+		indexer.blockInterval = blockInterval
+		return nil
+	}
+}
+
+func SetObserveReward(observeReward bool) IndexerOptionFunc {
+	return func(indexer *Indexer) error {
+		indexer.observeReward = observeReward
+		return nil
+	}
+}
+
+func SetCompilers(compilers []string) IndexerOptionFunc {
+	return func(indexer *Indexer) error {
+		for _, spec := range compilers {
+			version, compiler, err := lua_compiler.ParsePin(spec)
+			if err != nil {
+				return err
+			}
+			indexer.compilers.Pin(version, compiler)
+		}
+		return nil
+	}
+}
+
+func SetHardforks(hardforks []string) IndexerOptionFunc {
+	return func(indexer *Indexer) error {
+		for _, spec := range hardforks {
+			version, blockNo, err := transaction.ParseHardfork(spec)
+			if err != nil {
+				return err
+			}
+			indexer.chainConfig.SetHardfork(version, blockNo)
+		}
+		return nil
+	}
+}
+
+func SetSourceDir(sourceDir string) IndexerOptionFunc {
+	return func(indexer *Indexer) error {
+		indexer.compilers.SetSourceDir(sourceDir)
+		return nil
+	}
+}
+
+func SetEventIndexConfig(path string) IndexerOptionFunc {
+	return func(indexer *Indexer) error {
+		if path == "" {
+			return nil
+		}
+		config, err := doc.LoadEventIndexConfig(path)
+		if err != nil {
+			return err
+		}
+		indexer.eventIndices = config.Indices
+		return nil
+	}
+}
+
+func SetNftMetadata(enable bool, config metadata.Config) IndexerOptionFunc {
+	return func(indexer *Indexer) error {
+		if enable {
+			indexer.nftMetadata = metadata.NewResolver(config)
+		}
+		return nil
+	}
+}
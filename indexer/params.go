@@ -0,0 +1,90 @@
+package indexer
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/aergoio/aergo-indexer-2.0/indexer/client"
+	doc "github.com/aergoio/aergo-indexer-2.0/indexer/documents"
+	"github.com/aergoio/aergo-indexer-2.0/types"
+)
+
+// initChainParams loads the changes of chain parameters, and records the current values of chain info.
+// unknown parameters are seeded at genesis, so that blocks indexed in bulk use them until the first change
+func (ns *Indexer) initChainParams(lastHeight uint64) {
+	if err := ns.ScrollChainParam(func(paramDoc *doc.EsChainParam) {
+		ns.cache.applyChainParam(paramDoc)
+	}); err != nil {
+		ns.log.Error().Err(err).Str("func", "initChainParams").Msg("error while scroll chain param")
+	}
+	chainInfo, err := ns.grpcClient.GetChainInfo()
+	if err != nil {
+		ns.log.Warn().Err(err).Msg("Failed to get chain info")
+		return
+	}
+	for name, value := range chainParamsOf(chainInfo) {
+		if _, exist := ns.cache.getChainParam(name, lastHeight); exist == true {
+			ns.recordChainParam(doc.ConvChainParam(name, lastHeight, time.Now(), value))
+			continue
+		}
+		ns.recordChainParam(doc.ConvChainParam(name, 0, ns.genesisTime(), value))
+	}
+}
+
+// genesisTime returns the timestamp of the genesis block, or zero time if failed to get
+func (ns *Indexer) genesisTime() time.Time {
+	block, err := ns.grpcClient.GetBlock(make([]byte, 8))
+	if err != nil {
+		ns.log.Warn().Err(err).Msg("Failed to get genesis block")
+		return time.Unix(0, 0)
+	}
+	return time.Unix(0, block.Header.Timestamp)
+}
+
+// MinerChainParams records the changes of chain parameters as of the block ( sync only )
+// chain info is the current state of node, so parameters are read only at the latest block
+func (ns *Indexer) MinerChainParams(blockDoc *doc.EsBlock, MinerGRPC *client.AergoClientController) {
+	// catching up
+	if blockDoc.BlockNo < ns.getLastHeight() {
+		return
+	}
+	chainInfo, err := MinerGRPC.GetChainInfo()
+	if err != nil {
+		ns.log.Warn().Err(err).Uint64("blockNo", blockDoc.BlockNo).Msg("Failed to get chain info")
+		return
+	}
+	for name, value := range chainParamsOf(chainInfo) {
+		ns.recordChainParam(doc.ConvChainParam(name, blockDoc.BlockNo, blockDoc.Timestamp, value))
+	}
+}
+
+// chainParamsOf returns the values of tracked chain parameters in chain info
+func chainParamsOf(chainInfo *types.ChainInfo) map[string]*big.Int {
+	return map[string]*big.Int{
+		doc.ParamGasPrice:     new(big.Int).SetBytes(chainInfo.GetGasprice()),
+		doc.ParamVotingReward: new(big.Int).SetBytes(chainInfo.GetVotingreward()),
+	}
+}
+
+func (ns *Indexer) recordChainParam(paramDoc *doc.EsChainParam) {
+	if ns.cache.applyChainParam(paramDoc) == true {
+		ns.addChainParam(paramDoc)
+		ns.log.Info().Str("name", paramDoc.Name).Str("value", paramDoc.Value).Uint64("blockNo", paramDoc.BlockNo).Msg("chain param changed")
+	}
+}
+
+// gasPriceAt returns the chain gas price at the block. empty if unknown
+func (ns *Indexer) gasPriceAt(blockNo uint64) string {
+	gasPrice, _ := ns.cache.getChainParam(doc.ParamGasPrice, blockNo)
+	return gasPrice
+}
+
+// votingRewardAt returns the voting reward of the block, which is paid since hardfork v2.
+// empty if no reward is paid, or unknown since the heights of hardforks are not set
+func (ns *Indexer) votingRewardAt(blockNo uint64) string {
+	if version, ok := ns.chainConfig.HardforkAt(blockNo); ok != true || version < 2 {
+		return ""
+	}
+	votingReward, _ := ns.cache.getChainParam(doc.ParamVotingReward, blockNo)
+	return votingReward
+}
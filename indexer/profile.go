@@ -0,0 +1,77 @@
+package indexer
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/aergoio/aergo-indexer-2.0/indexer/client"
+	doc "github.com/aergoio/aergo-indexer-2.0/indexer/documents"
+	"github.com/aergoio/aergo-indexer-2.0/indexer/transaction"
+	"github.com/aergoio/aergo-indexer-2.0/types"
+)
+
+// contractProfile returns the profile of a token contract, or nil if it is queried in the standard way
+func (ns *Indexer) contractProfile(contractAddress []byte) *client.ContractProfile {
+	return ns.contractProfiles[string(contractAddress)]
+}
+
+// addContractProfile registers the profile by contract address. a later profile of the same contract replaces the former
+func (ns *Indexer) addContractProfile(profile *client.ContractProfile) error {
+	raw, err := types.DecodeAddress(profile.Address)
+	if err != nil {
+		return err
+	}
+	ns.contractProfiles[string(raw)] = profile
+	return nil
+}
+
+// tokenDecimals returns the decimals of a token from the cache, the token record of its profile, the token doc, or the contract.
+// 0 if unknown, which is not cached so that it is queried again
+func (ns *Indexer) tokenDecimals(contractAddress []byte, MinerGRPC *client.AergoClientController) uint8 {
+	if decimals, exist := ns.cache.getTokenDecimals(string(contractAddress)); exist == true {
+		return decimals
+	}
+	profile := ns.contractProfile(contractAddress)
+	if profile != nil && profile.Token != nil {
+		ns.cache.storeTokenDecimals(string(contractAddress), profile.Token.Decimals)
+		return profile.Token.Decimals
+	}
+	if tokenDoc, err := ns.getToken(transaction.EncodeAccount(contractAddress)); err == nil && tokenDoc != nil {
+		ns.cache.storeTokenDecimals(string(contractAddress), tokenDoc.Decimals)
+		return tokenDoc.Decimals
+	}
+	decimals, err := MinerGRPC.QueryDecimals(contractAddress, profile)
+	if err != nil {
+		ns.log.Debug().Err(err).Str("contract", transaction.EncodeAccount(contractAddress)).Msg("Failed to query decimals")
+		return 0
+	}
+	ns.cache.storeTokenDecimals(string(contractAddress), decimals)
+	return decimals
+}
+
+// initContractProfiles seeds the token records of profiles which are not indexed yet, so that supply and holders of indexed tokens are kept
+func (ns *Indexer) initContractProfiles() {
+	for _, profile := range ns.contractProfiles {
+		if profile.Token == nil {
+			continue
+		}
+		if tokenDoc, err := ns.getToken(profile.Address); err != nil || tokenDoc != nil {
+			continue
+		}
+		document := &doc.EsToken{
+			BaseEsType:   &doc.BaseEsType{Id: profile.Address},
+			TxId:         profile.Token.TxId,
+			BlockNo:      profile.Token.BlockNo,
+			Name:         profile.Token.Name,
+			Name_lower:   strings.ToLower(profile.Token.Name),
+			Symbol:       profile.Token.Symbol,
+			Symbol_lower: strings.ToLower(profile.Token.Symbol),
+			Type:         profile.Token.Type,
+			Supply:       "0",
+			SupplyFloat:  float32(0),
+			SupplyPadded: doc.PadAmount(new(big.Int), 0),
+			Decimals:     profile.Token.Decimals,
+		}
+		ns.addToken(document)
+	}
+}
@@ -0,0 +1,84 @@
+package indexer
+
+import (
+	doc "github.com/aergoio/aergo-indexer-2.0/indexer/documents"
+	"github.com/aergoio/aergo-indexer-2.0/indexer/transaction"
+	"github.com/aergoio/aergo-indexer-2.0/types"
+)
+
+// MinerRaftMember records raft membership and leader changes seen through consensus info
+func (ns *Indexer) MinerRaftMember(blockDoc *doc.EsBlock, consensusInfo *types.ConsensusInfo) {
+	if consensusInfo == nil {
+		return
+	}
+	members := transaction.UnmarshalConsensusBps(consensusInfo.GetBps())
+	if len(members) == 0 {
+		return
+	}
+	var leader string
+	if raftInfo, err := transaction.UnmarshalRaftInfo(consensusInfo.GetInfo()); err == nil {
+		leader = raftInfo.Leader
+	}
+
+	prevMembers, prevLeader, exist := ns.cache.getRaftMembers()
+	if exist != true {
+		raftMemberDoc, err := ns.getLastRaftMember()
+		if err != nil {
+			return
+		}
+		if raftMemberDoc == nil {
+			// first seen, record current members as a snapshot
+			ns.addRaftMember(doc.ConvRaftMember(blockDoc, "snapshot", transaction.ConsensusBp{}, leader, members, ""))
+			ns.cache.storeRaftMembers(members, leader)
+			return
+		}
+		for i, peerId := range raftMemberDoc.Members {
+			member := transaction.ConsensusBp{PeerID: peerId}
+			if i < len(raftMemberDoc.MemberNames) {
+				member.Name = raftMemberDoc.MemberNames[i]
+			}
+			prevMembers = append(prevMembers, member)
+		}
+		prevLeader = raftMemberDoc.Leader
+	}
+
+	added, removed := transaction.DiffBps(transaction.ConsensusBpPeerIds(prevMembers), transaction.ConsensusBpPeerIds(members))
+	for _, peerId := range added {
+		member := findRaftMember(members, peerId)
+		ns.addRaftMember(doc.ConvRaftMember(blockDoc, "add", member, leader, members, ns.clusterChangeTx("add", member)))
+		ns.log.Info().Uint64("blockNo", blockDoc.BlockNo).Str("name", member.Name).Str("peerId", peerId).Msg("raft member added")
+	}
+	for _, peerId := range removed {
+		member := findRaftMember(prevMembers, peerId)
+		ns.addRaftMember(doc.ConvRaftMember(blockDoc, "remove", member, leader, members, ns.clusterChangeTx("remove", member)))
+		ns.log.Info().Uint64("blockNo", blockDoc.BlockNo).Str("name", member.Name).Str("peerId", peerId).Msg("raft member removed")
+	}
+	if leader != "" && leader != prevLeader {
+		ns.addRaftMember(doc.ConvRaftMember(blockDoc, "leader", transaction.ConsensusBp{}, leader, members, ""))
+		ns.log.Info().Uint64("blockNo", blockDoc.BlockNo).Str("leader", leader).Msg("raft leader changed")
+	}
+	ns.cache.storeRaftMembers(members, leader)
+}
+
+// clusterChangeTx returns the last changeCluster tx if it targets the member
+func (ns *Indexer) clusterChangeTx(command string, member transaction.ConsensusBp) string {
+	historyDoc, err := ns.getLastClusterChange()
+	if err != nil || historyDoc == nil || historyDoc.ClusterCommand != command {
+		return ""
+	}
+	if (historyDoc.ClusterPeerId != "" && historyDoc.ClusterPeerId == member.PeerID) ||
+		(historyDoc.ClusterName != "" && historyDoc.ClusterName == member.Name) ||
+		(historyDoc.ClusterId != "" && historyDoc.ClusterId == member.RaftID) {
+		return historyDoc.Id
+	}
+	return ""
+}
+
+func findRaftMember(members []transaction.ConsensusBp, peerId string) transaction.ConsensusBp {
+	for _, member := range members {
+		if member.PeerID == peerId {
+			return member
+		}
+	}
+	return transaction.ConsensusBp{PeerID: peerId}
+}
@@ -0,0 +1,102 @@
+package indexer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/aergoio/aergo-indexer-2.0/indexer/client"
+	doc "github.com/aergoio/aergo-indexer-2.0/indexer/documents"
+	"github.com/aergoio/aergo-indexer-2.0/indexer/transaction"
+	"github.com/aergoio/aergo-indexer-2.0/types"
+)
+
+// rewardState is the balance of reward account at a block, which is reused as the previous balance of the next block
+type rewardState struct {
+	blockHash []byte
+	rootHash  []byte
+	account   []byte
+	balance   *big.Int
+}
+
+// MinerReward replaces the reward of block with the balance change of reward account, if nothing else in the block changes it ( sync only, if enabled )
+func (ns *Indexer) MinerReward(block *types.Block, blockDoc *doc.EsBlock, MinerGRPC *client.AergoClientController) {
+	if ns.observeReward != true {
+		return
+	}
+	reward, state, ok := ns.observeRewardOf(block, ns.cache.lastReward, MinerGRPC)
+	ns.cache.lastReward = state
+	if ok == true {
+		blockDoc.SetReward(reward, doc.RewardFromState)
+	}
+}
+
+// observeRewardOf returns the balance change of reward account between the previous block and the block, and the state of the block.
+// the root and the balance of the previous block are taken from prev if it is the previous block, so a block of the same account as the previous one costs a single query
+func (ns *Indexer) observeRewardOf(block *types.Block, prev *rewardState, MinerGRPC *client.AergoClientController) (*big.Int, *rewardState, bool) {
+	state := &rewardState{blockHash: block.Hash, rootHash: block.Header.BlocksRootHash}
+	account := block.Header.GetConsensus()
+	if len(account) == 0 || block.Header.BlockNo == 0 || transaction.IsAccountTouched(block, account) {
+		return nil, state, false
+	}
+	if prev == nil || bytes.Equal(prev.blockHash, block.Header.PrevBlockHash) != true {
+		prevBlock, err := MinerGRPC.GetBlock(block.Header.PrevBlockHash)
+		if err != nil {
+			ns.log.Warn().Err(err).Uint64("blockNo", block.Header.BlockNo).Msg("Failed to get previous block")
+			return nil, state, false
+		}
+		prev = &rewardState{blockHash: block.Header.PrevBlockHash, rootHash: prevBlock.Header.BlocksRootHash}
+	}
+	prevBalance := prev.balance
+	if prevBalance == nil || bytes.Equal(prev.account, account) != true {
+		var err error
+		if prevBalance, err = MinerGRPC.GetBalanceAt(account, prev.rootHash); err != nil {
+			ns.log.Warn().Err(err).Uint64("blockNo", block.Header.BlockNo-1).Msg("Failed to get balance of reward account")
+			return nil, state, false
+		}
+	}
+	balance, err := MinerGRPC.GetBalanceAt(account, block.Header.BlocksRootHash)
+	if err != nil {
+		ns.log.Warn().Err(err).Uint64("blockNo", block.Header.BlockNo).Msg("Failed to get balance of reward account")
+		return nil, state, false
+	}
+	state.account, state.balance = account, balance
+	reward := new(big.Int).Sub(balance, prevBalance)
+	if reward.Sign() < 0 {
+		return nil, state, false
+	}
+	return reward, state, true
+}
+
+// BackfillRewards recomputes the rewards of indexed blocks in the block range
+func (ns *Indexer) BackfillRewards(from uint64, to uint64) error {
+	if err := ns.InitIndex(); err != nil {
+		return err
+	}
+	ns.initChainParams(ns.GetBestBlock() - 1)
+
+	blockQuery := make([]byte, 8)
+	var blocks, observed int
+	var state *rewardState
+	if err := ns.ScrollBlocksInRange(from, to, func(blockDoc *doc.EsBlock) {
+		binary.LittleEndian.PutUint64(blockQuery, blockDoc.BlockNo)
+		block, err := ns.grpcClient.GetBlock(blockQuery)
+		if err != nil {
+			ns.log.Warn().Err(err).Uint64("blockNo", blockDoc.BlockNo).Msg("Failed to get block")
+			return
+		}
+		rewardDoc := doc.ConvBlock(block, "", ns.votingRewardAt(blockDoc.BlockNo), ns.resolveName)
+		var reward *big.Int
+		var ok bool
+		if reward, state, ok = ns.observeRewardOf(block, state, ns.grpcClient); ok == true {
+			rewardDoc.SetReward(reward, doc.RewardFromState)
+			observed++
+		}
+		ns.updateBlockReward(doc.ConvBlockUpReward(rewardDoc))
+		blocks++
+	}); err != nil {
+		return err
+	}
+	ns.log.Info().Int("blocks", blocks).Int("observed", observed).Uint64("from", from).Uint64("to", to).Msg("backfill block rewards")
+	return nil
+}
@@ -0,0 +1,319 @@
+package indexer
+
+import (
+	"time"
+
+	doc "github.com/aergoio/aergo-indexer-2.0/indexer/documents"
+)
+
+// blocks to wait before a marked rollup is rebuilt, so that indexed documents are searchable, and between flushes of an accumulated rollup
+var statsDelay = map[doc.StatsPeriod]uint64{
+	doc.PeriodHour: 10,
+	doc.PeriodDay:  300,
+}
+
+// statsBucket is a rollup document to be rebuilt
+type statsBucket struct {
+	typeName string
+	key      string
+	period   doc.StatsPeriod
+	start    time.Time
+	markedAt uint64 // block number when first marked
+}
+
+func (b *statsBucket) id() string {
+	return b.typeName + "/" + doc.StatsId(b.key, b.period, b.start)
+}
+
+// firstSeenLimit is the number of accounts cached with the block of their first tx
+const firstSeenLimit = 100000
+
+// statsAcc is a rollup of the current period accumulated while syncing, which is flushed to its document every few blocks
+type statsAcc struct {
+	bucket    *statsBucket
+	chain     *doc.ChainStatsAcc
+	token     *doc.TokenStatsAcc
+	flushedAt uint64
+}
+
+// markStats marks the rollups of key in every period containing ts to be rebuilt
+func (ns *Indexer) markStats(typeName string, key string, ts time.Time, blockNo uint64) {
+	for _, period := range doc.StatsPeriods {
+		ns.cache.markStats(&statsBucket{
+			typeName: typeName,
+			key:      key,
+			period:   period,
+			start:    period.Start(ts),
+			markedAt: blockNo,
+		})
+	}
+}
+
+// flushStats rebuilds marked rollups which waited enough from blockNo. every marked rollup is rebuilt if force
+func (ns *Indexer) flushStats(blockNo uint64, force bool) {
+	ns.cache.rangeStats(func(bucket *statsBucket) {
+		if force == false && blockNo < bucket.markedAt+statsDelay[bucket.period] {
+			return
+		}
+		ns.cache.unmarkStats(bucket)
+		ns.dropStatsAcc(bucket)
+		switch bucket.typeName {
+		case "token_stats":
+			ns.rebuildTokenStats(bucket.key, bucket.period, bucket.start)
+		case "chain_stats":
+			ns.rebuildChainStats(bucket.period, bucket.start)
+		}
+	})
+}
+
+// rebuildAfterBulk rebuilds values derived from documents indexed in bulk. token holders and enterprise configs are rebuilt for the tokens and keys indexed, bp stats and fee sponsors are applied in the block ranges indexed
+func (ns *Indexer) rebuildAfterBulk(indexed []blockRange) {
+	var indexNames []string
+	for _, typeName := range []string{"block", "tx", "contract", "token", "token_transfer", "account_tokens", "enterprise_history"} {
+		indexNames = append(indexNames, ns.indexNamePrefix+typeName)
+	}
+	if err := ns.db.Refresh(indexNames...); err != nil {
+		ns.log.Warn().Err(err).Msg("Failed to refresh indices")
+	}
+	ns.recountMarkedTokenHolders()
+	ns.rebuildMarkedEnterpriseConfs()
+	for _, r := range indexed {
+		ns.applyBpStatsInRange(r.from, r.to, false)
+		ns.applyFeeSponsorsInRange(r.from, r.to, false)
+	}
+	ns.cache.resetFirstSeen()
+	ns.flushStats(0, true)
+}
+
+// accumulateChainStats adds the block and its txs to the chain rollups of the periods containing the block, unless the block is added already ( sync only )
+func (ns *Indexer) accumulateChainStats(blockDoc *doc.EsBlock, txDocs []*doc.EsTx) {
+	ns.cache.statsLock.Lock()
+	defer ns.cache.statsLock.Unlock()
+
+	for _, period := range doc.StatsPeriods {
+		acc := ns.loadStatsAcc(&statsBucket{typeName: "chain_stats", key: "chain", period: period, start: period.Start(blockDoc.Timestamp)}, blockDoc.BlockNo, blockDoc.Timestamp)
+		if acc == nil {
+			continue
+		}
+		if acc.chain.AccumulateBlock(blockDoc) != true {
+			continue
+		}
+		for _, txDoc := range txDocs {
+			var newAccounts uint64
+			for _, account := range acc.chain.AddTx(txDoc) {
+				if firstSeen, exist := ns.firstSeenBlock(account, blockDoc.BlockNo); exist == true && firstSeen >= acc.chain.FirstBlock {
+					newAccounts++
+				}
+			}
+			acc.chain.AddNewAccounts(newAccounts)
+		}
+	}
+}
+
+// accumulateTokenStats adds the token transfer to the token rollups of the periods containing the transfer, unless it is added already ( sync only )
+func (ns *Indexer) accumulateTokenStats(tokenTransferDoc *doc.EsTokenTransfer) {
+	ns.cache.statsLock.Lock()
+	defer ns.cache.statsLock.Unlock()
+
+	for _, period := range doc.StatsPeriods {
+		acc := ns.loadStatsAcc(&statsBucket{typeName: "token_stats", key: tokenTransferDoc.TokenAddress, period: period, start: period.Start(tokenTransferDoc.Timestamp)}, tokenTransferDoc.BlockNo, tokenTransferDoc.Timestamp)
+		if acc == nil {
+			continue
+		}
+		acc.token.AccumulateTransfer(tokenTransferDoc)
+	}
+}
+
+// loadStatsAcc returns the accumulated rollup of the bucket. a rollup not accumulated yet starts from the documents indexed before the block of ts, which happens once a period unless rolled back
+// returns nil if the documents can not be read. statsLock must be held
+func (ns *Indexer) loadStatsAcc(bucket *statsBucket, blockNo uint64, ts time.Time) *statsAcc {
+	if acc, exist := ns.cache.statsAcc[bucket.id()]; exist == true {
+		return acc
+	}
+	acc := &statsAcc{bucket: bucket, flushedAt: blockNo}
+	var err error
+	switch bucket.typeName {
+	case "chain_stats":
+		acc.chain, err = ns.chainStatsAccOf(bucket.period, bucket.start, ts)
+	case "token_stats":
+		acc.token, err = ns.tokenStatsAccOf(bucket.key, bucket.period, bucket.start, ts)
+	}
+	if err != nil {
+		return nil
+	}
+	ns.cache.statsAcc[bucket.id()] = acc
+	return acc
+}
+
+// dropStatsAcc drops the accumulated rollup of the bucket, which is rebuilt from documents
+func (ns *Indexer) dropStatsAcc(bucket *statsBucket) {
+	ns.cache.statsLock.Lock()
+	defer ns.cache.statsLock.Unlock()
+	delete(ns.cache.statsAcc, bucket.id())
+}
+
+// resetStatsAcc drops every accumulated rollup, so that they start from the documents left after rollback
+func (ns *Indexer) resetStatsAcc() {
+	ns.cache.statsLock.Lock()
+	defer ns.cache.statsLock.Unlock()
+	ns.cache.statsAcc = make(map[string]*statsAcc)
+}
+
+// flushStatsAcc stores the accumulated rollups which waited enough from the last flush, and evicts the rollups of periods ended before the block ( sync only )
+func (ns *Indexer) flushStatsAcc(blockDoc *doc.EsBlock) {
+	ns.cache.statsLock.Lock()
+	defer ns.cache.statsLock.Unlock()
+
+	for id, acc := range ns.cache.statsAcc {
+		ended := !blockDoc.Timestamp.Before(acc.bucket.period.End(acc.bucket.start))
+		if ended == false && blockDoc.BlockNo < acc.flushedAt+statsDelay[acc.bucket.period] {
+			continue
+		}
+		switch acc.bucket.typeName {
+		case "chain_stats":
+			newContracts, err := ns.cntContractsIn(acc.bucket.start, acc.bucket.period.End(acc.bucket.start))
+			if err != nil {
+				continue
+			}
+			newTokens, err := ns.cntTokensIn(acc.chain.FirstBlock, acc.chain.LastBlock)
+			if err != nil {
+				continue
+			}
+			ns.addChainStats(acc.chain.Stats(newContracts, newTokens))
+		case "token_stats":
+			ns.addTokenStats(acc.token.Stats(ns.tokenDecimalsOf(acc.bucket.key)))
+		}
+		acc.flushedAt = blockDoc.BlockNo
+		if ended == true {
+			delete(ns.cache.statsAcc, id)
+		}
+	}
+}
+
+// tokenDecimalsOf returns the decimals of token doc, or 0 if unknown
+func (ns *Indexer) tokenDecimalsOf(tokenAddr string) uint8 {
+	if tokenDoc, err := ns.getToken(tokenAddr); err == nil && tokenDoc != nil {
+		return tokenDoc.Decimals
+	}
+	return 0
+}
+
+// tokenStatsAccOf accumulates the transfers of a token in the time range of [start, end) of the period
+func (ns *Indexer) tokenStatsAccOf(tokenAddr string, period doc.StatsPeriod, start time.Time, end time.Time) (*doc.TokenStatsAcc, error) {
+	acc := doc.NewTokenStatsAcc(tokenAddr, period, start)
+	if err := ns.ScrollTokenTransferOf(tokenAddr, start, end, func(tokenTransferDoc *doc.EsTokenTransfer) {
+		acc.AddTransfer(tokenTransferDoc)
+	}); err != nil {
+		ns.log.Error().Err(err).Str("token", tokenAddr).Str("func", "tokenStatsAccOf").Msg("error while scroll token transfer")
+		return nil, err
+	}
+	return acc, nil
+}
+
+// rebuildTokenStats rebuilds the rollup of a token in the period from token transfers
+func (ns *Indexer) rebuildTokenStats(tokenAddr string, period doc.StatsPeriod, start time.Time) {
+	var transfers uint64
+	acc := doc.NewTokenStatsAcc(tokenAddr, period, start)
+	if err := ns.ScrollTokenTransferOf(tokenAddr, start, period.End(start), func(tokenTransferDoc *doc.EsTokenTransfer) {
+		acc.AddTransfer(tokenTransferDoc)
+		transfers++
+	}); err != nil {
+		ns.log.Error().Err(err).Str("token", tokenAddr).Str("func", "rebuildTokenStats").Msg("error while scroll token transfer")
+		return
+	}
+	if transfers == 0 {
+		ns.deleteStats("token_stats", doc.StatsId(tokenAddr, period, start))
+		return
+	}
+	ns.addTokenStats(acc.Stats(ns.tokenDecimalsOf(tokenAddr)))
+}
+
+// rollbackTokenStats marks the rollups of token transfers in the block range, which are going to be deleted
+func (ns *Indexer) rollbackTokenStats(fromBlockHeight uint64, toBlockHeight uint64) {
+	if err := ns.ScrollTokenTransferInBlocks(fromBlockHeight, toBlockHeight, func(tokenTransferDoc *doc.EsTokenTransfer) {
+		ns.markStats("token_stats", tokenTransferDoc.TokenAddress, tokenTransferDoc.Timestamp, fromBlockHeight)
+	}); err != nil {
+		ns.log.Error().Err(err).Str("func", "rollbackTokenStats").Msg("error while scroll token transfer")
+	}
+}
+
+// chainStatsAccOf accumulates the blocks and txs in the time range of [start, end) of the period
+func (ns *Indexer) chainStatsAccOf(period doc.StatsPeriod, start time.Time, end time.Time) (*doc.ChainStatsAcc, error) {
+	acc := doc.NewChainStatsAcc(period, start)
+	if err := ns.ScrollBlocksIn(start, end, func(blockDoc *doc.EsBlock) {
+		acc.AddBlock(blockDoc)
+	}); err != nil {
+		ns.log.Error().Err(err).Str("func", "chainStatsAccOf").Msg("error while scroll block")
+		return nil, err
+	}
+
+	var unseen []string
+	if err := ns.ScrollTxIn(start, end, func(txDoc *doc.EsTx) {
+		unseen = append(unseen, acc.AddTx(txDoc)...)
+	}); err != nil {
+		ns.log.Error().Err(err).Str("func", "chainStatsAccOf").Msg("error while scroll tx")
+		return nil, err
+	}
+
+	// accounts which are seen first in the period
+	var newAccounts uint64
+	for _, account := range unseen {
+		if firstSeen, exist := ns.firstSeenBlock(account, acc.LastBlock); exist == true && firstSeen >= acc.FirstBlock {
+			newAccounts++
+		}
+	}
+	acc.AddNewAccounts(newAccounts)
+	return acc, nil
+}
+
+// rebuildChainStats rebuilds the rollup of chain in the period from blocks, txs, contracts and tokens
+func (ns *Indexer) rebuildChainStats(period doc.StatsPeriod, start time.Time) {
+	end := period.End(start)
+	acc, err := ns.chainStatsAccOf(period, start, end)
+	if err != nil {
+		return
+	}
+	if acc.LastBlock == 0 {
+		ns.deleteStats("chain_stats", doc.StatsId("chain", period, start))
+		return
+	}
+
+	newContracts, err := ns.cntContractsIn(start, end)
+	if err != nil {
+		return
+	}
+	newTokens, err := ns.cntTokensIn(acc.FirstBlock, acc.LastBlock)
+	if err != nil {
+		return
+	}
+	ns.addChainStats(acc.Stats(newContracts, newTokens))
+}
+
+// firstSeenBlock returns the block number of the first tx which account sent or received
+// an account without a tx before the block is first seen at the block, since the txs of the block may not be searchable yet
+func (ns *Indexer) firstSeenBlock(account string, blockNo uint64) (firstSeen uint64, exist bool) {
+	if firstSeen, exist = ns.cache.getFirstSeen(account); exist == true {
+		return firstSeen, true
+	}
+	firstSeen = blockNo
+	txDoc, err := ns.getFirstTx(account)
+	if err != nil {
+		return 0, false
+	}
+	if txDoc != nil && txDoc.BlockNo < firstSeen {
+		firstSeen = txDoc.BlockNo
+	}
+	ns.cache.storeFirstSeen(account, firstSeen)
+	return firstSeen, true
+}
+
+// rollbackChainStats marks the rollups of blocks in the block range, which are going to be deleted. accumulated rollups start again from the documents left
+func (ns *Indexer) rollbackChainStats(fromBlockHeight uint64, toBlockHeight uint64) {
+	if err := ns.ScrollBlocksInRange(fromBlockHeight, toBlockHeight, func(blockDoc *doc.EsBlock) {
+		ns.markStats("chain_stats", "chain", blockDoc.Timestamp, fromBlockHeight)
+	}); err != nil {
+		ns.log.Error().Err(err).Str("func", "rollbackChainStats").Msg("error while scroll block")
+	}
+	ns.resetStatsAcc()
+	ns.cache.resetFirstSeen()
+}
@@ -9,6 +9,12 @@ import (
 	tx "github.com/aergoio/aergo-indexer-2.0/indexer/transaction"
 )
 
+// blockRange is a block range of [from, to] indexed in bulk
+type blockRange struct {
+	from uint64
+	to   uint64
+}
+
 // Start setups the indexer
 func (ns *Indexer) Check(startFrom uint64, stopAt uint64) {
 	ns.log.Info().Uint64("from", startFrom).Uint64("to", stopAt).Msg("Start Check...")
@@ -54,6 +60,7 @@ func (ns *Indexer) checkIndex(startFrom uint64, stopAt uint64) {
 
 	prevBlockNo := stopAt + 1
 	missingBlocks := uint64(0)
+	var indexed []blockRange
 	blockNo := startFrom + 1
 	for {
 		block, err = scroll.Next()
@@ -79,6 +86,7 @@ func (ns *Indexer) checkIndex(startFrom uint64, stopAt uint64) {
 		if blockNo < prevBlockNo-1 {
 			missingBlocks = missingBlocks + (prevBlockNo - blockNo - 1)
 			ns.bulk.InsertBlocksInRange(blockNo+1, prevBlockNo-1)
+			indexed = append(indexed, blockRange{blockNo + 1, prevBlockNo - 1})
 		}
 		prevBlockNo = blockNo
 	}
@@ -86,19 +94,25 @@ func (ns *Indexer) checkIndex(startFrom uint64, stopAt uint64) {
 	if blockNo != startFrom && prevBlockNo > startFrom {
 		missingBlocks = missingBlocks + (prevBlockNo - startFrom)
 		ns.bulk.InsertBlocksInRange(startFrom, prevBlockNo-1)
+		indexed = append(indexed, blockRange{startFrom, prevBlockNo - 1})
 	}
 
 	ns.bulk.StopBulkChannel()
+	ns.rebuildAfterBulk(indexed)
 	ns.log.Info().Uint64("missing", missingBlocks).Msg("Done with consistency check")
 }
 
 func (ns *Indexer) fixIndex(startFrom uint64, stopAt uint64) {
 	ns.log.Info().Uint64("startFrom", startFrom).Uint64("stopAt", stopAt).Msg("Fix Block range")
+	// blocks indexed already are applied to bp stats and fee sponsors again after bulk sync
+	ns.applyBpStatsInRange(startFrom, stopAt, true)
+	ns.applyFeeSponsorsInRange(startFrom, stopAt, true)
 	ns.bulk.StartBulkChannel()
 
 	ns.bulk.InsertBlocksInRange(startFrom, stopAt)
 
 	ns.bulk.StopBulkChannel()
+	ns.rebuildAfterBulk([]blockRange{{startFrom, stopAt}})
 	ns.log.Info().Msg("Done with fix")
 }
 
@@ -12,7 +12,7 @@ import (
 // Start setups the indexer
 func (ns *Indexer) OnSync() {
 	// Get ready to start
-	ns.log.Info().Uint64("height", ns.lastHeight+1).Msg("Start Onsync...")
+	ns.log.Info().Uint64("height", ns.getLastHeight()+1).Msg("Start Onsync...")
 
 	ns.cache.registerVariables()
 	// Sync stream
@@ -28,17 +28,17 @@ func (ns *Indexer) startStream() {
 
 	SyncBlock := func(block *types.Block) error {
 		newHeight := block.Header.BlockNo
-		if newHeight < ns.lastHeight { // Rewound 1 or more blocks
+		if newHeight < ns.getLastHeight() { // Rewound 1 or more blocks
 			// This needs to be syncronous, otherwise it may
 			// delete the block we are just about to add
-			ns.DeleteBlocksInRange(newHeight+1, ns.lastHeight)
-			ns.lastHeight = newHeight
+			ns.DeleteBlocksInRange(newHeight+1, ns.getLastHeight())
+			ns.setLastHeight(newHeight)
 			return nil
 		}
 
 		// indexing
-		if newHeight > ns.lastHeight+1 {
-			for H := ns.lastHeight + 1; H < newHeight; H++ {
+		if newHeight > ns.getLastHeight()+1 {
+			for H := ns.getLastHeight() + 1; H < newHeight; H++ {
 				MChannel <- BlockInfo{BlockType_Sync, H}
 				fmt.Println(">>> New Block :", H)
 			}
@@ -51,12 +51,12 @@ func (ns *Indexer) startStream() {
 				ns.sleepStream(newHeight)
 			} else {
 				MChannel <- BlockInfo{BlockType_Sync, newHeight}
-				ns.lastHeight = newHeight
+				ns.setLastHeight(newHeight)
 				fmt.Println(">>> New Block :", newHeight)
 			}
 		} else {
 			MChannel <- BlockInfo{BlockType_Sync, newHeight}
-			ns.lastHeight = newHeight
+			ns.setLastHeight(newHeight)
 			fmt.Println(">>> New Block :", newHeight)
 		}
 		return nil
@@ -121,8 +121,8 @@ func (ns *Indexer) sleepStream(BlockNo uint64) {
 		BestBlockNo, err := ns.GetBestBlockFromDb()
 		if err == nil {
 			if CBlockNo >= BestBlockNo {
-				ns.lastHeight = BestBlockNo
-				ns.log.Info().Msgf("Wake up stream %d", ns.lastHeight)
+				ns.setLastHeight(BestBlockNo)
+				ns.log.Info().Msgf("Wake up stream %d", ns.getLastHeight())
 				return_tag = true
 				return
 			} else {
@@ -142,12 +142,32 @@ func (ns *Indexer) DeleteBlocksInRange(fromBlockHeight uint64, toBlockHeight uin
 	}
 
 	ns.log.Info().Msg(fmt.Sprintf("Rolling back %d blocks [%d..%d]", (1 + toBlockHeight - fromBlockHeight), fromBlockHeight, toBlockHeight))
+	ns.rollbackChainStats(fromBlockHeight, toBlockHeight)
+	ns.rollbackBpStats(fromBlockHeight, toBlockHeight)
 	ns.deleteTypeByQuery("block", db.IntegerRangeQuery{Field: "no", Min: fromBlockHeight, Max: toBlockHeight})
+	ns.rollbackFeeSponsors(fromBlockHeight, toBlockHeight)
 	ns.deleteTypeByQuery("tx", db.IntegerRangeQuery{Field: "blockno", Min: fromBlockHeight, Max: toBlockHeight})
 	ns.deleteTypeByQuery("name", db.IntegerRangeQuery{Field: "blockno", Min: fromBlockHeight, Max: toBlockHeight})
+	ns.rollbackEventIndices(fromBlockHeight, toBlockHeight)
+	ns.rollbackTokenStats(fromBlockHeight, toBlockHeight)
 	ns.deleteTypeByQuery("token_transfer", db.IntegerRangeQuery{Field: "blockno", Min: fromBlockHeight, Max: toBlockHeight})
 	ns.deleteTypeByQuery("token", db.IntegerRangeQuery{Field: "blockno", Min: fromBlockHeight, Max: toBlockHeight})
-	ns.deleteTypeByQuery("nft", db.IntegerRangeQuery{Field: "blockno", Min: fromBlockHeight, Max: toBlockHeight})
+	ns.rollbackTokenHolders()
+	ns.rollbackNFT(fromBlockHeight, toBlockHeight)
+	ns.deleteTypeByQuery("bp_votes", db.IntegerRangeQuery{Field: "blockno", Min: fromBlockHeight, Max: toBlockHeight})
+	ns.deleteTypeByQuery("bp_change", db.IntegerRangeQuery{Field: "blockno", Min: fromBlockHeight, Max: toBlockHeight})
+	ns.cache.resetBps()
+	ns.cache.rollbackBpSchedules(fromBlockHeight)
+	ns.rollbackNameState(fromBlockHeight)
+	ns.deleteTypeByQuery("enterprise_history", db.IntegerRangeQuery{Field: "blockno", Min: fromBlockHeight, Max: toBlockHeight})
+	ns.rollbackEnterpriseConf(fromBlockHeight)
+	ns.deleteTypeByQuery("raft_member", db.IntegerRangeQuery{Field: "blockno", Min: fromBlockHeight, Max: toBlockHeight})
+	ns.cache.resetRaftMembers()
+	ns.deleteTypeByQuery("contract_abi", db.IntegerRangeQuery{Field: "blockno", Min: fromBlockHeight, Max: toBlockHeight})
+	ns.cache.resetContractAbi()
+	ns.deleteTypeByQuery("chain_param", db.IntegerRangeQuery{Field: "blockno", Min: fromBlockHeight, Max: toBlockHeight})
+	ns.cache.rollbackChainParams(fromBlockHeight)
+	ns.rollbackContractVersion(fromBlockHeight, toBlockHeight)
 }
 
 func (ns *Indexer) deleteTypeByQuery(typeName string, rangeQuery db.IntegerRangeQuery) {
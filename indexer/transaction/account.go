@@ -63,7 +63,16 @@ func EncodeAccount(account []byte) string {
 	return types.EncodeAddress(account)
 }
 
-func EncodeAndResolveAccount(account []byte, blockNo uint64) string {
+// NameResolver resolves a name into an address as of a block height
+type NameResolver func(name string, blockNo uint64) (address string, ok bool)
+
+// EncodeAndResolveAccount encodes account and resolves it with resolver when it is a name. a nil resolver leaves names as they are
+func EncodeAndResolveAccount(account []byte, blockNo uint64, resolver NameResolver) string {
 	var encoded = EncodeAccount(account)
+	if resolver != nil && IsAlias(encoded) {
+		if address, ok := resolver(encoded, blockNo); ok {
+			return address
+		}
+	}
 	return encoded
 }
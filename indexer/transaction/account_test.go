@@ -21,3 +21,23 @@ func TestAccountEncodeDecode(t *testing.T) {
 	fn_test("AmMjrVRQbrgDYChnWgyYL6gfneGT5ui6DwuvUXp8nTdUz8wwstAq")
 	fn_test("AmPERyyJgoDLm6GBTEaVSwennQeyQGDSFycGUuSMsvupL1qKfTFo")
 }
+
+func TestEncodeAndResolveAccount(t *testing.T) {
+	resolver := func(name string, blockNo uint64) (string, bool) {
+		if name == "testnametest" && blockNo >= 100 {
+			return "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA", true
+		}
+		return "", false
+	}
+	fn_test := func(account string, blockNo uint64, expect string) {
+		require.Equal(t, expect, EncodeAndResolveAccount(DecodeAccount(account), blockNo, resolver))
+	}
+
+	fn_test("testnametest", 99, "testnametest")
+	fn_test("testnametest", 100, "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA")
+	fn_test("aergo.system", 100, "aergo.system")
+	fn_test("AmMK3LZiR1oEf66xzXir7mA5SUVVHSinWUYmh5FwueoVmciH3CuJ", 100, "AmMK3LZiR1oEf66xzXir7mA5SUVVHSinWUYmh5FwueoVmciH3CuJ")
+
+	// without resolver, names are kept
+	require.Equal(t, "testnametest", EncodeAndResolveAccount(DecodeAccount("testnametest"), 100, nil))
+}
@@ -0,0 +1,220 @@
+package transaction
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aergoio/aergo-indexer-2.0/types"
+)
+
+const (
+	ArgString  = "string"
+	ArgNumber  = "number"
+	ArgBignum  = "bignum"
+	ArgBool    = "bool"
+	ArgAddress = "address"
+	ArgObject  = "object"
+	ArgArray   = "array"
+	ArgNull    = "null"
+
+	// VarArgs is the abi argument name of variable arguments
+	VarArgs = "..."
+
+	// MaxEventArgs is the number of leading event arguments which are indexed by position
+	MaxEventArgs = 10
+	// MaxEventArgValues is the number of values indexed per event argument, after flattening
+	MaxEventArgValues = 32
+
+	encodedAddressLength = 52
+)
+
+// Arg is a decoded argument of contract call or event
+type Arg struct {
+	Idx   uint64  `json:"idx" db:"idx"`
+	Name  string  `json:"name" db:"name"` // argument name from abi, if known
+	Type  string  `json:"type" db:"type"`
+	Value string  `json:"value" db:"value"` // string representation of argument
+	Num   float64 `json:"num" db:"num"`     // number or bignum, useful for range queries
+}
+
+// PayloadArgs is an unmarshalled contract call payload with arguments of any json type
+type PayloadArgs struct {
+	Name string        `json:"Name"`
+	Args []interface{} `json:"Args"`
+}
+
+// UnmarshalPayloadArgs converts payload bytes into a struct using json. numbers are kept as json.Number
+func UnmarshalPayloadArgs(payloadSource []byte) (*PayloadArgs, error) {
+	payload := new(PayloadArgs)
+	decoder := json.NewDecoder(bytes.NewReader(payloadSource))
+	decoder.UseNumber()
+	if err := decoder.Decode(payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// UnmarshalJsonArgs converts json array of arguments. numbers are kept as json.Number
+func UnmarshalJsonArgs(jsonArgs string) ([]interface{}, error) {
+	var args []interface{}
+	decoder := json.NewDecoder(bytes.NewReader([]byte(jsonArgs)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&args); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+// DecodeArgs converts arguments into typed args. names are argument names from abi, can be nil
+func DecodeArgs(args []interface{}, names []string) []Arg {
+	decoded := make([]Arg, 0, len(args))
+	for i, arg := range args {
+		decodedArg := DecodeArg(arg)
+		decodedArg.Idx = uint64(i)
+		if i < len(names) {
+			decodedArg.Name = names[i]
+		} else if len(names) > 0 && names[len(names)-1] == VarArgs {
+			decodedArg.Name = VarArgs
+		}
+		decoded = append(decoded, decodedArg)
+	}
+	return decoded
+}
+
+// DecodeArg converts an argument into typed arg
+func DecodeArg(arg interface{}) Arg {
+	switch data := arg.(type) {
+	case nil:
+		return Arg{Type: ArgNull}
+	case bool:
+		return Arg{Type: ArgBool, Value: strconv.FormatBool(data)}
+	case json.Number:
+		num, _ := data.Float64()
+		return Arg{Type: ArgNumber, Value: data.String(), Num: num}
+	case float64:
+		return Arg{Type: ArgNumber, Value: strconv.FormatFloat(data, 'f', -1, 64), Num: data}
+	case string:
+		if IsAddress(data) {
+			return Arg{Type: ArgAddress, Value: data}
+		}
+		return Arg{Type: ArgString, Value: data}
+	case map[string]interface{}:
+		if bignum, ok := ConvertBignumJson(data); ok {
+			num, _ := new(big.Float).SetInt(bignum).Float64()
+			return Arg{Type: ArgBignum, Value: bignum.String(), Num: num}
+		}
+		encoded, _ := json.Marshal(data)
+		return Arg{Type: ArgObject, Value: string(encoded)}
+	case []interface{}:
+		encoded, _ := json.Marshal(data)
+		return Arg{Type: ArgArray, Value: string(encoded)}
+	}
+	encoded, _ := json.Marshal(arg)
+	return Arg{Type: ArgObject, Value: string(encoded)}
+}
+
+// EventArg is the values of an event argument by type. nested objects and arrays are flattened into their leaf values
+type EventArg struct {
+	Str  []string  `json:"str,omitempty" db:"str"`   // strings, bools and exact numbers
+	Num  []float64 `json:"num,omitempty" db:"num"`   // numbers and bignums, for range queries
+	Addr []string  `json:"addr,omitempty" db:"addr"` // account addresses
+}
+
+// FlattenEventArgs converts json array of event arguments into typed values by position. returns nil if the args are not an array
+func FlattenEventArgs(jsonArgs string) map[string]*EventArg {
+	args, err := UnmarshalJsonArgs(jsonArgs)
+	if err != nil || len(args) == 0 {
+		return nil
+	}
+	flattened := make(map[string]*EventArg)
+	for i, arg := range args {
+		if i >= MaxEventArgs {
+			break
+		}
+		eventArg := new(EventArg)
+		eventArg.add(arg)
+		if len(eventArg.Str) > 0 || len(eventArg.Num) > 0 || len(eventArg.Addr) > 0 {
+			flattened[strconv.Itoa(i)] = eventArg
+		}
+	}
+	if len(flattened) == 0 {
+		return nil
+	}
+	return flattened
+}
+
+func (a *EventArg) add(arg interface{}) {
+	if len(a.Str)+len(a.Num)+len(a.Addr) >= MaxEventArgValues {
+		return
+	}
+	switch data := arg.(type) {
+	case bool:
+		a.Str = append(a.Str, strconv.FormatBool(data))
+	case json.Number:
+		num, _ := data.Float64()
+		a.Str = append(a.Str, data.String())
+		a.Num = append(a.Num, num)
+	case string:
+		if IsAddress(data) {
+			a.Addr = append(a.Addr, data)
+		} else {
+			a.Str = append(a.Str, data)
+		}
+	case map[string]interface{}:
+		if bignum, ok := ConvertBignumJson(data); ok {
+			num, _ := new(big.Float).SetInt(bignum).Float64()
+			a.Str = append(a.Str, bignum.String())
+			a.Num = append(a.Num, num)
+			return
+		}
+		keys := make([]string, 0, len(data))
+		for key := range data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			a.add(data[key])
+		}
+	case []interface{}:
+		for _, item := range data {
+			a.add(item)
+		}
+	}
+}
+
+// ArgAtPath returns the argument value at the path, which is the position of argument followed by keys of objects or indices of arrays, e.g. "1.amount" or "2.0"
+func ArgAtPath(args []interface{}, path string) (interface{}, bool) {
+	var value interface{} = args
+	for _, key := range strings.Split(path, ".") {
+		switch data := value.(type) {
+		case []interface{}:
+			idx, err := strconv.Atoi(key)
+			if err != nil || idx < 0 || idx >= len(data) {
+				return nil, false
+			}
+			value = data[idx]
+		case map[string]interface{}:
+			item, exist := data[key]
+			if exist != true {
+				return nil, false
+			}
+			value = item
+		default:
+			return nil, false
+		}
+	}
+	return value, value != nil
+}
+
+// IsAddress checks if the string is a base58check encoded account address
+func IsAddress(address string) bool {
+	if len(address) != encodedAddressLength {
+		return false
+	}
+	_, err := types.DecodeAddress(address)
+	return err == nil
+}
@@ -0,0 +1,106 @@
+package transaction
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalPayloadArgs(t *testing.T) {
+	fn_test := func(payload string, names []string, expectName string, expectArgs []Arg) {
+		decoded, err := UnmarshalPayloadArgs([]byte(payload))
+		require.NoError(t, err)
+		require.Equal(t, expectName, decoded.Name)
+		require.Equal(t, expectArgs, DecodeArgs(decoded.Args, names))
+	}
+
+	fn_test(`{"Name":"transfer","Args":["AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA",{"_bignum":"1000000000000000000"},"memo"]}`,
+		[]string{"to", "amount", VarArgs}, "transfer", []Arg{
+			{Idx: 0, Name: "to", Type: ArgAddress, Value: "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA"},
+			{Idx: 1, Name: "amount", Type: ArgBignum, Value: "1000000000000000000", Num: 1e18},
+			{Idx: 2, Name: VarArgs, Type: ArgString, Value: "memo"},
+		})
+
+	fn_test(`{"Name":"set","Args":[12345678901234567890,true,null,[1,2],{"a":"b"},1.5]}`,
+		nil, "set", []Arg{
+			{Idx: 0, Type: ArgNumber, Value: "12345678901234567890", Num: 12345678901234567890},
+			{Idx: 1, Type: ArgBool, Value: "true"},
+			{Idx: 2, Type: ArgNull},
+			{Idx: 3, Type: ArgArray, Value: "[1,2]"},
+			{Idx: 4, Type: ArgObject, Value: `{"a":"b"}`},
+			{Idx: 5, Type: ArgNumber, Value: "1.5", Num: 1.5},
+		})
+
+	fn_test(`{"Name":"vote","Args":["a","b","c"]}`, []string{"x", "...", "z"}, "vote", []Arg{
+		{Idx: 0, Name: "x", Type: ArgString, Value: "a"},
+		{Idx: 1, Name: "...", Type: ArgString, Value: "b"},
+		{Idx: 2, Name: "z", Type: ArgString, Value: "c"},
+	})
+}
+
+func TestIsAddress(t *testing.T) {
+	require.True(t, IsAddress("AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA"))
+	require.False(t, IsAddress("AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPB"))
+	require.False(t, IsAddress("aergo.system"))
+	require.False(t, IsAddress(""))
+}
+
+func TestFlattenEventArgs(t *testing.T) {
+	fn_test := func(jsonArgs string, expect map[string]*EventArg) {
+		require.Equal(t, expect, FlattenEventArgs(jsonArgs))
+	}
+
+	fn_test(`["AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA",{"_bignum":"1000000000000000000"},"memo",12,true,null]`, map[string]*EventArg{
+		"0": {Addr: []string{"AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA"}},
+		"1": {Str: []string{"1000000000000000000"}, Num: []float64{1e18}},
+		"2": {Str: []string{"memo"}},
+		"3": {Str: []string{"12"}, Num: []float64{12}},
+		"4": {Str: []string{"true"}},
+	})
+
+	// nested objects and arrays are flattened in the order of keys
+	fn_test(`[{"to":"AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA","amount":{"_bignum":"5"},"tags":["a",["b"]]}]`, map[string]*EventArg{
+		"0": {Str: []string{"5", "a", "b"}, Num: []float64{5}, Addr: []string{"AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA"}},
+	})
+
+	// positions and values are bounded
+	fn_test(`[0,1,2,3,4,5,6,7,8,9,10,11]`, map[string]*EventArg{
+		"0": {Str: []string{"0"}, Num: []float64{0}},
+		"1": {Str: []string{"1"}, Num: []float64{1}},
+		"2": {Str: []string{"2"}, Num: []float64{2}},
+		"3": {Str: []string{"3"}, Num: []float64{3}},
+		"4": {Str: []string{"4"}, Num: []float64{4}},
+		"5": {Str: []string{"5"}, Num: []float64{5}},
+		"6": {Str: []string{"6"}, Num: []float64{6}},
+		"7": {Str: []string{"7"}, Num: []float64{7}},
+		"8": {Str: []string{"8"}, Num: []float64{8}},
+		"9": {Str: []string{"9"}, Num: []float64{9}},
+	})
+	flattened := FlattenEventArgs(`[["a","b","c","d","e","f","g","h","i","j","k","l","m","n","o","p","q","r","s","t","u","v","w","x","y","z","0","1","2","3","4","5","6","7"]]`)
+	require.Len(t, flattened["0"].Str, MaxEventArgValues)
+
+	fn_test(`[]`, nil)
+	fn_test(`[null]`, nil)
+	fn_test(`invalid`, nil)
+}
+
+func TestArgAtPath(t *testing.T) {
+	args, err := UnmarshalJsonArgs(`["AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA",{"amount":{"_bignum":"100"},"path":["a","b"]},null]`)
+	require.NoError(t, err)
+
+	fn_test := func(path string, expect interface{}, expectOk bool) {
+		value, ok := ArgAtPath(args, path)
+		require.Equal(t, expectOk, ok)
+		require.Equal(t, expect, value)
+	}
+
+	fn_test("0", "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA", true)
+	fn_test("1.amount", map[string]interface{}{"_bignum": "100"}, true)
+	fn_test("1.path.1", "b", true)
+	fn_test("1.path.2", nil, false)
+	fn_test("1.fee", nil, false)
+	fn_test("0.x", nil, false)
+	fn_test("2", nil, false)
+	fn_test("3", nil, false)
+	fn_test("", nil, false)
+}
@@ -1,6 +1,9 @@
 package transaction
 
 import (
+	"bytes"
+
+	"github.com/aergoio/aergo-indexer-2.0/types"
 	"github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/libp2p/go-libp2p-core/peer"
 )
@@ -17,3 +20,16 @@ func MakePeerId(pubKey []byte) string {
 	peerId := peer.IDB58Encode(Id)
 	return peerId
 }
+
+// IsAccountTouched checks if txs or fees of block may change the balance of account, besides the block reward
+func IsAccountTouched(block *types.Block, account []byte) bool {
+	if bytes.Equal(block.GetHeader().GetCoinbaseAccount(), account) {
+		return true
+	}
+	for _, tx := range block.GetBody().GetTxs() {
+		if bytes.Equal(tx.GetBody().GetAccount(), account) || bytes.Equal(tx.GetBody().GetRecipient(), account) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package transaction
+
+import (
+	"testing"
+
+	"github.com/aergoio/aergo-indexer-2.0/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsAccountTouched(t *testing.T) {
+	fn_test := func(block *types.Block, account []byte, expect bool) {
+		require.Equal(t, expect, IsAccountTouched(block, account))
+	}
+
+	account := []byte{1, 2, 3}
+	other := []byte{4, 5, 6}
+	fn_test(&types.Block{Header: &types.BlockHeader{}, Body: &types.BlockBody{}}, account, false)
+	fn_test(&types.Block{Header: &types.BlockHeader{CoinbaseAccount: account}, Body: &types.BlockBody{}}, account, true)
+	fn_test(&types.Block{Header: &types.BlockHeader{CoinbaseAccount: other}, Body: &types.BlockBody{
+		Txs: []*types.Tx{{Body: &types.TxBody{Account: other, Recipient: other}}},
+	}}, account, false)
+	fn_test(&types.Block{Header: &types.BlockHeader{}, Body: &types.BlockBody{
+		Txs: []*types.Tx{{Body: &types.TxBody{Account: other, Recipient: other}}, {Body: &types.TxBody{Account: account}}},
+	}}, account, true)
+	fn_test(&types.Block{Header: &types.BlockHeader{}, Body: &types.BlockBody{
+		Txs: []*types.Tx{{Body: &types.TxBody{Account: other, Recipient: account}}},
+	}}, account, true)
+}
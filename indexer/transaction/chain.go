@@ -0,0 +1,60 @@
+package transaction
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ChainConfig is the consensus configuration of the chain which is not served by the node, such as the heights of hardforks
+type ChainConfig struct {
+	hardforks []hardfork // sorted by block number
+}
+
+type hardfork struct {
+	version int32
+	blockNo uint64
+}
+
+// NewChainConfig returns a chain config without hardforks
+func NewChainConfig() *ChainConfig {
+	return &ChainConfig{}
+}
+
+// SetHardfork sets the block number where the hardfork version is activated
+func (c *ChainConfig) SetHardfork(version int32, blockNo uint64) {
+	c.hardforks = append(c.hardforks, hardfork{version: version, blockNo: blockNo})
+	sort.SliceStable(c.hardforks, func(i, j int) bool {
+		return c.hardforks[i].blockNo < c.hardforks[j].blockNo
+	})
+}
+
+// HardforkAt returns the hardfork version of block. version 1 is the genesis version.
+// returns false if the heights of hardforks are not set, since the version is unknown then
+func (c *ChainConfig) HardforkAt(blockNo uint64) (int32, bool) {
+	var version int32 = 1
+	for _, hf := range c.hardforks {
+		if hf.blockNo > blockNo {
+			break
+		}
+		version = hf.version
+	}
+	return version, len(c.hardforks) > 0
+}
+
+// ParseHardfork parses the hardfork height in the form of <version>=<block number>
+func ParseHardfork(spec string) (version int32, blockNo uint64, err error) {
+	versionStr, blockStr, ok := strings.Cut(spec, "=")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid hardfork: %s", spec)
+	}
+	v, err := strconv.ParseInt(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(versionStr)), "v"), 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid hardfork version: %s", spec)
+	}
+	if blockNo, err = strconv.ParseUint(strings.TrimSpace(blockStr), 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("invalid hardfork block number: %s", spec)
+	}
+	return int32(v), blockNo, nil
+}
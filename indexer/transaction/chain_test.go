@@ -0,0 +1,39 @@
+package transaction
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainConfigHardforkAt(t *testing.T) {
+	config := NewChainConfig()
+	version, ok := config.HardforkAt(9000)
+	require.Equal(t, int32(1), version)
+	require.False(t, ok)
+
+	config.SetHardfork(3, 3000)
+	config.SetHardfork(2, 2000)
+	fn_test := func(blockNo uint64, expect int32) {
+		version, ok := config.HardforkAt(blockNo)
+		require.True(t, ok)
+		require.Equal(t, expect, version)
+	}
+	fn_test(0, 1)
+	fn_test(2000, 2)
+	fn_test(2999, 2)
+	fn_test(3000, 3)
+	fn_test(9000, 3)
+}
+
+func TestParseHardfork(t *testing.T) {
+	version, blockNo, err := ParseHardfork("V2=19009900")
+	require.NoError(t, err)
+	require.Equal(t, int32(2), version)
+	require.Equal(t, uint64(19009900), blockNo)
+
+	_, _, err = ParseHardfork("v2")
+	require.Error(t, err)
+	_, _, err = ParseHardfork("vx=100")
+	require.Error(t, err)
+}
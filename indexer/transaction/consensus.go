@@ -0,0 +1,153 @@
+package transaction
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mr-tron/base58"
+)
+
+const (
+	VoteBP = "voteBP"
+
+	ConsensusRaft = "raft"
+)
+
+// ConsensusBp is a block producer entry of consensus info
+type ConsensusBp struct {
+	Index  string `json:"Index"`
+	Name   string `json:"Name"`
+	RaftID string `json:"RaftID"`
+	PeerID string `json:"PeerID"`
+	Addr   string `json:"Addr"`
+}
+
+// UnmarshalConsensusBps parses the bp list of consensus info. entries which are not json are treated as peer id
+func UnmarshalConsensusBps(bps []string) []ConsensusBp {
+	parsed := make([]ConsensusBp, 0, len(bps))
+	for _, bp := range bps {
+		var entry ConsensusBp
+		if err := json.Unmarshal([]byte(bp), &entry); err != nil {
+			entry = ConsensusBp{PeerID: strings.TrimSpace(bp)}
+		}
+		if entry.PeerID == "" {
+			continue
+		}
+		parsed = append(parsed, entry)
+	}
+	return parsed
+}
+
+// RaftInfo is the raft status of consensus info
+type RaftInfo struct {
+	Leader string `json:"Leader"`
+	Total  uint32 `json:"Total"`
+	Name   string `json:"Name"`
+	RaftId string `json:"RaftId"`
+}
+
+// UnmarshalRaftInfo parses the info of raft consensus
+func UnmarshalRaftInfo(info string) (*RaftInfo, error) {
+	raftInfo := new(RaftInfo)
+	if err := json.Unmarshal([]byte(info), raftInfo); err != nil {
+		return nil, err
+	}
+	return raftInfo, nil
+}
+
+// ConsensusBpPeerIds returns the sorted peer ids of block producers
+func ConsensusBpPeerIds(bps []ConsensusBp) []string {
+	peerIds := make([]string, 0, len(bps))
+	for _, bp := range bps {
+		peerIds = append(peerIds, bp.PeerID)
+	}
+	sort.Strings(peerIds)
+	return peerIds
+}
+
+// ConsensusBpSchedule returns the peer ids of block producers in the order of slots. nil if the bps have no index ( raft )
+func ConsensusBpSchedule(bps []ConsensusBp) []string {
+	ordered := make([]ConsensusBp, 0, len(bps))
+	for _, bp := range bps {
+		if _, err := strconv.Atoi(bp.Index); err != nil {
+			return nil
+		}
+		ordered = append(ordered, bp)
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, _ := strconv.Atoi(ordered[i].Index)
+		b, _ := strconv.Atoi(ordered[j].Index)
+		return a < b
+	})
+	schedule := make([]string, 0, len(ordered))
+	for _, bp := range ordered {
+		schedule = append(schedule, bp.PeerID)
+	}
+	if len(schedule) == 0 {
+		return nil
+	}
+	return schedule
+}
+
+// MissedSlots returns the number of slots each block producer missed between two consecutive blocks.
+// a slot of interval is assigned to schedule[slot % len(schedule)], where slot is the timestamp divided by interval
+func MissedSlots(schedule []string, prevTs, ts int64, interval time.Duration) map[string]uint64 {
+	if len(schedule) == 0 || interval <= 0 {
+		return nil
+	}
+	prevSlot, slot := prevTs/int64(interval), ts/int64(interval)
+	if slot-prevSlot <= 1 {
+		return nil
+	}
+	n := int64(len(schedule))
+	skipped := slot - prevSlot - 1
+	missed := make(map[string]uint64)
+	for i := int64(0); i < n && i < skipped; i++ {
+		// slots of the same bp repeat every n slots
+		count := (skipped-i-1)/n + 1
+		missed[schedule[(prevSlot+1+i)%n]] += uint64(count)
+	}
+	return missed
+}
+
+// DiffBps returns the peer ids added to and removed from the previous bp set
+func DiffBps(prev, curr []string) (added, removed []string) {
+	prevSet := make(map[string]bool, len(prev))
+	for _, bp := range prev {
+		prevSet[bp] = true
+	}
+	currSet := make(map[string]bool, len(curr))
+	for _, bp := range curr {
+		currSet[bp] = true
+		if !prevSet[bp] {
+			added = append(added, bp)
+		}
+	}
+	for _, bp := range prev {
+		if !currSet[bp] {
+			removed = append(removed, bp)
+		}
+	}
+	return added, removed
+}
+
+// SameSchedule returns true if both schedules assign slots to the same bps in the same order
+func SameSchedule(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// EncodeCandidate encodes a raw bp candidate of vote list into peer id
+func EncodeCandidate(candidate []byte) string {
+	return base58.Encode(candidate)
+}
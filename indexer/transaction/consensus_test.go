@@ -0,0 +1,96 @@
+package transaction
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalConsensusBps(t *testing.T) {
+	fn_test := func(bps []string, expectPeerIds []string) {
+		peerIds := ConsensusBpPeerIds(UnmarshalConsensusBps(bps))
+		require.Equal(t, expectPeerIds, peerIds)
+	}
+
+	// dpos
+	fn_test([]string{
+		`{"Index":"1","PeerID":"16Uiu2HAmGiJ2QgVAWHMUtzLKKNM5eFUJ3Ds3FN7nYJq1mHN5ZPj9"}`,
+		`{"Index":"0","PeerID":"16Uiu2HAkvJTHFuJXxr15rFEHsJWnyn1QvGatW2E9ED9Mvy4HWjVF"}`,
+	}, []string{"16Uiu2HAkvJTHFuJXxr15rFEHsJWnyn1QvGatW2E9ED9Mvy4HWjVF", "16Uiu2HAmGiJ2QgVAWHMUtzLKKNM5eFUJ3Ds3FN7nYJq1mHN5ZPj9"})
+
+	// raft
+	fn_test([]string{
+		`{"Name":"aergo1","RaftID":"aebe0b6ae1d8a39b","PeerID":"16Uiu2HAmGiJ2QgVAWHMUtzLKKNM5eFUJ3Ds3FN7nYJq1mHN5ZPj9","Addr":"/ip4/127.0.0.1/tcp/11001"}`,
+	}, []string{"16Uiu2HAmGiJ2QgVAWHMUtzLKKNM5eFUJ3Ds3FN7nYJq1mHN5ZPj9"})
+
+	// plain peer id
+	fn_test([]string{"16Uiu2HAmGiJ2QgVAWHMUtzLKKNM5eFUJ3Ds3FN7nYJq1mHN5ZPj9", ""}, []string{"16Uiu2HAmGiJ2QgVAWHMUtzLKKNM5eFUJ3Ds3FN7nYJq1mHN5ZPj9"})
+}
+
+func TestDiffBps(t *testing.T) {
+	fn_test := func(prev, curr []string, expectAdded, expectRemoved []string) {
+		added, removed := DiffBps(prev, curr)
+		require.Equal(t, expectAdded, added)
+		require.Equal(t, expectRemoved, removed)
+	}
+
+	fn_test([]string{"a", "b", "c"}, []string{"a", "b", "c"}, nil, nil)
+	fn_test([]string{"a", "b", "c"}, []string{"a", "c", "d"}, []string{"d"}, []string{"b"})
+	fn_test(nil, []string{"a"}, []string{"a"}, nil)
+}
+
+func TestSameSchedule(t *testing.T) {
+	require.True(t, SameSchedule([]string{"a", "b"}, []string{"a", "b"}))
+	require.True(t, SameSchedule(nil, []string{}))
+	require.False(t, SameSchedule([]string{"a", "b"}, []string{"b", "a"}))
+	require.False(t, SameSchedule(nil, []string{"a"}))
+}
+
+func TestUnmarshalRaftInfo(t *testing.T) {
+	raftInfo, err := UnmarshalRaftInfo(`{"Leader":"aergo2","Total":3,"Name":"aergo1","RaftId":"aebe0b6ae1d8a39b","Status":{"id":"aebe0b6ae1d8a39b"}}`)
+	require.NoError(t, err)
+	require.Equal(t, "aergo2", raftInfo.Leader)
+	require.Equal(t, uint32(3), raftInfo.Total)
+
+	_, err = UnmarshalRaftInfo("")
+	require.Error(t, err)
+}
+
+func TestConsensusBpSchedule(t *testing.T) {
+	fn_test := func(bps []string, expect []string) {
+		require.Equal(t, expect, ConsensusBpSchedule(UnmarshalConsensusBps(bps)))
+	}
+
+	// dpos
+	fn_test([]string{
+		`{"Index":"1","PeerID":"bp1"}`,
+		`{"Index":"10","PeerID":"bp10"}`,
+		`{"Index":"0","PeerID":"bp0"}`,
+		`{"Index":"2","PeerID":"bp2"}`,
+	}, []string{"bp0", "bp1", "bp2", "bp10"})
+
+	// raft
+	fn_test([]string{
+		`{"Name":"aergo1","RaftID":"aebe0b6ae1d8a39b","PeerID":"16Uiu2HAmGiJ2QgVAWHMUtzLKKNM5eFUJ3Ds3FN7nYJq1mHN5ZPj9","Addr":"/ip4/127.0.0.1/tcp/11001"}`,
+	}, nil)
+	fn_test(nil, nil)
+}
+
+func TestMissedSlots(t *testing.T) {
+	fn_test := func(schedule []string, prevSec, sec int64, expect map[string]uint64) {
+		require.Equal(t, expect, MissedSlots(schedule, prevSec*int64(time.Second)+1000, sec*int64(time.Second)+2000, time.Second))
+	}
+	schedule := []string{"a", "b", "c"}
+
+	// consecutive slots
+	fn_test(schedule, 10, 11, nil)
+	fn_test(schedule, 10, 10, nil)
+	fn_test(nil, 10, 20, nil)
+
+	// slot 11 of c is missed
+	fn_test(schedule, 10, 12, map[string]uint64{"c": 1})
+
+	// slots 4..11
+	fn_test(schedule, 3, 12, map[string]uint64{"b": 3, "c": 3, "a": 2})
+}
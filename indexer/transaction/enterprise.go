@@ -0,0 +1,98 @@
+package transaction
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	EnterpriseAppendAdmin   = "appendAdmin"
+	EnterpriseRemoveAdmin   = "removeAdmin"
+	EnterpriseSetConf       = "setConf"
+	EnterpriseAppendConf    = "appendConf"
+	EnterpriseRemoveConf    = "removeConf"
+	EnterpriseEnableConf    = "enableConf"
+	EnterpriseChangeCluster = "changeCluster"
+
+	EnterpriseAdmins = "ADMINS"
+)
+
+// EnterprisePayload is a decoded aergo.enterprise call
+type EnterprisePayload struct {
+	Name    string
+	Key     string
+	Values  []string
+	Enable  bool
+	Cluster *ClusterChange
+}
+
+// ClusterChange is the argument of changeCluster
+type ClusterChange struct {
+	Command string `json:"command"`
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	PeerID  string `json:"peerid"`
+	ID      string `json:"id"`
+}
+
+// UnmarshalEnterprisePayload decodes the payload of a tx to aergo.enterprise
+func UnmarshalEnterprisePayload(payloadSource []byte) (*EnterprisePayload, error) {
+	var raw struct {
+		Name string            `json:"Name"`
+		Args []json.RawMessage `json:"Args"`
+	}
+	if err := json.Unmarshal(payloadSource, &raw); err != nil {
+		return nil, err
+	}
+	payload := &EnterprisePayload{Name: raw.Name}
+
+	switch raw.Name {
+	case EnterpriseAppendAdmin, EnterpriseRemoveAdmin:
+		if len(raw.Args) != 1 {
+			return nil, fmt.Errorf("invalid arguments of %s", raw.Name)
+		}
+		payload.Key = EnterpriseAdmins
+		payload.Values = []string{unmarshalStringArg(raw.Args[0])}
+	case EnterpriseSetConf, EnterpriseAppendConf, EnterpriseRemoveConf:
+		if len(raw.Args) < 1 {
+			return nil, fmt.Errorf("invalid arguments of %s", raw.Name)
+		}
+		payload.Key = strings.ToUpper(unmarshalStringArg(raw.Args[0]))
+		for _, arg := range raw.Args[1:] {
+			payload.Values = append(payload.Values, unmarshalStringArg(arg))
+		}
+	case EnterpriseEnableConf:
+		if len(raw.Args) != 2 {
+			return nil, fmt.Errorf("invalid arguments of %s", raw.Name)
+		}
+		payload.Key = strings.ToUpper(unmarshalStringArg(raw.Args[0]))
+		if err := json.Unmarshal(raw.Args[1], &payload.Enable); err != nil {
+			payload.Enable, err = strconv.ParseBool(unmarshalStringArg(raw.Args[1]))
+			if err != nil {
+				return nil, fmt.Errorf("invalid arguments of %s", raw.Name)
+			}
+		}
+	case EnterpriseChangeCluster:
+		if len(raw.Args) != 1 {
+			return nil, fmt.Errorf("invalid arguments of %s", raw.Name)
+		}
+		payload.Cluster = new(ClusterChange)
+		if err := json.Unmarshal(raw.Args[0], payload.Cluster); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown enterprise call %s", raw.Name)
+	}
+	return payload, nil
+}
+
+// unmarshalStringArg returns the string of an argument, or the raw json when it is not a string
+func unmarshalStringArg(arg json.RawMessage) string {
+	var str string
+	if err := json.Unmarshal(arg, &str); err != nil {
+		return string(arg)
+	}
+	return str
+}
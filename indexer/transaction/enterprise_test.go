@@ -0,0 +1,46 @@
+package transaction
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalEnterprisePayload(t *testing.T) {
+	fn_test := func(payload string, expect *EnterprisePayload, expectErr bool) {
+		decoded, err := UnmarshalEnterprisePayload([]byte(payload))
+		if expectErr {
+			require.Error(t, err)
+			return
+		}
+		require.NoError(t, err)
+		require.Equal(t, expect, decoded)
+	}
+
+	fn_test(`{"Name":"appendAdmin","Args":["AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA"]}`, &EnterprisePayload{
+		Name:   EnterpriseAppendAdmin,
+		Key:    EnterpriseAdmins,
+		Values: []string{"AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA"},
+	}, false)
+	fn_test(`{"Name":"setConf","Args":["p2pwhite","16Uiu2HAmGiJ2QgVAWHMUtzLKKNM5eFUJ3Ds3FN7nYJq1mHN5ZPj9","16Uiu2HAkvJTHFuJXxr15rFEHsJWnyn1QvGatW2E9ED9Mvy4HWjVF"]}`, &EnterprisePayload{
+		Name:   EnterpriseSetConf,
+		Key:    "P2PWHITE",
+		Values: []string{"16Uiu2HAmGiJ2QgVAWHMUtzLKKNM5eFUJ3Ds3FN7nYJq1mHN5ZPj9", "16Uiu2HAkvJTHFuJXxr15rFEHsJWnyn1QvGatW2E9ED9Mvy4HWjVF"},
+	}, false)
+	fn_test(`{"Name":"enableConf","Args":["P2PWHITE",true]}`, &EnterprisePayload{
+		Name:   EnterpriseEnableConf,
+		Key:    "P2PWHITE",
+		Enable: true,
+	}, false)
+	fn_test(`{"Name":"changeCluster","Args":[{"command":"add","name":"aergo4","address":"/ip4/127.0.0.1/tcp/11004","peerid":"16Uiu2HAmGiJ2QgVAWHMUtzLKKNM5eFUJ3Ds3FN7nYJq1mHN5ZPj9"}]}`, &EnterprisePayload{
+		Name: EnterpriseChangeCluster,
+		Cluster: &ClusterChange{
+			Command: "add",
+			Name:    "aergo4",
+			Address: "/ip4/127.0.0.1/tcp/11004",
+			PeerID:  "16Uiu2HAmGiJ2QgVAWHMUtzLKKNM5eFUJ3Ds3FN7nYJq1mHN5ZPj9",
+		},
+	}, false)
+	fn_test(`{"Name":"enableConf","Args":["P2PWHITE"]}`, nil, true)
+	fn_test(`{"Name":"unknown","Args":[]}`, nil, true)
+}
@@ -0,0 +1,121 @@
+package transaction
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	MulticallCall  = "call"
+	MulticallPcall = "pcall"
+	MulticallSend  = "send"
+	MulticallLet   = "let"
+)
+
+// MulticallOp is a command of multicall script
+type MulticallOp struct {
+	Idx      uint64   `json:"idx" db:"idx"`
+	Depth    uint64   `json:"depth" db:"depth"` // nesting level of if and loop blocks
+	Command  string   `json:"command" db:"command"`
+	Contract string   `json:"contract" db:"contract"` // target of call, pcall and send. variables are resolved when assigned by let
+	Method   string   `json:"method" db:"method"`
+	Args     []string `json:"args" db:"args"`
+}
+
+// UnmarshalMulticall parses multicall script payload into ordered commands
+func UnmarshalMulticall(payloadSource []byte) ([]MulticallOp, error) {
+	var script [][]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(payloadSource))
+	decoder.UseNumber()
+	if err := decoder.Decode(&script); err != nil {
+		return nil, err
+	}
+
+	vars := make(map[string]string)
+	resolve := func(arg string) string {
+		if len(arg) > 2 && strings.HasPrefix(arg, "%") && strings.HasSuffix(arg, "%") {
+			if value, ok := vars[arg[1:len(arg)-1]]; ok {
+				return value
+			}
+		}
+		return arg
+	}
+
+	ops := make([]MulticallOp, 0, len(script))
+	var depth uint64
+	for i, line := range script {
+		if len(line) == 0 {
+			return nil, fmt.Errorf("empty command at %d", i)
+		}
+		command, ok := line[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid command at %d", i)
+		}
+		command = strings.ToLower(command)
+		args := make([]string, 0, len(line)-1)
+		for _, arg := range line[1:] {
+			args = append(args, DecodeArg(arg).Value)
+		}
+
+		// close blocks
+		switch command {
+		case "end", "loop", "else", "elif":
+			if depth > 0 {
+				depth--
+			}
+		}
+
+		op := MulticallOp{
+			Idx:     uint64(i),
+			Depth:   depth,
+			Command: command,
+			Args:    args,
+		}
+		switch command {
+		case MulticallCall, MulticallPcall:
+			if len(args) > 0 {
+				op.Contract = resolve(args[0])
+			}
+			if len(args) > 1 {
+				op.Method = args[1]
+				op.Args = args[2:]
+			} else {
+				op.Args = nil
+			}
+		case MulticallSend:
+			if len(args) > 0 {
+				op.Contract = resolve(args[0])
+				op.Args = args[1:]
+			}
+		case MulticallLet:
+			if len(args) == 2 {
+				vars[args[0]] = resolve(args[1])
+			}
+		}
+		ops = append(ops, op)
+
+		// open blocks
+		switch command {
+		case "if", "elif", "else", "for", "foreach", "forpair":
+			depth++
+		}
+	}
+	return ops, nil
+}
+
+// MulticallMethodOf returns the method of the calls to the contract.
+// events do not tell which call emitted them, so "" if the contract is called with different methods
+func MulticallMethodOf(ops []MulticallOp, contract string) string {
+	var method string
+	for _, op := range ops {
+		if (op.Command == MulticallCall || op.Command == MulticallPcall) && op.Contract == contract {
+			if method != "" && method != op.Method {
+				return ""
+			}
+			method = op.Method
+		}
+	}
+	return method
+}
@@ -0,0 +1,48 @@
+package transaction
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalMulticall(t *testing.T) {
+	token := "Amg5KQVkBcX1rR1nmKFPyZPnU8CeGWnZkqAiqp3v4fgSL6KmcCuF"
+	account := "AmLc7W3E9kGq9aFshbgBJdss1D8nwbMdjw3ErtJAXwjpBc69VkPA"
+	ops, err := UnmarshalMulticall([]byte(`[
+		["let","token","` + token + `"],
+		["for","n",1,3],
+		["call","%token%","transfer","` + account + `",{"_bignum":"100"}],
+		["if","%n%","=",2],
+		["send","` + account + `","1 aergo"],
+		["end"],
+		["loop"],
+		["pcall","` + account + `","approve"],
+		["return","%last result%"]
+	]`))
+	require.NoError(t, err)
+	require.Equal(t, []MulticallOp{
+		{Idx: 0, Depth: 0, Command: "let", Args: []string{"token", token}},
+		{Idx: 1, Depth: 0, Command: "for", Args: []string{"n", "1", "3"}},
+		{Idx: 2, Depth: 1, Command: "call", Contract: token, Method: "transfer", Args: []string{account, "100"}},
+		{Idx: 3, Depth: 1, Command: "if", Args: []string{"%n%", "=", "2"}},
+		{Idx: 4, Depth: 2, Command: "send", Contract: account, Args: []string{"1 aergo"}},
+		{Idx: 5, Depth: 1, Command: "end", Args: []string{}},
+		{Idx: 6, Depth: 0, Command: "loop", Args: []string{}},
+		{Idx: 7, Depth: 0, Command: "pcall", Contract: account, Method: "approve", Args: []string{}},
+		{Idx: 8, Depth: 0, Command: "return", Args: []string{"%last result%"}},
+	}, ops)
+
+	require.Equal(t, "transfer", MulticallMethodOf(ops, token))
+	require.Equal(t, "approve", MulticallMethodOf(ops, account))
+	require.Equal(t, "", MulticallMethodOf(ops, "AmMK3LZiR1oEf66xzXir7mA5SUVVHSinWUYmh5FwueoVmciH3CuJ"))
+	require.Equal(t, "", MulticallMethodOf([]MulticallOp{
+		{Idx: 0, Command: "call", Contract: token, Method: "transfer"},
+		{Idx: 1, Command: "call", Contract: token, Method: "burn"},
+	}, token))
+
+	_, err = UnmarshalMulticall([]byte(`{"Name":"transfer"}`))
+	require.Error(t, err)
+	_, err = UnmarshalMulticall([]byte(`[[]]`))
+	require.Error(t, err)
+}
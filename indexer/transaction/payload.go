@@ -1,6 +1,9 @@
 package transaction
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
 
 	"github.com/aergoio/aergo-indexer-2.0/types"
@@ -47,3 +50,21 @@ func GetCallName(tx *types.Tx) (string, error) {
 	}
 	return payload.Name, nil
 }
+
+// ContractCode extracts the code from a deploy payload, which is the code prefixed by its length followed by constructor arguments
+func ContractCode(payloadSource []byte) []byte {
+	if len(payloadSource) < 4 {
+		return payloadSource
+	}
+	headLen := int(binary.LittleEndian.Uint32(payloadSource[:4]))
+	if headLen < 4 || headLen > len(payloadSource) {
+		return payloadSource
+	}
+	return payloadSource[4:headLen]
+}
+
+// HashPayload returns the hex encoded sha256 hash of payload
+func HashPayload(payloadSource []byte) string {
+	hash := sha256.Sum256(payloadSource)
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,24 @@
+package transaction
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContractCode(t *testing.T) {
+	code := []byte("function hello() return 'world' end abi.register(hello)")
+	payload := make([]byte, 4, 4+len(code)+8)
+	binary.LittleEndian.PutUint32(payload, uint32(4+len(code)))
+	payload = append(payload, code...)
+	payload = append(payload, []byte(`[1,"a"]`)...)
+
+	require.Equal(t, code, ContractCode(payload))
+	require.Equal(t, HashPayload(code), HashPayload(ContractCode(payload)))
+	require.NotEqual(t, HashPayload(payload), HashPayload(ContractCode(payload)))
+
+	// not prefixed by length
+	require.Equal(t, []byte("abc"), ContractCode([]byte("abc")))
+	require.Equal(t, code, ContractCode(code))
+}
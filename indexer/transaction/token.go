@@ -1,9 +1,7 @@
 package transaction
 
 import (
-	"strings"
-
-	"github.com/aergoio/aergo-indexer-2.0/types"
+	"sort"
 )
 
 // TokenType
@@ -16,47 +14,141 @@ const (
 	TokenARC2 TokenType = "ARC2"
 )
 
-// MaybeTokenCreation runs a heuristic to determine if tx might be creating a token
-func MaybeTokenCreation(tx *types.Tx) TokenType {
-	txBody := tx.GetBody()
+// TokenConfidence is the minimum confidence to treat a contract as a token
+const TokenConfidence = 0.8
 
-	// We treat the payload (which is part bytecode, part ABI) as text
-	// and check that ALL the ARC1/2 keywords are included
-	if !(txBody.GetType() == types.TxType_DEPLOY && len(txBody.Payload) > 30) {
-		return TokenNone
-	}
+// weight of abi and probe of view calls in confidence
+const (
+	abiWeight   = 0.7
+	probeWeight = 0.3
+)
 
-	payload := string(txBody.GetPayload())
+// TokenStandard is a token standard or its extension, identified by the functions it requires
+type TokenStandard struct {
+	Interface string
+	Type      TokenType
+	Functions []string
+	Views     []string // functions which must be view
+	Probes    []string // view functions probed by query, which return a value with no argument or an account ( see ProbeArgs )
+}
+
+// TokenStandards are the core standards, checked in order
+var TokenStandards = []TokenStandard{
+	{Interface: "ARC2", Type: TokenARC2, Functions: []string{"name", "symbol", "totalSupply", "balanceOf", "ownerOf", "transfer"}, Views: []string{"name", "symbol", "totalSupply", "balanceOf", "ownerOf"}, Probes: []string{"name", "symbol", "totalSupply", "balanceOf"}},
+	{Interface: "ARC1", Type: TokenARC1, Functions: []string{"name", "symbol", "decimals", "totalSupply", "balanceOf", "transfer"}, Views: []string{"name", "symbol", "decimals", "totalSupply", "balanceOf"}, Probes: []string{"name", "symbol", "decimals", "totalSupply", "balanceOf"}},
+}
 
-	keywords := [...]string{"name", "balanceOf", "transfer", "symbol", "totalSupply"}
-	for _, keyword := range keywords {
-		if !strings.Contains(payload, keyword) {
-			return TokenNone
+// TokenExtensions are the extensions of core standards
+var TokenExtensions = []TokenStandard{
+	{Interface: "ARC1-burnable", Type: TokenARC1, Functions: []string{"burn"}},
+	{Interface: "ARC1-mintable", Type: TokenARC1, Functions: []string{"mint"}},
+	{Interface: "ARC1-pausable", Type: TokenARC1, Functions: []string{"pause", "unpause", "paused"}},
+	{Interface: "ARC1-blacklist", Type: TokenARC1, Functions: []string{"addToBlacklist", "removeFromBlacklist", "isOnBlacklist"}},
+	{Interface: "ARC1-all-approval", Type: TokenARC1, Functions: []string{"setApprovalForAll", "isApprovedForAll", "transferFrom"}},
+	{Interface: "ARC1-limited-approval", Type: TokenARC1, Functions: []string{"approve", "allowance", "transferFrom"}},
+	{Interface: "ARC2-burnable", Type: TokenARC2, Functions: []string{"burn"}},
+	{Interface: "ARC2-mintable", Type: TokenARC2, Functions: []string{"mint"}},
+	{Interface: "ARC2-pausable", Type: TokenARC2, Functions: []string{"pause", "unpause", "paused"}},
+	{Interface: "ARC2-blacklist", Type: TokenARC2, Functions: []string{"addToBlacklist", "removeFromBlacklist", "isOnBlacklist"}},
+	{Interface: "ARC2-approval", Type: TokenARC2, Functions: []string{"approve", "getApproved", "setApprovalForAll", "isApprovedForAll", "transferFrom"}},
+	{Interface: "ARC2-metadata", Type: TokenARC2, Functions: []string{"get_metadata"}},
+	{Interface: "ARC2-searchable", Type: TokenARC2, Functions: []string{"findToken"}},
+	{Interface: "ARC2-nonfungible", Type: TokenARC2, Functions: []string{"nextToken", "tokenFromUser"}},
+}
+
+// TokenDetection is the token standard detected from the abi of a contract
+type TokenDetection struct {
+	Type       TokenType
+	Interfaces []string // detected standards and extensions
+	Confidence float64  // 0 ~ 1
+	Probes     []string // view functions to be probed by query
+}
+
+// DetectTokenStandard classifies the contract by the functions of its abi, which maps function name to view
+// the confidence only counts the abi until the result of probe is applied
+func DetectTokenStandard(functions map[string]bool) *TokenDetection {
+	detection := &TokenDetection{Type: TokenNone}
+	var best *TokenStandard
+	var bestScore float64
+	for i, standard := range TokenStandards {
+		score := standardScore(standard, functions)
+		if score > bestScore {
+			best, bestScore = &TokenStandards[i], score
 		}
 	}
+	if best == nil {
+		return detection
+	}
 
-	include := true
-	keywords1 := [...]string{"decimals"}
-	for _, keyword := range keywords1 {
-		if !strings.Contains(payload, keyword) {
-			include = false
-			break
+	detection.Type = best.Type
+	detection.Confidence = bestScore * abiWeight
+	detection.Probes = best.Probes
+	if hasFunctions(*best, functions) {
+		detection.Interfaces = append(detection.Interfaces, best.Interface)
+	}
+	for _, extension := range TokenExtensions {
+		if extension.Type == best.Type && hasFunctions(extension, functions) {
+			detection.Interfaces = append(detection.Interfaces, extension.Interface)
 		}
 	}
-	if include {
-		return TokenARC1
+	sort.Strings(detection.Interfaces)
+	return detection
+}
+
+// ApplyProbe adds the result of querying view functions to the confidence
+func (d *TokenDetection) ApplyProbe(passed int) {
+	if len(d.Probes) == 0 {
+		return
 	}
+	d.Confidence += probeWeight * float64(passed) / float64(len(d.Probes))
+}
+
+// ProbeArgs returns the arguments to probe the view function. balanceOf is probed with the account, which returns a balance even if zero
+// ownerOf is not probed, since it returns nothing for an unknown token id
+func ProbeArgs(name string, account string) []string {
+	if name == "balanceOf" {
+		return []string{account}
+	}
+	return nil
+}
+
+// IsToken checks if the detection is confident enough
+func (d *TokenDetection) IsToken() bool {
+	return d != nil && d.Type != TokenNone && d.Confidence >= TokenConfidence
+}
+
+// standardScore returns the ratio of functions of the standard in abi. a function which should be view but is not counts less
+func standardScore(standard TokenStandard, functions map[string]bool) float64 {
+	var score float64
+	for _, name := range standard.Functions {
+		view, exist := functions[name]
+		if !exist {
+			continue
+		}
+		if !view && contains(standard.Views, name) {
+			score += 0.9
+		} else {
+			score += 1
+		}
+	}
+	return score / float64(len(standard.Functions))
+}
 
-	include = true
-	keywords2 := [...]string{"ownerOf"}
-	for _, keyword := range keywords2 {
-		if !strings.Contains(payload, keyword) {
-			include = false
-			break
+// hasFunctions checks if all functions of the standard are in abi
+func hasFunctions(standard TokenStandard, functions map[string]bool) bool {
+	for _, name := range standard.Functions {
+		if _, exist := functions[name]; !exist {
+			return false
 		}
 	}
-	if include {
-		return TokenARC2
+	return true
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
 	}
-	return TokenNone
+	return false
 }
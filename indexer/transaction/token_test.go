@@ -0,0 +1,52 @@
+package transaction
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectTokenStandard(t *testing.T) {
+	views := func(views []string, calls ...string) map[string]bool {
+		functions := make(map[string]bool)
+		for _, name := range views {
+			functions[name] = true
+		}
+		for _, name := range calls {
+			functions[name] = false
+		}
+		return functions
+	}
+	fn_test := func(functions map[string]bool, passed int, expectType TokenType, expectInterfaces []string, expectToken bool) {
+		detection := DetectTokenStandard(functions)
+		detection.ApplyProbe(passed)
+		require.Equal(t, expectType, detection.Type)
+		require.Equal(t, expectInterfaces, detection.Interfaces)
+		require.Equal(t, expectToken, detection.IsToken())
+	}
+
+	arc1 := []string{"name", "symbol", "decimals", "totalSupply", "balanceOf"}
+	arc2 := []string{"name", "symbol", "totalSupply", "balanceOf", "ownerOf"}
+
+	// arc1 with extensions
+	fn_test(views(append(arc1, "allowance", "paused"), "transfer", "approve", "transferFrom", "burn", "pause", "unpause"), 5,
+		TokenARC1, []string{"ARC1", "ARC1-burnable", "ARC1-limited-approval", "ARC1-pausable"}, true)
+	// arc1 registered without view
+	fn_test(views(nil, append(arc1, "transfer")...), 5, TokenARC1, []string{"ARC1"}, true)
+	// arc1 which view calls fail
+	fn_test(views(arc1, "transfer"), 0, TokenARC1, []string{"ARC1"}, false)
+	// arc2 with metadata
+	fn_test(views(append(arc2, "get_metadata"), "transfer", "mint"), 4, TokenARC2, []string{"ARC2", "ARC2-metadata", "ARC2-mintable"}, true)
+	// mentions keywords, but not a token
+	fn_test(views([]string{"name"}, "setName", "transferOwnership"), 1, TokenARC2, nil, false)
+	// no abi
+	fn_test(views(nil), 0, TokenNone, nil, false)
+}
+
+func TestProbeArgs(t *testing.T) {
+	require.Equal(t, []string{"AmhNNBNY7XFk4p5ym4CJf8nTcRTEHjWzAeXJfhP71244CjBCAQU3"}, ProbeArgs("balanceOf", "AmhNNBNY7XFk4p5ym4CJf8nTcRTEHjWzAeXJfhP71244CjBCAQU3"))
+	require.Nil(t, ProbeArgs("name", "AmhNNBNY7XFk4p5ym4CJf8nTcRTEHjWzAeXJfhP71244CjBCAQU3"))
+	for _, standard := range TokenStandards {
+		require.NotContains(t, standard.Probes, "ownerOf")
+	}
+}
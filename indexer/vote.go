@@ -0,0 +1,86 @@
+package indexer
+
+import (
+	"math/big"
+
+	"github.com/aergoio/aergo-indexer-2.0/indexer/client"
+	doc "github.com/aergoio/aergo-indexer-2.0/indexer/documents"
+	"github.com/aergoio/aergo-indexer-2.0/indexer/transaction"
+	"github.com/aergoio/aergo-indexer-2.0/types"
+)
+
+// MinerBpVotes stores a snapshot of the bp vote tally
+// votes are the current state of node, so the snapshot is taken only at the latest block
+func (ns *Indexer) MinerBpVotes(blockDoc *doc.EsBlock, MinerGRPC *client.AergoClientController) {
+	// catching up
+	if blockDoc.BlockNo < ns.getLastHeight() {
+		return
+	}
+	voteList, err := MinerGRPC.GetVotes(transaction.VoteBP, ns.bpVotesCount)
+	if err != nil {
+		ns.log.Warn().Err(err).Uint64("blockNo", blockDoc.BlockNo).Msg("Failed to get votes")
+		return
+	}
+
+	active := make(map[string]bool)
+	if bps, exist := ns.cache.getBps(); exist == true {
+		for _, bp := range bps {
+			active[bp] = true
+		}
+	}
+
+	for i, vote := range voteList.GetVotes() {
+		candidate := transaction.EncodeCandidate(vote.GetCandidate())
+		votes := big.NewInt(0).SetBytes(vote.GetAmount())
+		bpVotesDoc := doc.ConvBpVotes(blockDoc, candidate, votes, uint64(i+1), active[candidate])
+		ns.addBpVotes(bpVotesDoc)
+	}
+	ns.log.Info().Uint64("blockNo", blockDoc.BlockNo).Int("candidates", len(voteList.GetVotes())).Msg("bp votes snapshot")
+}
+
+// MinerBpChange records the active bp set and its schedule when either differs from the previous one
+// consensus info is the current state of node, so the bp set is read only at the latest block
+func (ns *Indexer) MinerBpChange(blockDoc *doc.EsBlock, consensusInfo *types.ConsensusInfo) {
+	// catching up, or failed to get
+	if consensusInfo == nil {
+		return
+	}
+	consensusBps := transaction.UnmarshalConsensusBps(consensusInfo.GetBps())
+	bps := transaction.ConsensusBpPeerIds(consensusBps)
+	if len(bps) == 0 {
+		return
+	}
+	schedule := transaction.ConsensusBpSchedule(consensusBps)
+
+	var prevBps, prevSchedule []string
+	if lastChange := ns.cache.getLastBpChange(); lastChange != nil {
+		prevBps, prevSchedule = lastChange.Bps, lastChange.Schedule
+	}
+	added, removed := transaction.DiffBps(prevBps, bps)
+	if len(added) > 0 || len(removed) > 0 || !transaction.SameSchedule(prevSchedule, schedule) {
+		bpChangeDoc := doc.ConvBpChange(blockDoc, bps, schedule, added, removed)
+		ns.addBpChange(bpChangeDoc)
+		ns.cache.applyBpChange(bpChangeDoc)
+		ns.log.Info().Uint64("blockNo", blockDoc.BlockNo).Strs("added", added).Strs("removed", removed).Msg("bp set changed")
+	}
+	ns.cache.storeBps(bps)
+}
+
+// initBpSchedules loads the changes of bp set, which schedule the slots of bp stats
+func (ns *Indexer) initBpSchedules() {
+	if err := ns.ScrollBpChange(func(bpChangeDoc *doc.EsBpChange) {
+		ns.cache.applyBpChange(bpChangeDoc)
+	}); err != nil {
+		ns.log.Error().Err(err).Str("func", "initBpSchedules").Msg("error while scroll bp change")
+	}
+}
+
+// MinerAccountVotes refreshes the bp voting of a voting tx sender
+func (ns *Indexer) MinerAccountVotes(txDoc *doc.EsTx, tx *types.Tx, MinerGRPC *client.AergoClientController) {
+	voteInfo, err := MinerGRPC.GetAccountVotes(tx.GetBody().GetAccount())
+	if err != nil {
+		ns.log.Warn().Err(err).Str("account", txDoc.Account).Msg("Failed to get account votes")
+		return
+	}
+	ns.addAccountVotes(doc.ConvAccountVotes(txDoc, txDoc.Account, voteInfo))
+}
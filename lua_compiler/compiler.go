@@ -2,19 +2,32 @@ package lua_compiler
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/mr-tron/base58"
 )
 
 const (
-	url = "https://luac.aergo.io/compile"
+	DefaultUrl = "https://luac.aergo.io/compile"
+
+	requestTimeout = 60 * time.Second
 )
 
+// Compiler compiles lua source code into the code deployed on chain
+type Compiler interface {
+	Compile(code string) ([]byte, error)
+	Version() string
+}
+
 func GetCode(url string) (code string, err error) {
 	// HTTP GET 요청 보내기
 	response, err := http.Get(url)
@@ -34,9 +47,30 @@ func GetCode(url string) (code string, err error) {
 	return content, nil
 }
 
+// CompileCode compiles code by the default luac web service
 func CompileCode(code string) ([]byte, error) {
+	return NewRemoteCompiler(DefaultUrl, "").Compile(code)
+}
+
+// RemoteCompiler compiles code by a luac web service
+type RemoteCompiler struct {
+	url     string
+	version string
+	client  *http.Client
+}
+
+// NewRemoteCompiler returns the compiler using the luac web service of url. version is the pinned compiler version of the service
+func NewRemoteCompiler(url, version string) *RemoteCompiler {
+	return &RemoteCompiler{
+		url:     url,
+		version: version,
+		client:  &http.Client{Timeout: requestTimeout},
+	}
+}
+
+func (c *RemoteCompiler) Compile(code string) ([]byte, error) {
 	data := []byte(base64.StdEncoding.EncodeToString([]byte(code)))
-	resp, err := http.Post(url, "text/plain", bytes.NewBuffer(data))
+	resp, err := c.client.Post(c.url, "text/plain", bytes.NewBuffer(data))
 	if err != nil {
 		return nil, fmt.Errorf("Error sending request: %v", err)
 	}
@@ -50,18 +84,112 @@ func CompileCode(code string) ([]byte, error) {
 	if len(retData) < 8 || retData[:8] != "result: " {
 		return nil, fmt.Errorf("Error in response: %v", retData)
 	}
+	return DecodeCode(retData[8:])
+}
 
-	// trim space - result:
-	byteCodeB58 := strings.TrimSpace(retData[8:])
-	byteCode, err := base58.Decode(byteCodeB58)
+func (c *RemoteCompiler) Version() string {
+	return versionOf("remote", c.url, c.version)
+}
+
+// LocalCompiler compiles code by a local aergoluac binary
+type LocalCompiler struct {
+	path    string
+	version string
+}
+
+// NewLocalCompiler returns the compiler running the aergoluac binary of path. version is the pinned compiler version of the binary
+func NewLocalCompiler(path, version string) *LocalCompiler {
+	return &LocalCompiler{
+		path:    path,
+		version: version,
+	}
+}
+
+func (c *LocalCompiler) Compile(code string) ([]byte, error) {
+	srcFile, err := os.CreateTemp("", "contract-*.lua")
 	if err != nil {
-		return nil, fmt.Errorf("Error decoding b58 byte code: %v", err)
+		return nil, err
+	}
+	defer os.Remove(srcFile.Name())
+	if _, err = srcFile.WriteString(code); err != nil {
+		srcFile.Close()
+		return nil, err
 	}
+	srcFile.Close()
 
-	// trim header and footer experimentally : Need more test
-	if len(byteCode) > 5 {
-		byteCode = byteCode[1 : len(byteCode)-4]
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(c.path, "--payload", srcFile.Name())
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err = cmd.Run(); err != nil {
+		return nil, fmt.Errorf("Error running aergoluac: %v %s", err, strings.TrimSpace(stderr.String()))
 	}
+	return DecodeCode(stdout.String())
+}
+
+func (c *LocalCompiler) Version() string {
+	return versionOf("local", c.path, c.version)
+}
 
-	return byteCode, nil
+// FakeCompiler is a deterministic compiler for tests, which returns registered codes or a hash of source
+type FakeCompiler struct {
+	version string
+	codes   map[string][]byte
+}
+
+// NewFakeCompiler returns the fake compiler. codes maps source code to the compiled code
+func NewFakeCompiler(version string, codes map[string][]byte) *FakeCompiler {
+	fake := &FakeCompiler{
+		version: version,
+		codes:   make(map[string][]byte),
+	}
+	for code, compiled := range codes {
+		fake.codes[NormalizeSource(code)] = compiled
+	}
+	return fake
+}
+
+func (c *FakeCompiler) Compile(code string) ([]byte, error) {
+	if compiled, ok := c.codes[NormalizeSource(code)]; ok {
+		return compiled, nil
+	}
+	return FakeByteCode(code), nil
+}
+
+func (c *FakeCompiler) Version() string {
+	return versionOf("fake", "", c.version)
+}
+
+// FakeByteCode returns the bytecode which the fake compiler makes from an unregistered source code
+func FakeByteCode(code string) []byte {
+	hash := sha256.Sum256([]byte(NormalizeSource(code)))
+	return append(append([]byte{}, byteCodeSignature...), hash[:]...)
+}
+
+// DecodeCode decodes the base58check encoded compile result into lua code (bytecode and abi)
+func DecodeCode(encoded string) ([]byte, error) {
+	raw, err := base58.Decode(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding b58 byte code: %v", err)
+	}
+	// version(1) + code + checksum(4)
+	if len(raw) < 5 {
+		return nil, errors.New("Error decoding b58 byte code: too short")
+	}
+	code, checksum := raw[:len(raw)-4], raw[len(raw)-4:]
+	first := sha256.Sum256(code)
+	second := sha256.Sum256(first[:])
+	if !bytes.Equal(second[:4], checksum) {
+		return nil, errors.New("Error decoding b58 byte code: invalid checksum")
+	}
+	return code[1:], nil
+}
+
+func versionOf(kind, target, version string) string {
+	if target != "" {
+		kind = kind + ":" + target
+	}
+	if version != "" {
+		kind = kind + "@" + version
+	}
+	return kind
 }
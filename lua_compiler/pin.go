@@ -0,0 +1,127 @@
+package lua_compiler
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Compilers pins a compiler per chain hardfork version, so that a contract is compiled by the compiler of the chain version it was deployed on
+type Compilers struct {
+	pins      []pin // sorted by version
+	sourceDir string
+}
+
+type pin struct {
+	version  int32
+	compiler Compiler
+}
+
+// NewCompilers returns an empty set of compilers
+func NewCompilers() *Compilers {
+	return &Compilers{}
+}
+
+// Pin sets the compiler used from the hardfork version
+func (c *Compilers) Pin(version int32, compiler Compiler) {
+	for i := range c.pins {
+		if c.pins[i].version == version {
+			c.pins[i].compiler = compiler
+			return
+		}
+	}
+	c.pins = append(c.pins, pin{version: version, compiler: compiler})
+	sort.SliceStable(c.pins, func(i, j int) bool {
+		return c.pins[i].version < c.pins[j].version
+	})
+}
+
+// Pinned checks if any compiler is pinned
+func (c *Compilers) Pinned() bool {
+	return len(c.pins) > 0
+}
+
+// SetSourceDir sets the directory which mirrors source codes by host and path of url. if set, source codes are read from it instead of network
+func (c *Compilers) SetSourceDir(dir string) {
+	c.sourceDir = dir
+}
+
+// CompilerOf returns the compiler pinned for the hardfork version, or nil if no compiler is pinned
+func (c *Compilers) CompilerOf(version int32) Compiler {
+	var compiler Compiler
+	for _, p := range c.pins {
+		if p.version > version {
+			break
+		}
+		compiler = p.compiler
+	}
+	return compiler
+}
+
+// GetCode returns the source code of url, from the source directory if set
+func (c *Compilers) GetCode(codeUrl string) (string, error) {
+	if c.sourceDir == "" {
+		return GetCode(codeUrl)
+	}
+	u, err := url.Parse(codeUrl)
+	if err != nil {
+		return "", err
+	}
+	code, err := os.ReadFile(filepath.Join(c.sourceDir, u.Host, filepath.FromSlash(u.Path)))
+	if err != nil {
+		return "", err
+	}
+	return string(code), nil
+}
+
+// ParsePin parses the compiler pinned to a hardfork in the form of <version>=<compiler>
+// compiler is one of remote:<url>, local:<aergoluac path> or fake, optionally followed by #<compiler version>
+func ParsePin(spec string) (version int32, compiler Compiler, err error) {
+	versionStr, compilerStr, ok := strings.Cut(spec, "=")
+	if !ok {
+		return 0, nil, fmt.Errorf("invalid compiler: %s", spec)
+	}
+	if version, err = parseVersion(versionStr); err != nil {
+		return 0, nil, err
+	}
+	if compiler, err = ParseCompiler(compilerStr); err != nil {
+		return 0, nil, err
+	}
+	return version, compiler, nil
+}
+
+// ParseCompiler parses the compiler in the form of remote:<url>, local:<aergoluac path> or fake, optionally followed by #<compiler version>.
+// the version is separated by '#', since an url or a path may contain '@'
+func ParseCompiler(spec string) (Compiler, error) {
+	spec, version, _ := strings.Cut(strings.TrimSpace(spec), "#")
+	kind, target, _ := strings.Cut(spec, ":")
+	switch kind {
+	case "remote":
+		if target == "" {
+			target = DefaultUrl
+		}
+		return NewRemoteCompiler(target, version), nil
+	case "local":
+		if target == "" {
+			target = "aergoluac"
+		}
+		return NewLocalCompiler(target, version), nil
+	case "fake":
+		return NewFakeCompiler(version, nil), nil
+	default:
+		return nil, fmt.Errorf("invalid compiler: %s", spec)
+	}
+}
+
+func parseVersion(s string) (int32, error) {
+	s = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "v")
+	version, err := strconv.ParseInt(s, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid hardfork version: %s", s)
+	}
+	return int32(version), nil
+}
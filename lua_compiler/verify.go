@@ -0,0 +1,96 @@
+package lua_compiler
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aergoio/aergo-indexer-2.0/indexer/transaction"
+)
+
+// luajit bytecode signature
+var byteCodeSignature = []byte("\x1bLJ")
+
+// Verification is the result of comparing a source code with the deployed contract
+type Verification struct {
+	Verified        bool
+	CompilerVersion string
+	Reason          string // mismatch reason, empty if verified
+}
+
+// SplitLuaCode splits lua code, which is the bytecode prefixed by its length followed by abi
+func SplitLuaCode(code []byte) (byteCode, abi []byte, ok bool) {
+	if len(code) < 4 {
+		return code, nil, false
+	}
+	byteCodeLen := int(binary.LittleEndian.Uint32(code[:4]))
+	if byteCodeLen == 0 || 4+byteCodeLen > len(code) {
+		return code, nil, false
+	}
+	return code[4 : 4+byteCodeLen], code[4+byteCodeLen:], true
+}
+
+// IsByteCode checks if the code is luajit bytecode
+func IsByteCode(code []byte) bool {
+	return bytes.HasPrefix(code, byteCodeSignature)
+}
+
+// NormalizeSource removes differences of line endings and trailing spaces in source code
+func NormalizeSource(code string) string {
+	lines := strings.Split(strings.ReplaceAll(code, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// Verify compiles the source code and compares the result with the deploy payload
+// a payload which contains the source code itself (since hardfork v4) is compared without compile
+func Verify(compiler Compiler, code string, payload []byte) *Verification {
+	deployed := transaction.ContractCode(payload)
+	deployedByteCode, deployedAbi, ok := SplitLuaCode(deployed)
+	if !ok || !IsByteCode(deployedByteCode) {
+		if NormalizeSource(string(deployed)) != NormalizeSource(code) {
+			return &Verification{Reason: "source code differs from deployed source"}
+		}
+		return &Verification{Verified: true}
+	}
+
+	if compiler == nil {
+		return &Verification{Reason: "no compiler"}
+	}
+	verification := &Verification{CompilerVersion: compiler.Version()}
+	compiled, err := compiler.Compile(code)
+	if err != nil {
+		verification.Reason = fmt.Sprintf("compile failed: %v", err)
+		return verification
+	}
+	compiledByteCode, compiledAbi, ok := SplitLuaCode(compiled)
+	if !ok {
+		compiledByteCode, compiledAbi = compiled, nil
+	}
+
+	if !bytes.Equal(compiledByteCode, deployedByteCode) {
+		verification.Reason = fmt.Sprintf("bytecode differs (compiled %d bytes, deployed %d bytes)", len(compiledByteCode), len(deployedByteCode))
+		return verification
+	}
+	if len(compiledAbi) > 0 && !equalAbi(compiledAbi, deployedAbi) {
+		verification.Reason = "abi differs"
+		return verification
+	}
+	verification.Verified = true
+	return verification
+}
+
+// equalAbi compares abi json regardless of formatting
+func equalAbi(a, b []byte) bool {
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return bytes.Equal(bytes.TrimSpace(a), bytes.TrimSpace(b))
+	}
+	ja, _ := json.Marshal(va)
+	jb, _ := json.Marshal(vb)
+	return bytes.Equal(ja, jb)
+}
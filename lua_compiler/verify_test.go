@@ -0,0 +1,70 @@
+package lua_compiler
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type stubCompiler struct {
+	code []byte
+	err  error
+}
+
+func (c *stubCompiler) Compile(code string) ([]byte, error) {
+	return c.code, c.err
+}
+
+func (c *stubCompiler) Version() string {
+	return "stub"
+}
+
+func luaCode(byteCode, abi []byte) []byte {
+	code := make([]byte, 4+len(byteCode)+len(abi))
+	binary.LittleEndian.PutUint32(code, uint32(len(byteCode)))
+	copy(code[4:], byteCode)
+	copy(code[4+len(byteCode):], abi)
+	return code
+}
+
+func deployPayload(code, args []byte) []byte {
+	payload := make([]byte, 4+len(code)+len(args))
+	binary.LittleEndian.PutUint32(payload, uint32(4+len(code)))
+	copy(payload[4:], code)
+	copy(payload[4+len(code):], args)
+	return payload
+}
+
+func TestVerify(t *testing.T) {
+	source := readLuaCode("contract_hello.lua")
+	byteCode := []byte("\x1bLJ\x02hello")
+	abi := []byte(`{"language":"lua","functions":[{"name":"hello"}]}`)
+	payload := deployPayload(luaCode(byteCode, abi), []byte(`["arg"]`))
+
+	fn_test := func(compiler Compiler, code string, payload []byte, expect *Verification) {
+		require.Equal(t, expect, Verify(compiler, code, payload))
+	}
+
+	// same bytecode and abi
+	fn_test(&stubCompiler{code: luaCode(byteCode, []byte(`{"functions": [{"name": "hello"}], "language": "lua"}`))}, source, payload, &Verification{Verified: true, CompilerVersion: "stub"})
+	// compiler returns bytecode only
+	fn_test(&stubCompiler{code: byteCode}, source, payload, &Verification{Verified: true, CompilerVersion: "stub"})
+	// bytecode differs
+	fn_test(&stubCompiler{code: luaCode([]byte("\x1bLJ\x02hi"), abi)}, source, payload, &Verification{CompilerVersion: "stub", Reason: "bytecode differs (compiled 6 bytes, deployed 9 bytes)"})
+	// abi differs
+	fn_test(&stubCompiler{code: luaCode(byteCode, []byte(`{"language":"lua","functions":[]}`))}, source, payload, &Verification{CompilerVersion: "stub", Reason: "abi differs"})
+	// compile error
+	fn_test(&stubCompiler{err: errors.New("syntax error")}, source, payload, &Verification{CompilerVersion: "stub", Reason: "compile failed: syntax error"})
+	// no compiler
+	fn_test(nil, source, payload, &Verification{Reason: "no compiler"})
+
+	// deployed source code
+	fn_test(nil, source, deployPayload([]byte(source+"\r\n\r\n"), nil), &Verification{Verified: true})
+	fn_test(nil, source, deployPayload([]byte("function hi() end"), nil), &Verification{Reason: "source code differs from deployed source"})
+}
+
+func TestNormalizeSource(t *testing.T) {
+	require.Equal(t, "a\n\nb", NormalizeSource("\r\na  \r\n\r\nb\t\n"))
+}
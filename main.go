@@ -5,9 +5,11 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/aergoio/aergo-indexer-2.0/indexer"
 	doc "github.com/aergoio/aergo-indexer-2.0/indexer/documents"
+	"github.com/aergoio/aergo-indexer-2.0/indexer/metadata"
 	"github.com/aergoio/aergo-lib/log"
 	"github.com/spf13/cobra"
 )
@@ -19,6 +21,12 @@ var (
 		Long:  "Aergo Metadata Indexer",
 		Run:   rootRun,
 	}
+	backfillRewardCmd = &cobra.Command{
+		Use:   "backfill_reward",
+		Short: "Backfill block rewards",
+		Long:  "Recompute rewards of indexed blocks from chain params and balances of reward accounts, in the range of --from and --to",
+		Run:   backfillRewardRun,
+	}
 
 	runMode    string
 	checkMode  bool
@@ -39,6 +47,19 @@ var (
 	contractVerifyAddress   string
 	tokenVerifyWhitelist    []string
 	contractVerifyWhitelist []string
+	bpVotesInterval         uint64
+	bpVotesCount            uint32
+	luacCompilers           []string
+	hardforks               []string
+	luacSourceDir           string
+	nftMetadata             bool
+	nftMetadataConfig       metadata.Config
+	holdersRecountInterval  uint64
+	topHolders              int
+	blockInterval           time.Duration
+	observeReward           bool
+	eventIndexConfig        string
+	contractProfiles        string
 
 	logger *log.Logger
 )
@@ -59,11 +80,30 @@ func init() {
 	fs.Uint64Var(&to, "to", 0, "stop syncing at this block number")
 
 	fs.StringVar(&cccvNftServerType, "cccv", "", "indexing cccv nft by network type.(mainnet,testnet)")
+	fs.MarkDeprecated("cccv", "use --contract_profiles profiles/<network>.json instead")
+	fs.StringVar(&contractProfiles, "contract_profiles", "", "json config file of token contracts queried in a non-standard way")
 	fs.StringVarP(&tokenVerifyAddress, "token", "t", "", "address for query verified token")
 	fs.StringVarP(&contractVerifyAddress, "contract", "c", "", "address for query contract code")
 	fs.StringSliceVarP(&balanceWhitelist, "balance_whitelist", "W", []string{}, "whitelist for update account balance")
 	fs.StringArrayVar(&tokenVerifyWhitelist, "token_whitelist", []string{}, "whitelist for update verified token")
 	fs.StringArrayVar(&contractVerifyWhitelist, "contract_whitelist", []string{}, "whitelist for update verified contract")
+	fs.Uint64Var(&bpVotesInterval, "bp_votes_interval", 3600, "store bp votes snapshot every this number of blocks (0 to disable)")
+	fs.Uint32Var(&bpVotesCount, "bp_votes_count", 100, "number of bp candidates in a bp votes snapshot")
+	fs.Uint64Var(&holdersRecountInterval, "holders_recount_interval", 86400, "recount holders of all tokens every this number of blocks (0 to disable)")
+	fs.IntVar(&topHolders, "top_holders", doc.DefaultTopHolders, "number of top holders stored in a token")
+	fs.DurationVar(&blockInterval, "block_interval", time.Second, "block interval of the chain to detect slots missed by bps")
+	fs.BoolVar(&observeReward, "observe_reward", false, "observe block rewards from balance changes of reward accounts while syncing")
+	fs.StringArrayVar(&luacCompilers, "luac", []string{}, "lua compiler pinned from a hardfork version (<version>=remote:<url>|local:<aergoluac path>|fake[#<compiler version>]). no compiler by default, so contracts deployed as bytecode are not verified")
+	fs.StringArrayVar(&hardforks, "hardfork", []string{}, "block number where a hardfork version is activated (<version>=<block number>)")
+	fs.StringVar(&luacSourceDir, "luac_source_dir", "", "directory which mirrors contract source codes by host and path of url, to verify contracts offline")
+	fs.StringVar(&eventIndexConfig, "event_index_config", "", "json config file which declares custom indices populated with events")
+	fs.BoolVar(&nftMetadata, "nft_metadata", false, "fetch off-chain metadata of nft token uri")
+	fs.StringVar(&nftMetadataConfig.IpfsGateway, "ipfs_gateway", metadata.DefaultIpfsGateway, "ipfs gateway to fetch ipfs:// token uri")
+	fs.Int64Var(&nftMetadataConfig.MaxSize, "nft_metadata_max_size", metadata.DefaultMaxSize, "max size of nft metadata in bytes")
+	fs.DurationVar(&nftMetadataConfig.Timeout, "nft_metadata_timeout", metadata.DefaultTimeout, "timeout to fetch nft metadata")
+	fs.DurationVar(&nftMetadataConfig.Refresh, "nft_metadata_refresh", metadata.DefaultRefresh, "interval to re-fetch nft metadata")
+
+	rootCmd.AddCommand(backfillRewardCmd)
 }
 
 func main() {
@@ -79,20 +119,7 @@ func rootRun(cmd *cobra.Command, args []string) {
 	doc.InitEsMappings(cluster)
 
 	// init indexer
-	indx, err := indexer.NewIndexer(
-		indexer.SetServerAddr(getServerAddress()),
-		indexer.SetDBAddr(dbURL),
-		indexer.SetPrefix(prefix),
-		indexer.SetNetworkTypeForCccv(cccvNftServerType),
-		indexer.SetRunMode(getRunMode()),
-		indexer.SetFix(fix),
-		indexer.SetLogger(logger),
-		indexer.SetWhiteListAddresses(balanceWhitelist),
-		indexer.SetTokenVerifyAddress(tokenVerifyAddress),
-		indexer.SetContractVerifyAddress(contractVerifyAddress),
-		indexer.SetTokenVerifyWhitelist(tokenVerifyWhitelist),
-		indexer.SetContractVerifyWhitelist(contractVerifyWhitelist),
-	)
+	indx, err := indexer.NewIndexer(indexerOptions()...)
 	if err != nil {
 		logger.Warn().Err(err).Msg("Could not start indexer")
 		return
@@ -112,6 +139,51 @@ func rootRun(cmd *cobra.Command, args []string) {
 	<-interrupt.C
 }
 
+func backfillRewardRun(cmd *cobra.Command, args []string) {
+	logger = log.NewLogger("indexer")
+	logger.Info().Uint64("from", from).Uint64("to", to).Msg("Backfilling block rewards ...")
+
+	doc.InitEsMappings(cluster)
+
+	indx, err := indexer.NewIndexer(indexerOptions()...)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Could not start indexer")
+		return
+	}
+	if err := indx.BackfillRewards(from, to); err != nil {
+		logger.Error().Err(err).Msg("Failed to backfill block rewards")
+	}
+}
+
+func indexerOptions() []indexer.IndexerOptionFunc {
+	return []indexer.IndexerOptionFunc{
+		indexer.SetServerAddr(getServerAddress()),
+		indexer.SetDBAddr(dbURL),
+		indexer.SetPrefix(prefix),
+		indexer.SetNetworkTypeForCccv(cccvNftServerType),
+		indexer.SetContractProfiles(contractProfiles),
+		indexer.SetRunMode(getRunMode()),
+		indexer.SetFix(fix),
+		indexer.SetLogger(logger),
+		indexer.SetWhiteListAddresses(balanceWhitelist),
+		indexer.SetTokenVerifyAddress(tokenVerifyAddress),
+		indexer.SetContractVerifyAddress(contractVerifyAddress),
+		indexer.SetTokenVerifyWhitelist(tokenVerifyWhitelist),
+		indexer.SetContractVerifyWhitelist(contractVerifyWhitelist),
+		indexer.SetBpVotesInterval(bpVotesInterval),
+		indexer.SetBpVotesCount(bpVotesCount),
+		indexer.SetHoldersRecountInterval(holdersRecountInterval),
+		indexer.SetTopHolders(topHolders),
+		indexer.SetBlockInterval(blockInterval),
+		indexer.SetObserveReward(observeReward),
+		indexer.SetCompilers(luacCompilers),
+		indexer.SetHardforks(hardforks),
+		indexer.SetSourceDir(luacSourceDir),
+		indexer.SetNftMetadata(nftMetadata, nftMetadataConfig),
+		indexer.SetEventIndexConfig(eventIndexConfig),
+	}
+}
+
 func getServerAddress() string {
 	if len(aergoAddress) > 0 {
 		return aergoAddress